@@ -36,5 +36,9 @@ type Application struct {
 	GoogleOauthService services.OAuthService
 	ProjectService     services.ProjectService
 	ChipService        services.ChipService
+	ClassroomService   services.ClassroomService
+	GradingService     services.GradingService
+	ProgressService    services.ProgressService
+	ApiTokenService    services.ApiTokenService
 	Bundle             *i18n.Bundle
 }
@@ -9,6 +9,8 @@ import (
 	"log/slog"
 	"maps"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/a-h/templ"
@@ -176,3 +178,31 @@ func (app *Application) ReadJSON(w http.ResponseWriter, r *http.Request, dst any
 	}
 	return nil
 }
+
+// ETagHeader returns the headers holding the entity tag of a resource at the given version.
+func (app *Application) ETagHeader(version int32) http.Header {
+	return http.Header{"Etag": []string{strconv.Quote(strconv.FormatInt(int64(version), 10))}}
+}
+
+// ReadIfMatch returns the version in the If-Match header of the request. It is
+// nil if the header is missing or "*", in which case any version matches. A weak
+// tag is read like a strong one, as proxies compressing the response weaken the
+// ETag the client got.
+func (app *Application) ReadIfMatch(r *http.Request) (*int32, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return nil, nil
+	}
+
+	tag, err := strconv.Unquote(strings.TrimPrefix(ifMatch, "W/"))
+	if err != nil {
+		return nil, errors.New("If-Match header must be a quoted version")
+	}
+	version, err := strconv.ParseInt(tag, 10, 32)
+	if err != nil {
+		return nil, errors.New("If-Match header must be a quoted version")
+	}
+
+	v := int32(version)
+	return &v, nil
+}
@@ -0,0 +1,76 @@
+package application
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadIfMatch(t *testing.T) {
+	version := int32(3)
+
+	tests := []struct {
+		name          string
+		ifMatch       string
+		expected      *int32
+		expectedError string
+	}{
+		{
+			name: "Missing",
+		},
+		{
+			name:    "Any version",
+			ifMatch: "*",
+		},
+		{
+			name:     "Strong tag",
+			ifMatch:  `"3"`,
+			expected: &version,
+		},
+		{
+			name:     "Weak tag",
+			ifMatch:  `W/"3"`,
+			expected: &version,
+		},
+		{
+			name:          "Unquoted version",
+			ifMatch:       "3",
+			expectedError: "If-Match header must be a quoted version",
+		},
+		{
+			name:          "Not a version",
+			ifMatch:       `"abc"`,
+			expectedError: "If-Match header must be a quoted version",
+		},
+		{
+			name:          "Version out of range",
+			ifMatch:       `"2147483648"`,
+			expectedError: "If-Match header must be a quoted version",
+		},
+		{
+			name:          "Several tags",
+			ifMatch:       `"3", "4"`,
+			expectedError: "If-Match header must be a quoted version",
+		},
+	}
+
+	app := &Application{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/", nil)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			version, err := app.ReadIfMatch(r)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package chiphandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// HandleDiffChipRevisions returns the line diff between the revisions given by
+// the from and to query parameters. A missing parameter, or "current", stands
+// for the current content of the chip.
+func (h *Handlers) HandleDiffChipRevisions(w http.ResponseWriter, r *http.Request) {
+	projectId, err := strconv.ParseInt(r.PathValue("projectId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+	chipId, err := strconv.ParseInt(r.PathValue("chipId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid chip id")
+		return
+	}
+
+	parseRevisionId := func(key string) (int32, bool) {
+		value := r.URL.Query().Get(key)
+		if value == "" || value == "current" {
+			return 0, true
+		}
+		revisionId, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || revisionId <= 0 {
+			return 0, false
+		}
+		return int32(revisionId), true
+	}
+
+	fromId, ok := parseRevisionId("from")
+	if !ok {
+		h.Application.WriteJSONBadRequestError(w, r, "from must be a revision id or current")
+		return
+	}
+	toId, ok := parseRevisionId("to")
+	if !ok {
+		h.Application.WriteJSONBadRequestError(w, r, "to must be a revision id or current")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	diff, err := h.Application.ChipService.DiffChipRevisions(fromId, toId, int32(chipId), int32(projectId), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrChipNotFound) || errors.Is(err, services.ErrChipRevisionNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		if errors.Is(err, services.ErrChipDiffTooLarge) {
+			h.Application.WriteJSONError(w, r, http.StatusUnprocessableEntity, "the chips are too long to diff")
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, diff, nil)
+	if err != nil {
+		h.Application.ServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,63 @@
+package chiphandlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// HandleDownloadVerilog responds with a zip of the Verilog modules of every
+// chip of the project, one .v file per module.
+func (h *Handlers) HandleDownloadVerilog(w http.ResponseWriter, r *http.Request) {
+	projectId, err := strconv.ParseInt(r.PathValue("projectId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	modules, err := h.Application.ChipService.GetProjectVerilog(int32(projectId), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		var hdlErr *services.HdlError
+		if errors.As(err, &hdlErr) {
+			h.Application.WriteJSONError(w, r, http.StatusUnprocessableEntity, hdlErr.Error())
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range slices.Sorted(maps.Keys(modules)) {
+		f, err := zw.Create(name + ".v")
+		if err != nil {
+			h.Application.ServerError(w, r, err)
+			return
+		}
+		if _, err := f.Write([]byte(modules[name])); err != nil {
+			h.Application.ServerError(w, r, err)
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="verilog.zip"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
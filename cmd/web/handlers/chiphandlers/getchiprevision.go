@@ -0,0 +1,45 @@
+package chiphandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+func (h *Handlers) HandleGetChipRevision(w http.ResponseWriter, r *http.Request) {
+	projectId, err := strconv.ParseInt(r.PathValue("projectId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+	chipId, err := strconv.ParseInt(r.PathValue("chipId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid chip id")
+		return
+	}
+	revisionId, err := strconv.ParseInt(r.PathValue("revisionId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid revision id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	revision, err := h.Application.ChipService.GetChipRevision(int32(revisionId), int32(chipId), int32(projectId), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrChipNotFound) || errors.Is(err, services.ErrChipRevisionNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, revision, nil)
+	if err != nil {
+		h.Application.ServerError(w, r, err)
+		return
+	}
+}
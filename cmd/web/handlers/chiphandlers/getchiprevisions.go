@@ -0,0 +1,41 @@
+package chiphandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// HandleGetChipRevisions lists the saved revisions of the chip, the newest first.
+func (h *Handlers) HandleGetChipRevisions(w http.ResponseWriter, r *http.Request) {
+	projectId, err := strconv.ParseInt(r.PathValue("projectId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+	chipId, err := strconv.ParseInt(r.PathValue("chipId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid chip id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	revisions, err := h.Application.ChipService.GetChipRevisions(int32(chipId), int32(projectId), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrChipNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, revisions, nil)
+	if err != nil {
+		h.Application.ServerError(w, r, err)
+		return
+	}
+}
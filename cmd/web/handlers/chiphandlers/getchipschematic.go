@@ -0,0 +1,70 @@
+package chiphandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// HandleGetChipSchematic returns the parts and wires of the chip as a JSON
+// schematic, or as a Graphviz DOT digraph with format=dot. The depth query
+// parameter sets how many levels of custom chips are expanded, 0 by default.
+func (h *Handlers) HandleGetChipSchematic(w http.ResponseWriter, r *http.Request) {
+	projectId, err := strconv.ParseInt(r.PathValue("projectId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+	chipId, err := strconv.ParseInt(r.PathValue("chipId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid chip id")
+		return
+	}
+
+	depth := 0
+	if depthStr := r.URL.Query().Get("depth"); depthStr != "" {
+		depth, err = strconv.Atoi(depthStr)
+		if err != nil || depth < 0 {
+			h.Application.WriteJSONBadRequestError(w, r, "depth must be a non-negative integer")
+			return
+		}
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "dot" {
+		h.Application.WriteJSONBadRequestError(w, r, "format must be json or dot")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	chipSchematic, err := h.Application.ChipService.GetChipSchematic(int32(chipId), int32(projectId), userId, depth)
+	if err != nil {
+		if errors.Is(err, services.ErrChipNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		var hdlErr *services.HdlError
+		if errors.As(err, &hdlErr) {
+			h.Application.WriteJSONError(w, r, http.StatusUnprocessableEntity, hdlErr.Error())
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	if format == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(chipSchematic.DOT()))
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, chipSchematic, nil)
+	if err != nil {
+		h.Application.ServerError(w, r, err)
+		return
+	}
+}
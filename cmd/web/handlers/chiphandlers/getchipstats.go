@@ -0,0 +1,45 @@
+package chiphandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+func (h *Handlers) HandleGetChipStats(w http.ResponseWriter, r *http.Request) {
+	projectId, err := strconv.ParseInt(r.PathValue("projectId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+	chipId, err := strconv.ParseInt(r.PathValue("chipId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid chip id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	stats, err := h.Application.ChipService.GetChipStats(int32(chipId), int32(projectId), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrChipNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		var hdlErr *services.HdlError
+		if errors.As(err, &hdlErr) {
+			h.Application.WriteJSONError(w, r, http.StatusUnprocessableEntity, hdlErr.Error())
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, stats, nil)
+	if err != nil {
+		h.Application.ServerError(w, r, err)
+		return
+	}
+}
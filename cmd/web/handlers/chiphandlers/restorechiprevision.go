@@ -0,0 +1,60 @@
+package chiphandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// HandleRestoreChipRevision sets the chip back to the revision and responds
+// with the updated chip.
+func (h *Handlers) HandleRestoreChipRevision(w http.ResponseWriter, r *http.Request) {
+	projectId, err := strconv.ParseInt(r.PathValue("projectId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+	chipId, err := strconv.ParseInt(r.PathValue("chipId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid chip id")
+		return
+	}
+	revisionId, err := strconv.ParseInt(r.PathValue("revisionId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid revision id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	chip, err := h.Application.ChipService.RestoreChipRevision(int32(revisionId), int32(chipId), int32(projectId), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrChipNotFound) || errors.Is(err, services.ErrChipRevisionNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		if errors.Is(err, models.ErrChipNameTaken) {
+			h.Application.WriteJSONBadRequestError(w, r, "chip name is already taken")
+			return
+		}
+		var conflictErr *services.ChipVersionConflictError
+		if errors.As(err, &conflictErr) {
+			err = h.Application.WriteJSON(w, http.StatusConflict, conflictErr.Chip, h.Application.ETagHeader(conflictErr.Chip.Version))
+			if err != nil {
+				h.Application.ServerError(w, r, err)
+			}
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, chip, h.Application.ETagHeader(chip.Version))
+	if err != nil {
+		h.Application.ServerError(w, r, err)
+		return
+	}
+}
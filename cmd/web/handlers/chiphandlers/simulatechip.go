@@ -0,0 +1,88 @@
+package chiphandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+	"github.com/bauerbrun0/nand2tetris-web/internal/validator"
+)
+
+const maxSimulationSteps = 10000
+
+// HandleSimulateChip runs the steps of the request on the chip, with the other
+// chips of the project available as parts, and responds with the values of its
+// pins after each step.
+func (h *Handlers) HandleSimulateChip(w http.ResponseWriter, r *http.Request) {
+	projectId, err := strconv.ParseInt(r.PathValue("projectId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+	chipId, err := strconv.ParseInt(r.PathValue("chipId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid chip id")
+		return
+	}
+
+	var simulateChipRequest apidata.SimulateChipRequest
+	err = h.Application.ReadJSON(w, r, &simulateChipRequest)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	v := &validator.Validator{
+		Validate: validator.NewValidator(),
+	}
+	v.CheckFieldBool(len(simulateChipRequest.Steps) > 0, "steps", "steps must not be empty")
+	v.CheckFieldBool(
+		len(simulateChipRequest.Steps) <= maxSimulationSteps,
+		"steps",
+		"steps must not contain more than 10000 steps",
+	)
+
+	if !v.Valid() {
+		h.Application.WriteJSONBadRequestError(w, r, v.GetFirstFieldError())
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	steps, err := h.Application.ChipService.SimulateChip(int32(chipId), int32(projectId), userId, simulateChipRequest.Steps)
+	if err != nil {
+		h.handleSimulationError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, apidata.SimulateChipResponse{Steps: steps}, nil)
+	if err != nil {
+		h.Application.ServerError(w, r, err)
+		return
+	}
+}
+
+// handleSimulationError responds to the errors of the simulation and test endpoints.
+func (h *Handlers) handleSimulationError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, services.ErrChipNotFound) {
+		h.Application.WriteJSONNotFoundError(w, r)
+		return
+	}
+	if errors.Is(err, services.ErrSimulationLimitExceeded) {
+		h.Application.WriteJSONError(w, r, http.StatusUnprocessableEntity, "the simulation took too long")
+		return
+	}
+	var simulationErr *services.SimulationError
+	if errors.As(err, &simulationErr) {
+		h.Application.WriteJSONBadRequestError(w, r, simulationErr.Error())
+		return
+	}
+	var hdlErr *services.HdlError
+	if errors.As(err, &hdlErr) {
+		h.Application.WriteJSONError(w, r, http.StatusUnprocessableEntity, hdlErr.Error())
+		return
+	}
+	h.Application.ServerError(w, r, err)
+}
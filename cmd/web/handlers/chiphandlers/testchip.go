@@ -0,0 +1,64 @@
+package chiphandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/validator"
+)
+
+// HandleTestChip runs a test script of the course on the chip, comparing the
+// output with the compare file of the request if there is one. A failing
+// comparison is not an error, it is reported in the result.
+func (h *Handlers) HandleTestChip(w http.ResponseWriter, r *http.Request) {
+	projectId, err := strconv.ParseInt(r.PathValue("projectId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+	chipId, err := strconv.ParseInt(r.PathValue("chipId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid chip id")
+		return
+	}
+
+	var testChipRequest apidata.TestChipRequest
+	err = h.Application.ReadJSON(w, r, &testChipRequest)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	v := &validator.Validator{
+		Validate: validator.NewValidator(),
+	}
+	v.CheckFieldTag(testChipRequest.Script, "required", "script", "script is required")
+	v.CheckFieldTag(testChipRequest.Script, "max=100000", "script", "script must not be more than 100000 characters long")
+	v.CheckFieldTag(testChipRequest.Compare, "max=1000000", "compare", "compare must not be more than 1000000 characters long")
+
+	if !v.Valid() {
+		h.Application.WriteJSONBadRequestError(w, r, v.GetFirstFieldError())
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	result, err := h.Application.ChipService.TestChip(
+		int32(chipId),
+		int32(projectId),
+		userId,
+		testChipRequest.Script,
+		testChipRequest.Compare,
+	)
+	if err != nil {
+		h.handleSimulationError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, result, nil)
+	if err != nil {
+		h.Application.ServerError(w, r, err)
+		return
+	}
+}
@@ -24,6 +24,12 @@ func (h *Handlers) HandleUpdateChip(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := h.Application.ReadIfMatch(r)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
 	userId := h.Application.GetAuthenticatedUserInfo(r).ID
 
 	var updateChipRequest *apidata.UpdateChipRequest
@@ -60,9 +66,18 @@ func (h *Handlers) HandleUpdateChip(w http.ResponseWriter, r *http.Request) {
 		userId,
 		updateChipRequest.Name,
 		updateChipRequest.Hdl,
+		version,
 	)
 
 	if err != nil {
+		var conflictErr *services.ChipVersionConflictError
+		if errors.As(err, &conflictErr) {
+			err = h.Application.WriteJSON(w, http.StatusConflict, conflictErr.Chip, h.Application.ETagHeader(conflictErr.Chip.Version))
+			if err != nil {
+				h.Application.ServerError(w, r, err)
+			}
+			return
+		}
 		if errors.Is(err, services.ErrChipNotFound) {
 			h.Application.WriteJSONNotFoundError(w, r)
 			return
@@ -75,7 +90,7 @@ func (h *Handlers) HandleUpdateChip(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.Application.WriteJSON(w, http.StatusOK, chip, nil)
+	err = h.Application.WriteJSON(w, http.StatusOK, chip, h.Application.ETagHeader(chip.Version))
 	if err != nil {
 		h.Application.ServerError(w, r, err)
 		return
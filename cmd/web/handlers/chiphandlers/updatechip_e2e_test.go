@@ -0,0 +1,160 @@
+package chiphandlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/bauerbrun0/nand2tetris-web/internal/testutils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleUpdateChip(t *testing.T) {
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+		Logs: false,
+	})
+	defer ts.Close()
+
+	ts.MustLogIn(t, testutils.LoginParams{})
+
+	// the service runs with the context of the whole test
+	ctx := t.Context()
+	hdl := "CHIP Not {\n    IN in;\n    OUT out;\n}"
+	chipAt := func(version int32) models.Chip {
+		return models.Chip{
+			ID:        testutils.MockId,
+			ProjectID: testutils.MockId,
+			Name:      "Not",
+			Hdl:       pgtype.Text{String: hdl, Valid: true},
+			Version:   version,
+		}
+	}
+
+	expectGetChip := func(version int32) {
+		queries.EXPECT().IsProjectOwnedByUser(ctx, models.IsProjectOwnedByUserParams{
+			ID:     testutils.MockId,
+			UserID: testutils.MockUserId,
+		}).Return(true, nil).Once()
+		queries.EXPECT().GetChip(ctx, models.GetChipParams{
+			ID:        testutils.MockId,
+			ProjectID: testutils.MockId,
+		}).Return(chipAt(version), nil).Once()
+	}
+
+	expectUpdateChip := func(version int32, err error) {
+		updatedChip := chipAt(version + 1)
+		if err != nil {
+			updatedChip = models.Chip{}
+		}
+		queries.EXPECT().UpdateChip(ctx, models.UpdateChipParams{
+			ID:      testutils.MockId,
+			Name:    "Not",
+			Hdl:     pgtype.Text{String: hdl, Valid: true},
+			Version: version,
+		}).Return(updatedChip, err).Once()
+	}
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		wantCode    int
+		wantVersion int32
+		before      func(t *testing.T)
+	}{
+		{
+			name:        "Current version",
+			ifMatch:     `"3"`,
+			wantCode:    http.StatusOK,
+			wantVersion: 4,
+			before: func(t *testing.T) {
+				expectGetChip(3)
+				expectUpdateChip(3, nil)
+			},
+		},
+		{
+			name:        "Weak tag of the current version",
+			ifMatch:     `W/"3"`,
+			wantCode:    http.StatusOK,
+			wantVersion: 4,
+			before: func(t *testing.T) {
+				expectGetChip(3)
+				expectUpdateChip(3, nil)
+			},
+		},
+		{
+			name:        "No If-Match header",
+			wantCode:    http.StatusOK,
+			wantVersion: 4,
+			before: func(t *testing.T) {
+				expectGetChip(3)
+				expectUpdateChip(3, nil)
+			},
+		},
+		{
+			name:        "Outdated version",
+			ifMatch:     `"2"`,
+			wantCode:    http.StatusConflict,
+			wantVersion: 3,
+			before: func(t *testing.T) {
+				expectGetChip(3)
+			},
+		},
+		{
+			name:        "Changed while updating",
+			ifMatch:     `"3"`,
+			wantCode:    http.StatusConflict,
+			wantVersion: 4,
+			before: func(t *testing.T) {
+				expectGetChip(3)
+				expectUpdateChip(3, pgx.ErrNoRows)
+				queries.EXPECT().GetChip(ctx, models.GetChipParams{
+					ID:        testutils.MockId,
+					ProjectID: testutils.MockId,
+				}).Return(chipAt(4), nil).Once()
+			},
+		},
+		{
+			name:     "Malformed If-Match header",
+			ifMatch:  "3",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.before != nil {
+				tt.before(t)
+			}
+
+			header := http.Header{}
+			if tt.ifMatch != "" {
+				header.Set("If-Match", tt.ifMatch)
+			}
+			body, err := json.Marshal(apidata.UpdateChipRequest{Hdl: &hdl})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := ts.SendJSON(t, http.MethodPatch, "/api/projects/1/chips/1", string(body), header)
+			assert.Equal(t, tt.wantCode, result.Status)
+			if tt.wantVersion == 0 {
+				return
+			}
+
+			// a conflict returns the current chip for the client to show
+			var chip apidata.Chip
+			err = json.Unmarshal([]byte(result.Body), &chip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.wantVersion, chip.Version)
+			assert.Equal(t, hdl, chip.Hdl)
+			assert.Equal(t, strconv.Quote(strconv.Itoa(int(tt.wantVersion))), result.Header.Get("ETag"))
+		})
+	}
+}
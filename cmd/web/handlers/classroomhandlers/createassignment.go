@@ -0,0 +1,53 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/validator"
+)
+
+// HandleCreateAssignment creates an assignment, giving every student of the
+// classroom a copy of the starter project.
+func (h *Handlers) HandleCreateAssignment(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+
+	var createAssignmentRequest apidata.CreateAssignmentRequest
+	err = h.Application.ReadJSON(w, r, &createAssignmentRequest)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	v := &validator.Validator{
+		Validate: validator.NewValidator(),
+	}
+
+	v.CheckFieldTag(createAssignmentRequest.Title, "required", "title", "title is required")
+	v.CheckFieldTag(createAssignmentRequest.Title, "max=100", "title", "title must not be more than 100 characters long")
+	v.CheckFieldTag(createAssignmentRequest.Title, "min=2", "title", "title must be at least 2 characters long")
+	v.CheckFieldTag(createAssignmentRequest.Description, "max=500", "description", "description must not be more than 500 characters long")
+	if !v.Valid() {
+		h.Application.WriteJSONBadRequestError(w, r, v.GetFirstFieldError())
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	assignment, err := h.Application.ClassroomService.CreateAssignment(int32(classroomId), createAssignmentRequest, userId)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusCreated, assignment, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,67 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/validator"
+)
+
+// HandleCreateAssignmentTest adds a reference test to the assignment, that the
+// submissions are graded with.
+func (h *Handlers) HandleCreateAssignmentTest(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+	assignmentId, err := strconv.ParseInt(r.PathValue("assignmentId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid assignment id")
+		return
+	}
+
+	var createAssignmentTestRequest apidata.CreateAssignmentTestRequest
+	err = h.Application.ReadJSON(w, r, &createAssignmentTestRequest)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	v := &validator.Validator{
+		Validate: validator.NewValidator(),
+	}
+
+	v.CheckFieldTag(createAssignmentTestRequest.Name, "required", "name", "name is required")
+	v.CheckFieldTag(createAssignmentTestRequest.Name, "max=100", "name", "name must not be more than 100 characters long")
+	v.CheckFieldTag(createAssignmentTestRequest.Chip, "required", "chip", "chip is required")
+	v.CheckFieldTag(createAssignmentTestRequest.Chip, "max=100", "chip", "chip must not be more than 100 characters long")
+	v.CheckFieldTag(createAssignmentTestRequest.Script, "required", "script", "script is required")
+	v.CheckFieldTag(createAssignmentTestRequest.Script, "max=100000", "script", "script must not be more than 100000 characters long")
+	v.CheckFieldTag(createAssignmentTestRequest.Compare, "required", "compare", "compare is required")
+	v.CheckFieldTag(createAssignmentTestRequest.Compare, "max=1000000", "compare", "compare must not be more than 1000000 characters long")
+	if !v.Valid() {
+		h.Application.WriteJSONBadRequestError(w, r, v.GetFirstFieldError())
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	test, err := h.Application.GradingService.CreateAssignmentTest(
+		int32(classroomId),
+		int32(assignmentId),
+		createAssignmentTestRequest,
+		userId,
+	)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusCreated, test, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,43 @@
+package classroomhandlers
+
+import (
+	"net/http"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/validator"
+)
+
+func (h *Handlers) HandleCreateClassroom(w http.ResponseWriter, r *http.Request) {
+	var createClassroomRequest apidata.CreateClassroomRequest
+	err := h.Application.ReadJSON(w, r, &createClassroomRequest)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	v := &validator.Validator{
+		Validate: validator.NewValidator(),
+	}
+
+	v.CheckFieldTag(createClassroomRequest.Name, "required", "name", "name is required")
+	v.CheckFieldTag(createClassroomRequest.Name, "max=100", "name", "name must not be more than 100 characters long")
+	v.CheckFieldTag(createClassroomRequest.Name, "min=2", "name", "name must be at least 2 characters long")
+	if !v.Valid() {
+		h.Application.WriteJSONBadRequestError(w, r, v.GetFirstFieldError())
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	classroom, err := h.Application.ClassroomService.CreateClassroom(createClassroomRequest.Name, userId)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusCreated, classroom, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
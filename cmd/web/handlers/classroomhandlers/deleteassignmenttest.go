@@ -0,0 +1,43 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+func (h *Handlers) HandleDeleteAssignmentTest(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+	assignmentId, err := strconv.ParseInt(r.PathValue("assignmentId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid assignment id")
+		return
+	}
+	testId, err := strconv.ParseInt(r.PathValue("testId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid test id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	test, err := h.Application.GradingService.DeleteAssignmentTest(
+		int32(testId),
+		int32(classroomId),
+		int32(assignmentId),
+		userId,
+	)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, test, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
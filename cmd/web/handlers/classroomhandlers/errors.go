@@ -0,0 +1,35 @@
+package classroomhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// writeClassroomError writes the response for an error of the classroom or the
+// grading service, a server error if it is not one of their own.
+func (h *Handlers) writeClassroomError(w http.ResponseWriter, r *http.Request, err error) {
+	var simulationErr *services.SimulationError
+	switch {
+	case errors.Is(err, services.ErrClassroomNotFound),
+		errors.Is(err, services.ErrClassroomMemberNotFound),
+		errors.Is(err, services.ErrAssignmentNotFound),
+		errors.Is(err, services.ErrSubmissionNotFound),
+		errors.Is(err, services.ErrAssignmentTestNotFound),
+		errors.Is(err, services.ErrGradingRunNotFound):
+		h.Application.WriteJSONNotFoundError(w, r)
+	case errors.Is(err, services.ErrClassroomForbidden):
+		h.Application.WriteJSONError(w, r, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+	case errors.Is(err, services.ErrAlreadyClassroomMember):
+		h.Application.WriteJSONError(w, r, http.StatusConflict, "already a member of the classroom")
+	case errors.Is(err, services.ErrProjectNotFound):
+		h.Application.WriteJSONBadRequestError(w, r, "starter project not found")
+	case errors.Is(err, services.ErrAssignmentTestNameTaken):
+		h.Application.WriteJSONBadRequestError(w, r, "test name is already taken")
+	case errors.As(err, &simulationErr):
+		h.Application.WriteJSONBadRequestError(w, r, simulationErr.Error())
+	default:
+		h.Application.WriteJSONServerError(w, r, err)
+	}
+}
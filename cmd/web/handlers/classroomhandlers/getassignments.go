@@ -0,0 +1,28 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+func (h *Handlers) HandleGetAssignments(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	assignments, err := h.Application.ClassroomService.GetAssignments(int32(classroomId), userId)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, assignments, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,33 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+func (h *Handlers) HandleGetAssignmentTests(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+	assignmentId, err := strconv.ParseInt(r.PathValue("assignmentId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid assignment id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	tests, err := h.Application.GradingService.GetAssignmentTests(int32(classroomId), int32(assignmentId), userId)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, tests, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,28 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+func (h *Handlers) HandleGetClassroomMembers(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	members, err := h.Application.ClassroomService.GetClassroomMembers(int32(classroomId), userId)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, members, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
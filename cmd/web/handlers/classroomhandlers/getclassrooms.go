@@ -0,0 +1,19 @@
+package classroomhandlers
+
+import "net/http"
+
+func (h *Handlers) HandleGetClassrooms(w http.ResponseWriter, r *http.Request) {
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	classrooms, err := h.Application.ClassroomService.GetClassrooms(userId)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, classrooms, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
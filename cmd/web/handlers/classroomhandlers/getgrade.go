@@ -0,0 +1,45 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// HandleGetGrade responds with the latest grading run of the submission of the
+// student, with the result of every test once it is done.
+func (h *Handlers) HandleGetGrade(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+	assignmentId, err := strconv.ParseInt(r.PathValue("assignmentId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid assignment id")
+		return
+	}
+	studentId, err := strconv.ParseInt(r.PathValue("userId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid user id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	run, err := h.Application.GradingService.GetGrade(
+		int32(classroomId),
+		int32(assignmentId),
+		int32(studentId),
+		userId,
+	)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, run, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,35 @@
+package classroomhandlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// HandleGetGradebook responds with the latest results of every student of the
+// assignment as a CSV file.
+func (h *Handlers) HandleGetGradebook(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+	assignmentId, err := strconv.ParseInt(r.PathValue("assignmentId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid assignment id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	gradebook, err := h.Application.GradingService.GetGradebook(int32(classroomId), int32(assignmentId), userId)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="gradebook-%d.csv"`, assignmentId))
+	w.WriteHeader(http.StatusOK)
+	w.Write(gradebook)
+}
@@ -0,0 +1,45 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// HandleGetSubmission responds with the project of a student for the
+// assignment, for the teachers of the classroom to review.
+func (h *Handlers) HandleGetSubmission(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+	assignmentId, err := strconv.ParseInt(r.PathValue("assignmentId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid assignment id")
+		return
+	}
+	studentId, err := strconv.ParseInt(r.PathValue("userId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid user id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	submission, err := h.Application.ClassroomService.GetSubmission(
+		int32(classroomId),
+		int32(assignmentId),
+		int32(studentId),
+		userId,
+	)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, submission, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,36 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// HandleGradeAssignment queues the submissions of the assignment for grading,
+// every submission for teachers and their own for students, responding with
+// the queued runs before they are graded.
+func (h *Handlers) HandleGradeAssignment(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+	assignmentId, err := strconv.ParseInt(r.PathValue("assignmentId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid assignment id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	runs, err := h.Application.GradingService.QueueGrading(int32(classroomId), int32(assignmentId), userId)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusAccepted, runs, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,7 @@
+package classroomhandlers
+
+import "github.com/bauerbrun0/nand2tetris-web/cmd/web/application"
+
+type Handlers struct {
+	*application.Application
+}
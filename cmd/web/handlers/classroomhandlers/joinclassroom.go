@@ -0,0 +1,43 @@
+package classroomhandlers
+
+import (
+	"net/http"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/validator"
+)
+
+// HandleJoinClassroom adds the user to the classroom of the invite code as a
+// student.
+func (h *Handlers) HandleJoinClassroom(w http.ResponseWriter, r *http.Request) {
+	var joinClassroomRequest apidata.JoinClassroomRequest
+	err := h.Application.ReadJSON(w, r, &joinClassroomRequest)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	v := &validator.Validator{
+		Validate: validator.NewValidator(),
+	}
+
+	v.CheckFieldTag(joinClassroomRequest.InviteCode, "required", "inviteCode", "invite code is required")
+	if !v.Valid() {
+		h.Application.WriteJSONBadRequestError(w, r, v.GetFirstFieldError())
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	classroom, err := h.Application.ClassroomService.JoinClassroom(joinClassroomRequest.InviteCode, userId)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusCreated, classroom, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,64 @@
+package classroomhandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/validator"
+)
+
+// HandleUpdateClassroomMember changes the role of a member of the classroom.
+func (h *Handlers) HandleUpdateClassroomMember(w http.ResponseWriter, r *http.Request) {
+	classroomId, err := strconv.ParseInt(r.PathValue("classroomId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid classroom id")
+		return
+	}
+	memberId, err := strconv.ParseInt(r.PathValue("userId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid user id")
+		return
+	}
+
+	var updateClassroomMemberRequest apidata.UpdateClassroomMemberRequest
+	err = h.Application.ReadJSON(w, r, &updateClassroomMemberRequest)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	v := &validator.Validator{
+		Validate: validator.NewValidator(),
+	}
+
+	v.CheckFieldBool(
+		updateClassroomMemberRequest.Role == apidata.ClassroomRoleTeacher ||
+			updateClassroomMemberRequest.Role == apidata.ClassroomRoleStudent,
+		"role",
+		"role must be teacher or student",
+	)
+	if !v.Valid() {
+		h.Application.WriteJSONBadRequestError(w, r, v.GetFirstFieldError())
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	member, err := h.Application.ClassroomService.UpdateClassroomMemberRole(
+		int32(classroomId),
+		int32(memberId),
+		updateClassroomMemberRequest.Role,
+		userId,
+	)
+	if err != nil {
+		h.writeClassroomError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, member, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
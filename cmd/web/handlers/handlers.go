@@ -3,14 +3,16 @@ package handlers
 import (
 	"github.com/bauerbrun0/nand2tetris-web/cmd/web/application"
 	"github.com/bauerbrun0/nand2tetris-web/cmd/web/handlers/chiphandlers"
+	"github.com/bauerbrun0/nand2tetris-web/cmd/web/handlers/classroomhandlers"
 	"github.com/bauerbrun0/nand2tetris-web/cmd/web/handlers/projecthandlers"
 	"github.com/bauerbrun0/nand2tetris-web/cmd/web/handlers/userhandlers"
 )
 
 type Handlers struct {
-	User    *userhandlers.Handlers
-	Project *projecthandlers.Handlers
-	Chip    *chiphandlers.Handlers
+	User      *userhandlers.Handlers
+	Project   *projecthandlers.Handlers
+	Chip      *chiphandlers.Handlers
+	Classroom *classroomhandlers.Handlers
 	*application.Application
 }
 
@@ -19,6 +21,7 @@ func NewHandlers(app *application.Application) *Handlers {
 		User:        NewUserHandlers(app),
 		Project:     NewProjectHandlers(app),
 		Chip:        NewChipHandlers(app),
+		Classroom:   NewClassroomHandlers(app),
 		Application: app,
 	}
 }
@@ -40,3 +43,9 @@ func NewChipHandlers(app *application.Application) *chiphandlers.Handlers {
 		Application: app,
 	}
 }
+
+func NewClassroomHandlers(app *application.Application) *classroomhandlers.Handlers {
+	return &classroomhandlers.Handlers{
+		Application: app,
+	}
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func TestPing(t *testing.T) {
-	ts, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, _, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
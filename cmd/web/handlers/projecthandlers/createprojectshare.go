@@ -0,0 +1,55 @@
+package projecthandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+	"github.com/bauerbrun0/nand2tetris-web/internal/validator"
+)
+
+func (h *Handlers) HandleCreateProjectShare(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+
+	var createProjectShareRequest apidata.CreateProjectShareRequest
+	err = h.Application.ReadJSON(w, r, &createProjectShareRequest)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	v := &validator.Validator{
+		Validate: validator.NewValidator(),
+	}
+
+	if createProjectShareRequest.Expires != nil {
+		v.CheckFieldBool(createProjectShareRequest.Expires.After(time.Now()), "expires", "expires must be in the future")
+	}
+
+	if !v.Valid() {
+		h.Application.WriteJSONBadRequestError(w, r, v.GetFirstFieldError())
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	share, err := h.Application.ProjectService.CreateProjectShare(int32(id), createProjectShareRequest.Expires, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	h.Application.WriteJSON(w, http.StatusCreated, share, nil)
+}
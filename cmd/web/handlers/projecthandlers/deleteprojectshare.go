@@ -0,0 +1,37 @@
+package projecthandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+func (h *Handlers) HandleDeleteProjectShare(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+	shareId, err := strconv.ParseInt(r.PathValue("shareId"), 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid share id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	share, err := h.Application.ProjectService.DeleteProjectShare(int32(shareId), int32(id), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) || errors.Is(err, services.ErrProjectShareNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	h.Application.WriteJSON(w, http.StatusOK, share, nil)
+}
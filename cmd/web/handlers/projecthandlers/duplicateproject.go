@@ -0,0 +1,37 @@
+package projecthandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+func (h *Handlers) HandleDuplicateProject(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	project, err := h.Application.ProjectService.DuplicateProject(int32(id), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		if errors.Is(err, models.ErrProjectTitleTaken) {
+			h.Application.WriteJSONError(w, r, http.StatusConflict, "project title is already taken, try again")
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	h.Application.WriteJSON(w, http.StatusCreated, project, nil)
+}
@@ -0,0 +1,56 @@
+package projecthandlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/projectarchive"
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// HandleExportProject responds with a zip of the project, one .hdl file per
+// chip and the title and the description in project.json.
+func (h *Handlers) HandleExportProject(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+
+	project, chips, err := h.Application.ProjectService.ExportProject(int32(id), h.Application.GetAuthenticatedUserInfo(r).ID)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	archiveChips := make([]projectarchive.Chip, 0, len(chips))
+	for _, chip := range chips {
+		archiveChips = append(archiveChips, projectarchive.Chip{Name: chip.Name, Hdl: chip.Hdl})
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, project.Slug))
+	w.WriteHeader(http.StatusOK)
+
+	// the status has been sent, so an error can only be logged
+	err = projectarchive.Write(w, projectarchive.Metadata{
+		Title:       project.Title,
+		Description: project.Description,
+	}, archiveChips)
+	if err != nil {
+		h.Application.Logger.Error(
+			"Failed to write project archive",
+			slog.String("error", err.Error()),
+			slog.String("method", r.Method),
+			slog.String("uri", r.URL.RequestURI()),
+		)
+	}
+}
@@ -0,0 +1,13 @@
+package projecthandlers
+
+import "net/http"
+
+// HandleGetCurriculum responds with the projects of the course, with the stub,
+// the specification and the reference test of every chip.
+func (h *Handlers) HandleGetCurriculum(w http.ResponseWriter, r *http.Request) {
+	err := h.Application.WriteJSON(w, http.StatusOK, h.Application.ProgressService.GetCurriculum(), nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
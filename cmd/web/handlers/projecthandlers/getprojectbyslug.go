@@ -20,7 +20,7 @@ func (h *Handlers) HandleGetProjectBySlug(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err = h.Application.WriteJSON(w, http.StatusOK, project, nil)
+	err = h.Application.WriteJSON(w, http.StatusOK, project, h.Application.ETagHeader(project.Version))
 	if err != nil {
 		h.Application.WriteJSONServerError(w, r, err)
 		return
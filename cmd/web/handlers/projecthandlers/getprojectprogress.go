@@ -0,0 +1,40 @@
+package projecthandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// HandleGetProjectProgress responds with the status of every chip of the
+// curriculum in the project, running the reference tests of the chips.
+func (h *Handlers) HandleGetProjectProgress(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+
+	progress, err := h.Application.ProgressService.GetProjectProgress(int32(id), h.Application.GetAuthenticatedUserInfo(r).ID)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		if errors.Is(err, services.ErrSimulationLimitExceeded) {
+			h.Application.WriteJSONError(w, r, http.StatusUnprocessableEntity, "the simulation took too long")
+			return
+		}
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, progress, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
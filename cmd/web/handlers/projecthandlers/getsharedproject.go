@@ -0,0 +1,28 @@
+package projecthandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// HandleGetSharedProject responds with the project of a share link. It needs
+// no authentication, the token of the link being the only credential.
+func (h *Handlers) HandleGetSharedProject(w http.ResponseWriter, r *http.Request) {
+	project, err := h.Application.ProjectService.GetSharedProject(r.PathValue("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrProjectShareNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusOK, project, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,85 @@
+package projecthandlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/bauerbrun0/nand2tetris-web/internal/projectarchive"
+	"github.com/bauerbrun0/nand2tetris-web/internal/validator"
+)
+
+const maxImportSize = 10 << 20
+
+// HandleImportProject creates a project from the zip archive in the request
+// body, either one exported by HandleExportProject or the project folders of
+// the nand2tetris software suite. The title and description query parameters
+// take precedence over the ones in the archive.
+func (h *Handlers) HandleImportProject(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			message := fmt.Sprintf("archive must not be larger than %d MB", maxImportSize>>20)
+			h.Application.WriteJSONError(w, r, http.StatusRequestEntityTooLarge, message)
+			return
+		}
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	archive, err := projectarchive.Read(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		var archiveErr *projectarchive.Error
+		if errors.As(err, &archiveErr) {
+			h.Application.WriteJSONBadRequestError(w, r, archiveErr.Error())
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	var title, description string
+	if archive.Metadata != nil {
+		title = archive.Metadata.Title
+		description = archive.Metadata.Description
+	}
+	query := r.URL.Query()
+	if query.Has("title") {
+		title = query.Get("title")
+	}
+	if query.Has("description") {
+		description = query.Get("description")
+	}
+
+	v := &validator.Validator{
+		Validate: validator.NewValidator(),
+	}
+
+	v.CheckFieldTag(title, "required", "title", "title is required")
+	v.CheckFieldTag(title, "max=100", "title", "title must not be more than 100 characters long")
+	v.CheckFieldTag(title, "min=2", "title", "title must be at least 2 characters long")
+	v.CheckFieldTag(description, "max=500", "description", "description must not be more than 500 characters long")
+	if !v.Valid() {
+		h.Application.WriteJSONBadRequestError(w, r, v.GetFirstFieldError())
+		return
+	}
+
+	userId := h.Application.GetAuthenticatedUserInfo(r).ID
+
+	project, err := h.Application.ProjectService.ImportProject(title, description, archive.Chips, userId)
+	if err != nil {
+		if errors.Is(err, models.ErrProjectTitleTaken) {
+			h.Application.WriteJSONBadRequestError(w, r, "project title is already taken")
+			return
+		}
+		h.Application.ServerError(w, r, err)
+		return
+	}
+
+	h.Application.WriteJSON(w, http.StatusCreated, project, nil)
+}
@@ -0,0 +1,52 @@
+package projecthandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+)
+
+// HandleSeedProject adds the stubs of the chips of a project of the course to
+// the project, responding with the chips that were added.
+func (h *Handlers) HandleSeedProject(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, "invalid project id")
+		return
+	}
+
+	var seedProjectRequest apidata.SeedProjectRequest
+	err = h.Application.ReadJSON(w, r, &seedProjectRequest)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
+	chips, err := h.Application.ProjectService.SeedProject(
+		int32(id),
+		seedProjectRequest.Project,
+		h.Application.GetAuthenticatedUserInfo(r).ID,
+	)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			h.Application.WriteJSONNotFoundError(w, r)
+			return
+		}
+		if errors.Is(err, services.ErrCurriculumProjectNotFound) {
+			h.Application.WriteJSONBadRequestError(w, r, "the curriculum has no such project")
+			return
+		}
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+
+	err = h.Application.WriteJSON(w, http.StatusCreated, chips, nil)
+	if err != nil {
+		h.Application.WriteJSONServerError(w, r, err)
+		return
+	}
+}
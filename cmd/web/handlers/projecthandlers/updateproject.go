@@ -19,6 +19,12 @@ func (h *Handlers) HandleUpdateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := h.Application.ReadIfMatch(r)
+	if err != nil {
+		h.Application.WriteJSONBadRequestError(w, r, err.Error())
+		return
+	}
+
 	var updateProjectRequest apidata.UpdateProjectRequest
 	err = h.Application.ReadJSON(w, r, &updateProjectRequest)
 	if err != nil {
@@ -48,10 +54,16 @@ func (h *Handlers) HandleUpdateProject(w http.ResponseWriter, r *http.Request) {
 		int32(id),
 		updateProjectRequest.Title,
 		updateProjectRequest.Description,
+		version,
 		userId,
 	)
 
 	if err != nil {
+		var conflictErr *services.ProjectVersionConflictError
+		if errors.As(err, &conflictErr) {
+			h.Application.WriteJSON(w, http.StatusConflict, conflictErr.Project, h.Application.ETagHeader(conflictErr.Project.Version))
+			return
+		}
 		if errors.Is(err, models.ErrProjectTitleTaken) {
 			h.Application.WriteJSONBadRequestError(w, r, "project title is already taken")
 			return
@@ -64,5 +76,5 @@ func (h *Handlers) HandleUpdateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.Application.WriteJSON(w, http.StatusOK, project, nil)
+	h.Application.WriteJSON(w, http.StatusOK, project, h.Application.ETagHeader(project.Version))
 }
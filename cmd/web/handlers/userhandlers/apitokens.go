@@ -0,0 +1,83 @@
+package userhandlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+	"github.com/bauerbrun0/nand2tetris-web/ui/pages"
+	"github.com/bauerbrun0/nand2tetris-web/ui/pages/usersettingspage"
+)
+
+func (h *Handlers) handleUserSettingsCreateApiTokenPost(w http.ResponseWriter, r *http.Request, data *usersettingspage.UserSettingsPageData) {
+	data.CheckFieldTag(data.CreateApiToken.Name, "required", "CreateApiToken.Name", data.T("error.field_required"))
+	data.CheckFieldTag(
+		data.CreateApiToken.Name, "max=255", "CreateApiToken.Name", data.TTemplate("error.field_too_many_characters", map[string]string{"Max": "255"}),
+	)
+	data.CheckFieldBool(
+		data.CreateApiToken.Scope == pages.ApiTokenScopeRead || data.CreateApiToken.Scope == pages.ApiTokenScopeWrite,
+		"CreateApiToken.Scope", data.T("error.invalid_form_field"),
+	)
+	data.CheckFieldBool(
+		slices.Contains(usersettingspage.ApiTokenExpirations, data.CreateApiToken.ExpiresIn),
+		"CreateApiToken.ExpiresIn", data.T("error.invalid_form_field"),
+	)
+
+	if !data.Valid() {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		h.Render(r.Context(), w, r, usersettingspage.Page(*data))
+		return
+	}
+
+	token, apiToken, err := h.ApiTokenService.CreateApiToken(
+		data.UserInfo.ID,
+		data.CreateApiToken.Name,
+		data.CreateApiToken.Scope,
+		time.Duration(data.CreateApiToken.ExpiresIn)*24*time.Hour,
+	)
+	if err != nil {
+		h.ServerError(w, r, err)
+		return
+	}
+
+	// the page is rendered instead of redirecting to it, as the token is
+	// shown only once and is not to be stored in the session
+	data.CreatedApiToken = token
+	data.ApiTokens = append([]pages.ApiToken{*apiToken}, data.ApiTokens...)
+	data.InitialToasts = append(data.InitialToasts, pages.Toast{
+		Message:  data.T("toast.api_token_created"),
+		Variant:  "success",
+		Duration: 3000,
+	})
+	h.Render(r.Context(), w, r, usersettingspage.Page(*data))
+}
+
+func (h *Handlers) handleUserSettingsRevokeApiTokenPost(w http.ResponseWriter, r *http.Request, data *usersettingspage.UserSettingsPageData) {
+	err := h.ApiTokenService.RevokeApiToken(data.UserInfo.ID, data.RevokeApiToken.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrApiTokenNotFound) {
+			h.SessionManager.Put(r.Context(), "initialToasts", []pages.Toast{
+				{
+					Message:  data.T("toast.api_token_not_found"),
+					Variant:  "error",
+					Duration: 3000,
+				},
+			})
+			http.Redirect(w, r, "/user/settings", http.StatusSeeOther)
+			return
+		}
+		h.ServerError(w, r, err)
+		return
+	}
+
+	h.SessionManager.Put(r.Context(), "initialToasts", []pages.Toast{
+		{
+			Message:  data.T("toast.api_token_revoked"),
+			Variant:  "success",
+			Duration: 3000,
+		},
+	})
+	http.Redirect(w, r, "/user/settings", http.StatusSeeOther)
+}
@@ -0,0 +1,153 @@
+package userhandlers_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/cmd/web/handlers/userhandlers"
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+	"github.com/bauerbrun0/nand2tetris-web/internal/testutils"
+	"github.com/bauerbrun0/nand2tetris-web/ui/pages"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleUserSettingsCreateApiTokenPost(t *testing.T) {
+	ts, _, _, _, apiTokenService := testutils.NewTestServer(t, testutils.TestServerOptions{
+		Logs: false,
+	})
+	defer ts.Close()
+
+	_, csrfToken := ts.MustLogIn(t, testutils.LoginParams{})
+
+	const token = "n2t_abcdefghijkl_abcdefghijklmnopqrstuvwxyz012345"
+
+	tests := []struct {
+		name      string
+		tokenName string
+		scope     string
+		expiresIn string
+		wantCode  int
+		wantToken bool
+		before    func(t *testing.T)
+	}{
+		{
+			name:      "Valid submission",
+			tokenName: testutils.MockApiTokenName,
+			scope:     string(pages.ApiTokenScopeRead),
+			expiresIn: "30",
+			wantCode:  http.StatusOK,
+			wantToken: true,
+			before: func(t *testing.T) {
+				apiTokenService.EXPECT().
+					CreateApiToken(testutils.MockUserId, testutils.MockApiTokenName, pages.ApiTokenScopeRead, 30*24*time.Hour).
+					Return(token, &testutils.MockApiTokens[0], nil).Once()
+			},
+		},
+		{
+			name:      "Never expires",
+			tokenName: testutils.MockApiTokenName,
+			scope:     string(pages.ApiTokenScopeWrite),
+			expiresIn: "0",
+			wantCode:  http.StatusOK,
+			wantToken: true,
+			before: func(t *testing.T) {
+				apiTokenService.EXPECT().
+					CreateApiToken(testutils.MockUserId, testutils.MockApiTokenName, pages.ApiTokenScopeWrite, time.Duration(0)).
+					Return(token, &testutils.MockApiTokens[0], nil).Once()
+			},
+		},
+		{
+			name:      "Empty name",
+			tokenName: "",
+			scope:     string(pages.ApiTokenScopeRead),
+			expiresIn: "30",
+			wantCode:  http.StatusUnprocessableEntity,
+		},
+		{
+			name:      "Too long name",
+			tokenName: strings.Repeat("x", 256),
+			scope:     string(pages.ApiTokenScopeRead),
+			expiresIn: "30",
+			wantCode:  http.StatusUnprocessableEntity,
+		},
+		{
+			name:      "Invalid scope",
+			tokenName: testutils.MockApiTokenName,
+			scope:     "admin",
+			expiresIn: "30",
+			wantCode:  http.StatusUnprocessableEntity,
+		},
+		{
+			name:      "Invalid expiration",
+			tokenName: testutils.MockApiTokenName,
+			scope:     string(pages.ApiTokenScopeRead),
+			expiresIn: "1000",
+			wantCode:  http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.before != nil {
+				tt.before(t)
+			}
+
+			form := url.Values{}
+			form.Add("Action", string(userhandlers.ActionCreateApiToken))
+			form.Add("csrf_token", csrfToken)
+			form.Add("CreateApiToken.Name", tt.tokenName)
+			form.Add("CreateApiToken.Scope", tt.scope)
+			form.Add("CreateApiToken.ExpiresIn", tt.expiresIn)
+
+			testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
+			result := ts.PostForm(t, "/user/settings", form)
+			assert.Equal(t, tt.wantCode, result.Status)
+			assert.Equal(t, tt.wantToken, strings.Contains(result.Body, token))
+		})
+	}
+}
+
+func TestHandleUserSettingsRevokeApiTokenPost(t *testing.T) {
+	ts, _, _, _, apiTokenService := testutils.NewTestServer(t, testutils.TestServerOptions{
+		Logs: false,
+	})
+	defer ts.Close()
+
+	_, csrfToken := ts.MustLogIn(t, testutils.LoginParams{})
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "Valid submission",
+			wantCode: http.StatusSeeOther,
+		},
+		{
+			name:     "Token not found",
+			err:      services.ErrApiTokenNotFound,
+			wantCode: http.StatusSeeOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiTokenService.EXPECT().RevokeApiToken(testutils.MockUserId, testutils.MockId).
+				Return(tt.err).Once()
+
+			form := url.Values{}
+			form.Add("Action", string(userhandlers.ActionRevokeApiToken))
+			form.Add("csrf_token", csrfToken)
+			form.Add("RevokeApiToken.ID", "1")
+
+			testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
+			result := ts.PostForm(t, "/user/settings", form)
+			assert.Equal(t, tt.wantCode, result.Status)
+			assert.Equal(t, "/user/settings", result.Header.Get("Location"))
+		})
+	}
+}
@@ -12,7 +12,7 @@ import (
 )
 
 func TestHandleUserSettingsCreatePasswordPost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, apiTokenService := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -95,6 +95,7 @@ func TestHandleUserSettingsCreatePasswordPost(t *testing.T) {
 			form.Add("CreatePassword.Password", tt.password)
 			form.Add("CreatePassword.PasswordConfirmation", tt.passwordConfirmation)
 
+			testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
 			result := ts.PostForm(t, "/user/settings", form)
 			assert.Equal(t, tt.wantCode, result.Status)
 
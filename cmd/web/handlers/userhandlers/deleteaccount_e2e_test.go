@@ -15,7 +15,7 @@ import (
 )
 
 func TestHandleUserSettingsDeleteAccountPost(t *testing.T) {
-	ts, queries, githubOauthService, googleOauthService := testutils.NewTestServer(t,
+	ts, queries, githubOauthService, googleOauthService, apiTokenService := testutils.NewTestServer(t,
 		testutils.TestServerOptions{
 			Logs: false,
 		},
@@ -114,6 +114,7 @@ func TestHandleUserSettingsDeleteAccountPost(t *testing.T) {
 			form.Add("DeleteAccount.Email", tt.email)
 			form.Add("DeleteAccount.Password", tt.password)
 
+			testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
 			result := ts.PostForm(t, "/user/settings", form)
 			assert.Equal(t, tt.wantCode, result.Status)
 
@@ -125,7 +126,7 @@ func TestHandleUserSettingsDeleteAccountPost(t *testing.T) {
 }
 
 func TestUserDeleteAccountActionOAuthCallback(t *testing.T) {
-	ts, queries, githubOauthService, googleOauthService := testutils.NewTestServer(t,
+	ts, queries, githubOauthService, googleOauthService, _ := testutils.NewTestServer(t,
 		testutils.TestServerOptions{
 			Logs: false,
 		},
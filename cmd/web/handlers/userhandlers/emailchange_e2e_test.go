@@ -16,7 +16,7 @@ import (
 )
 
 func TestHandleUserSettingsChangeEmailPost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, apiTokenService := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -94,6 +94,7 @@ func TestHandleUserSettingsChangeEmailPost(t *testing.T) {
 			form.Add("ChangeEmail.Password", tt.password)
 			form.Add("ChangeEmail.NewEmail", tt.newEmail)
 
+			testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
 			result := ts.PostForm(t, "/user/settings", form)
 			assert.Equal(t, tt.wantCode, result.Status)
 
@@ -105,7 +106,7 @@ func TestHandleUserSettingsChangeEmailPost(t *testing.T) {
 }
 
 func TestHandleUserSettingsChangeEmailSendCodePost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, apiTokenService := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -179,6 +180,7 @@ func TestHandleUserSettingsChangeEmailSendCodePost(t *testing.T) {
 			form.Add("csrf_token", csrfToken)
 			form.Add("ChangeEmailSendCode.Code", tt.code)
 
+			testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
 			result := ts.PostForm(t, "/user/settings", form)
 			assert.Equal(t, tt.wantCode, result.Status)
 
@@ -13,7 +13,7 @@ import (
 )
 
 func TestUserVerifyEmail(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -52,7 +52,7 @@ func TestUserVerifyEmail(t *testing.T) {
 }
 
 func TestUserVerifyEmailPost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -148,7 +148,7 @@ func TestUserVerifyEmailPost(t *testing.T) {
 }
 
 func TestUserVerifyEmailResendCode(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -188,7 +188,7 @@ func TestUserVerifyEmailResendCode(t *testing.T) {
 }
 
 func TestUserVerifyEmailResendCodePost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -55,7 +55,9 @@ func parseActionString(str string) (Action, bool) {
 		string(ActionLinkGitHubAccount),
 		string(ActionLinkGoogleAccount),
 		string(ActionUnlinkGitHubAccount),
-		string(ActionUnlinkGoogleAccount):
+		string(ActionUnlinkGoogleAccount),
+		string(ActionCreateApiToken),
+		string(ActionRevokeApiToken):
 		return Action(str), true
 	default:
 		return "", false
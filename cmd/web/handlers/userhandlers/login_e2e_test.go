@@ -12,7 +12,7 @@ import (
 )
 
 func TestUserLogin(t *testing.T) {
-	ts, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, _, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -34,7 +34,7 @@ func TestUserLogin(t *testing.T) {
 }
 
 func TestUserLoginPost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
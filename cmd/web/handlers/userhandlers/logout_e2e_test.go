@@ -10,7 +10,7 @@ import (
 )
 
 func TestUserLogoutPost(t *testing.T) {
-	ts, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, _, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -14,7 +14,7 @@ import (
 )
 
 func TestHandleUserSettingsLinkAccountPost(t *testing.T) {
-	ts, queries, githubOauthService, googleOauthService := testutils.NewTestServer(t,
+	ts, queries, githubOauthService, googleOauthService, apiTokenService := testutils.NewTestServer(t,
 		testutils.TestServerOptions{
 			Logs: false,
 		},
@@ -144,6 +144,7 @@ func TestHandleUserSettingsLinkAccountPost(t *testing.T) {
 				t.Fatal("Invalid action")
 			}
 
+			testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
 			result := ts.PostForm(t, "/user/settings", form)
 			assert.Equal(t, tt.wantCode, result.Status)
 
@@ -155,7 +156,7 @@ func TestHandleUserSettingsLinkAccountPost(t *testing.T) {
 }
 
 func TestUserLinkOAuthCallback(t *testing.T) {
-	ts, queries, githubOauthService, googleOauthService := testutils.NewTestServer(t,
+	ts, queries, githubOauthService, googleOauthService, _ := testutils.NewTestServer(t,
 		testutils.TestServerOptions{
 			Logs: false,
 		},
@@ -13,7 +13,7 @@ import (
 )
 
 func TestUserLoginOAuth(t *testing.T) {
-	ts, _, githubOauthService, googleOauthService := testutils.NewTestServer(t,
+	ts, _, githubOauthService, googleOauthService, _ := testutils.NewTestServer(t,
 		testutils.TestServerOptions{
 			Logs: false,
 		},
@@ -76,7 +76,7 @@ func TestUserLoginOAuth(t *testing.T) {
 }
 
 func TestUserLoginOAuthCallback(t *testing.T) {
-	ts, queries, githubOauthService, googleOauthService := testutils.NewTestServer(t,
+	ts, queries, githubOauthService, googleOauthService, _ := testutils.NewTestServer(t,
 		testutils.TestServerOptions{
 			Logs: false,
 		},
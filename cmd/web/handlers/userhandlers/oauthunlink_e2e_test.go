@@ -13,7 +13,7 @@ import (
 )
 
 func TestHandleUserSettingsUnlinkAccountPost(t *testing.T) {
-	ts, queries, githubOauthService, googleOauthService := testutils.NewTestServer(t,
+	ts, queries, githubOauthService, googleOauthService, apiTokenService := testutils.NewTestServer(t,
 		testutils.TestServerOptions{
 			Logs: false,
 		},
@@ -155,6 +155,7 @@ func TestHandleUserSettingsUnlinkAccountPost(t *testing.T) {
 				t.Fatal("Invalid action")
 			}
 
+			testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
 			result := ts.PostForm(t, "/user/settings", form)
 			assert.Equal(t, tt.wantCode, result.Status)
 
@@ -166,7 +167,7 @@ func TestHandleUserSettingsUnlinkAccountPost(t *testing.T) {
 }
 
 func TestUserUnlinkOAuthCallback(t *testing.T) {
-	ts, queries, githubOauthService, googleOauthService := testutils.NewTestServer(t,
+	ts, queries, githubOauthService, googleOauthService, _ := testutils.NewTestServer(t,
 		testutils.TestServerOptions{
 			Logs: false,
 		},
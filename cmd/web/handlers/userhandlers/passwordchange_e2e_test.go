@@ -12,7 +12,7 @@ import (
 )
 
 func TestHandleUserSettingsChangePasswordPost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, apiTokenService := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -109,6 +109,7 @@ func TestHandleUserSettingsChangePasswordPost(t *testing.T) {
 			form.Add("ChangePassword.NewPassword", tt.newPassword)
 			form.Add("ChangePassword.NewPasswordConfirmation", tt.newPasswordConfirmation)
 
+			testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
 			result := ts.PostForm(t, "/user/settings", form)
 			assert.Equal(t, tt.wantCode, result.Status)
 
@@ -15,7 +15,7 @@ import (
 )
 
 func TestUserResetPasswordSendCode(t *testing.T) {
-	ts, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, _, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -35,7 +35,7 @@ func TestUserResetPasswordSendCode(t *testing.T) {
 }
 
 func TestUserResetPasswordSendCodePost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -128,7 +128,7 @@ func TestUserResetPasswordSendCodePost(t *testing.T) {
 }
 
 func TestUserResetPasswordEnterCode(t *testing.T) {
-	ts, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, _, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -148,7 +148,7 @@ func TestUserResetPasswordEnterCode(t *testing.T) {
 }
 
 func TestUserResetPasswordEnterCodePost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -256,7 +256,7 @@ func TestUserResetPasswordEnterCodePost(t *testing.T) {
 }
 
 func TestUserResetPassword(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -310,7 +310,7 @@ func TestUserResetPassword(t *testing.T) {
 }
 
 func TestUserResetPasswordPost(t *testing.T) {
-	ts, queries, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, queries, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
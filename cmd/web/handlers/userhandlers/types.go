@@ -27,6 +27,8 @@ const (
 	ActionLinkGoogleAccount   Action = "link-google-account"
 	ActionUnlinkGitHubAccount Action = "unlink-github-account"
 	ActionUnlinkGoogleAccount Action = "unlink-google-account"
+	ActionCreateApiToken      Action = "create-api-token"
+	ActionRevokeApiToken      Action = "revoke-api-token"
 )
 
 var ErrInvalidActionInSession = errors.New("handlers: invalid action stored in session")
@@ -13,6 +13,14 @@ func (h *Handlers) UserSettings(w http.ResponseWriter, r *http.Request) {
 		PageData: basePageData,
 	}
 	data.Validate = validator.NewValidator()
+
+	apiTokens, err := h.ApiTokenService.GetApiTokens(data.UserInfo.ID)
+	if err != nil {
+		h.ServerError(w, r, err)
+		return
+	}
+	data.ApiTokens = apiTokens
+
 	h.Render(r.Context(), w, r, usersettingspage.Page(data))
 }
 
@@ -29,6 +37,14 @@ func (h *Handlers) UserSettingsPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the tokens are listed on the page rendered when a form is invalid
+	apiTokens, err := h.ApiTokenService.GetApiTokens(data.UserInfo.ID)
+	if err != nil {
+		h.ServerError(w, r, err)
+		return
+	}
+	data.ApiTokens = apiTokens
+
 	action, ok := ParseAction(data.Action)
 	if !ok {
 		w.WriteHeader(http.StatusUnprocessableEntity)
@@ -55,6 +71,10 @@ func (h *Handlers) UserSettingsPost(w http.ResponseWriter, r *http.Request) {
 		h.handleUserSettingsUnlinkGoogleAccountPost(w, r, &data)
 	case ActionUnlinkGitHubAccount:
 		h.handleUserSettingsUnlinkGithubAccountPost(w, r, &data)
+	case ActionCreateApiToken:
+		h.handleUserSettingsCreateApiTokenPost(w, r, &data)
+	case ActionRevokeApiToken:
+		h.handleUserSettingsRevokeApiTokenPost(w, r, &data)
 	default:
 		w.WriteHeader(http.StatusBadRequest)
 		h.Render(r.Context(), w, r, usersettingspage.Page(data))
@@ -10,19 +10,29 @@ import (
 )
 
 func TestUserSettings(t *testing.T) {
-	ts, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, _, _, _, apiTokenService := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
 
 	t.Run("Can visit page if authenticated", func(t *testing.T) {
 		ts.MustLogIn(t, testutils.LoginParams{})
+		testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
 		result := ts.Get(t, "/user/settings")
 		assert.Equal(t, http.StatusOK, result.Status)
 		csrfToken := testutils.ExtractCSRFToken(t, result.Body)
 		assert.NotEmpty(t, csrfToken)
 	})
 
+	t.Run("Lists api tokens", func(t *testing.T) {
+		ts.MustLogIn(t, testutils.LoginParams{})
+		testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
+		result := ts.Get(t, "/user/settings")
+		assert.Equal(t, http.StatusOK, result.Status)
+		assert.Contains(t, result.Body, testutils.MockApiTokenName)
+		assert.Contains(t, result.Body, `name="RevokeApiToken.ID" value="1"`)
+	})
+
 	t.Run("Redirect if unauthenticated", func(t *testing.T) {
 		ts.RemoveCookie(t, "session")
 		result := ts.Get(t, "/user/settings")
@@ -31,7 +41,7 @@ func TestUserSettings(t *testing.T) {
 }
 
 func TestUserSettingsPost(t *testing.T) {
-	ts, _, _, _ := testutils.NewTestServer(t, testutils.TestServerOptions{
+	ts, _, _, _, apiTokenService := testutils.NewTestServer(t, testutils.TestServerOptions{
 		Logs: false,
 	})
 	defer ts.Close()
@@ -39,6 +49,7 @@ func TestUserSettingsPost(t *testing.T) {
 	_, csrfToken := ts.MustLogIn(t, testutils.LoginParams{})
 
 	t.Run("Invalid action", func(t *testing.T) {
+		testutils.ExpectGetApiTokensReturnsTokens(t, apiTokenService)
 		form := url.Values{}
 		form.Add("csrf_token", csrfToken)
 		form.Add("Action", "invalid")
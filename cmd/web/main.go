@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"runtime"
 	"strconv"
 	"time"
 
@@ -151,6 +152,17 @@ func main() {
 
 	projectService := services.NewProjectService(logger, ctx, queries, txStarter)
 	chipService := services.NewChipService(logger, ctx, queries, txStarter)
+	classroomService := services.NewClassroomService(logger, ctx, queries, txStarter)
+	gradingService := services.NewGradingService(logger, ctx, queries, txStarter)
+	progressService := services.NewProgressService(logger, ctx, queries, txStarter)
+	apiTokenService := services.NewApiTokenService(logger, ctx, queries, txStarter)
+
+	// half of the CPUs are left for the simulations run from the editor
+	err = gradingService.StartWorkers(max(1, runtime.NumCPU()/2))
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
 
 	app := &application.Application{
 		Logger:             logger,
@@ -163,6 +175,10 @@ func main() {
 		GoogleOauthService: googleOauthService,
 		ProjectService:     projectService,
 		ChipService:        chipService,
+		ClassroomService:   classroomService,
+		GradingService:     gradingService,
+		ProgressService:    progressService,
+		ApiTokenService:    apiTokenService,
 		Bundle:             bundle,
 	}
 
@@ -2,12 +2,15 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/bauerbrun0/nand2tetris-web/cmd/web/application"
 	"github.com/bauerbrun0/nand2tetris-web/internal/appctx"
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
 	"github.com/bauerbrun0/nand2tetris-web/ui/pages"
 	"github.com/justinas/nosurf"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
@@ -106,6 +109,59 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 	})
 }
 
+// AuthenticateApiToken authenticates the requests carrying a personal API token
+// in an "Authorization: Bearer" header, leaving the others to the session.
+// Tokens with the read scope are only accepted on safe methods.
+func (m *Middleware) AuthenticateApiToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			m.WriteJSONError(w, r, http.StatusUnauthorized, "invalid authorization header")
+			return
+		}
+
+		userId, scope, err := m.ApiTokenService.AuthenticateApiToken(strings.TrimSpace(token))
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidApiToken) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				m.WriteJSONError(w, r, http.StatusUnauthorized, "invalid or expired api token")
+				return
+			}
+			m.WriteJSONServerError(w, r, err)
+			return
+		}
+
+		if scope != pages.ApiTokenScopeWrite && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="write"`)
+			m.WriteJSONError(w, r, http.StatusForbidden, "the api token is read-only")
+			return
+		}
+
+		user, err := m.UserService.UserExists(userId)
+		if err != nil {
+			m.WriteJSONServerError(w, r, err)
+			return
+		}
+		if user == nil {
+			m.WriteJSONError(w, r, http.StatusUnauthorized, "invalid or expired api token")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), appctx.IsAuthenticatedContextKey, true)
+		ctx = context.WithValue(ctx, appctx.AuthenticatedUserInfoKey, user)
+		r = r.WithContext(ctx)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (m *Middleware) RequireAuthentication(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !m.IsAuthenticated(r) {
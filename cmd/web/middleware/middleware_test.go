@@ -3,11 +3,14 @@ package middleware
 import (
 	"bytes"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/bauerbrun0/nand2tetris-web/cmd/web/application"
+	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+	"github.com/bauerbrun0/nand2tetris-web/ui/pages"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,3 +52,92 @@ func TestCommonHeaders(t *testing.T) {
 	bytes.TrimSpace(body)
 	assert.Equal(t, string(body), "OK")
 }
+
+// fakeApiTokenService knows the tokens in tokens, the others are unknown,
+// revoked or expired, which the service does not tell apart.
+type fakeApiTokenService struct {
+	services.ApiTokenService
+	tokens map[string]pages.ApiTokenScope
+}
+
+func (s *fakeApiTokenService) AuthenticateApiToken(token string) (int32, pages.ApiTokenScope, error) {
+	scope, ok := s.tokens[token]
+	if !ok {
+		return 0, "", services.ErrInvalidApiToken
+	}
+	return 1, scope, nil
+}
+
+type fakeUserService struct {
+	services.UserService
+}
+
+func (s *fakeUserService) UserExists(id int32) (*pages.UserInfo, error) {
+	return &pages.UserInfo{ID: id, Username: "user"}, nil
+}
+
+func TestAuthenticateApiToken(t *testing.T) {
+	m := NewMiddleware(&application.Application{
+		Logger: slog.New(slog.DiscardHandler),
+		ApiTokenService: &fakeApiTokenService{tokens: map[string]pages.ApiTokenScope{
+			"read-token":  pages.ApiTokenScopeRead,
+			"write-token": pages.ApiTokenScopeWrite,
+		}},
+		UserService: &fakeUserService{},
+	})
+
+	tests := []struct {
+		name            string
+		method          string
+		authorization   string
+		wantStatus      int
+		wantUserId      int32
+		isAuthenticated bool
+	}{
+		{"No header", http.MethodGet, "", http.StatusOK, 0, false},
+		{"Not bearer", http.MethodGet, "Basic dXNlcjpwYXNz", http.StatusUnauthorized, 0, false},
+		{"Bearer without token", http.MethodGet, "Bearer", http.StatusUnauthorized, 0, false},
+		{"Bearer with empty token", http.MethodGet, "Bearer ", http.StatusUnauthorized, 0, false},
+		{"Lowercase bearer", http.MethodGet, "bearer read-token", http.StatusUnauthorized, 0, false},
+		{"Invalid token", http.MethodGet, "Bearer wrong-token", http.StatusUnauthorized, 0, false},
+		{"Revoked token", http.MethodGet, "Bearer revoked-token", http.StatusUnauthorized, 0, false},
+		{"Expired token", http.MethodGet, "Bearer expired-token", http.StatusUnauthorized, 0, false},
+		{"Read token with GET", http.MethodGet, "Bearer read-token", http.StatusOK, 1, true},
+		{"Read token with HEAD", http.MethodHead, "Bearer read-token", http.StatusOK, 1, true},
+		{"Read token with POST", http.MethodPost, "Bearer read-token", http.StatusForbidden, 0, false},
+		{"Read token with PUT", http.MethodPut, "Bearer read-token", http.StatusForbidden, 0, false},
+		{"Read token with PATCH", http.MethodPatch, "Bearer read-token", http.StatusForbidden, 0, false},
+		{"Read token with DELETE", http.MethodDelete, "Bearer read-token", http.StatusForbidden, 0, false},
+		{"Write token with POST", http.MethodPost, "Bearer write-token", http.StatusOK, 1, true},
+		{"Write token with DELETE", http.MethodDelete, "Bearer write-token", http.StatusOK, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			r, err := http.NewRequest(tt.method, "/api/projects", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			var isAuthenticated bool
+			var userId int32
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				isAuthenticated = m.IsAuthenticated(r)
+				if isAuthenticated {
+					userId = m.GetAuthenticatedUserInfo(r).ID
+				}
+				w.Write([]byte("OK"))
+			})
+
+			m.AuthenticateApiToken(next).ServeHTTP(rr, r)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.isAuthenticated, isAuthenticated)
+			assert.Equal(t, tt.wantUserId, userId)
+		})
+	}
+}
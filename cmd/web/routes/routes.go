@@ -63,8 +63,12 @@ func GetRoutes(app *application.Application, m *middleware.Middleware, h *handle
 	mux.Handle("POST /user/verify-email/send-code", requireUnverifiedEmailChain.
 		Append(m.GetIPRateLimiter(10, time.Minute)).ThenFunc(h.User.UserVerifyEmailResendCodePost))
 
+	mux.Handle("GET /shared/{token}", dynamicChain.ThenFunc(h.HardwareSimulator))
+	mux.Handle("GET /api/shared/{token}", apiDynamicChain.ThenFunc(h.Project.HandleGetSharedProject))
+	mux.Handle("GET /api/curriculum", apiDynamicChain.ThenFunc(h.Project.HandleGetCurriculum))
+
 	protectedChain := dynamicChain.Append(m.RequireAuthentication)
-	apiProtectedChain := apiDynamicChain.Append(m.RequireAuthentication)
+	apiProtectedChain := apiDynamicChain.Append(m.AuthenticateApiToken, m.RequireAuthentication)
 
 	mux.Handle("POST /user/logout", protectedChain.ThenFunc(h.User.UserLogoutPost))
 	mux.Handle("GET /user/settings", protectedChain.ThenFunc(h.User.UserSettings))
@@ -83,11 +87,45 @@ func GetRoutes(app *application.Application, m *middleware.Middleware, h *handle
 	mux.Handle("DELETE /api/projects/{id}", apiProtectedChain.ThenFunc(h.Project.HandleDeleteProject))
 	mux.Handle("PATCH /api/projects/{id}", apiProtectedChain.ThenFunc(h.Project.HandleUpdateProject))
 	mux.Handle("POST /api/projects", apiProtectedChain.ThenFunc(h.Project.HandleCreateProject))
+	mux.Handle("GET /api/projects/{id}/export", apiProtectedChain.ThenFunc(h.Project.HandleExportProject))
+	mux.Handle("POST /api/projects/import", apiProtectedChain.ThenFunc(h.Project.HandleImportProject))
+	mux.Handle("POST /api/projects/{id}/duplicate", apiProtectedChain.ThenFunc(h.Project.HandleDuplicateProject))
+	mux.Handle("POST /api/projects/{id}/shares", apiProtectedChain.ThenFunc(h.Project.HandleCreateProjectShare))
+	mux.Handle("GET /api/projects/{id}/shares", apiProtectedChain.ThenFunc(h.Project.HandleGetProjectShares))
+	mux.Handle("DELETE /api/projects/{id}/shares/{shareId}", apiProtectedChain.ThenFunc(h.Project.HandleDeleteProjectShare))
+	mux.Handle("POST /api/projects/{id}/seed", apiProtectedChain.ThenFunc(h.Project.HandleSeedProject))
+	mux.Handle("GET /api/projects/{id}/progress", apiProtectedChain.ThenFunc(h.Project.HandleGetProjectProgress))
 
 	mux.Handle("POST /api/projects/{projectId}/chips", apiProtectedChain.ThenFunc(h.Chip.HandleCreateChip))
 	mux.Handle("GET /api/projects/{projectId}/chips", apiProtectedChain.ThenFunc(h.Chip.HandleGetChips))
 	mux.Handle("DELETE /api/projects/{projectId}/chips/{chipId}", apiProtectedChain.ThenFunc(h.Chip.HandleDeleteChip))
 	mux.Handle("PATCH  /api/projects/{projectId}/chips/{chipId}", apiProtectedChain.ThenFunc(h.Chip.HandleUpdateChip))
+	mux.Handle("GET /api/projects/{projectId}/chips/{chipId}/stats", apiProtectedChain.ThenFunc(h.Chip.HandleGetChipStats))
+	mux.Handle("GET /api/projects/{projectId}/chips/{chipId}/schematic", apiProtectedChain.ThenFunc(h.Chip.HandleGetChipSchematic))
+	mux.Handle("POST /api/projects/{projectId}/chips/{chipId}/simulate", apiProtectedChain.ThenFunc(h.Chip.HandleSimulateChip))
+	mux.Handle("POST /api/projects/{projectId}/chips/{chipId}/test", apiProtectedChain.ThenFunc(h.Chip.HandleTestChip))
+	mux.Handle("GET /api/projects/{projectId}/chips/{chipId}/revisions", apiProtectedChain.ThenFunc(h.Chip.HandleGetChipRevisions))
+	mux.Handle("GET /api/projects/{projectId}/chips/{chipId}/revisions/diff", apiProtectedChain.ThenFunc(h.Chip.HandleDiffChipRevisions))
+	mux.Handle("GET /api/projects/{projectId}/chips/{chipId}/revisions/{revisionId}", apiProtectedChain.ThenFunc(h.Chip.HandleGetChipRevision))
+	mux.Handle("POST /api/projects/{projectId}/chips/{chipId}/revisions/{revisionId}/restore", apiProtectedChain.ThenFunc(h.Chip.HandleRestoreChipRevision))
+	mux.Handle("GET /api/projects/{projectId}/verilog", apiProtectedChain.ThenFunc(h.Chip.HandleDownloadVerilog))
+
+	mux.Handle("POST /api/classrooms", apiProtectedChain.ThenFunc(h.Classroom.HandleCreateClassroom))
+	mux.Handle("GET /api/classrooms", apiProtectedChain.ThenFunc(h.Classroom.HandleGetClassrooms))
+	mux.Handle("POST /api/classrooms/join", apiProtectedChain.ThenFunc(h.Classroom.HandleJoinClassroom))
+	mux.Handle("GET /api/classrooms/{classroomId}", apiProtectedChain.ThenFunc(h.Classroom.HandleGetClassroom))
+	mux.Handle("GET /api/classrooms/{classroomId}/members", apiProtectedChain.ThenFunc(h.Classroom.HandleGetClassroomMembers))
+	mux.Handle("PATCH /api/classrooms/{classroomId}/members/{userId}", apiProtectedChain.ThenFunc(h.Classroom.HandleUpdateClassroomMember))
+	mux.Handle("POST /api/classrooms/{classroomId}/assignments", apiProtectedChain.ThenFunc(h.Classroom.HandleCreateAssignment))
+	mux.Handle("GET /api/classrooms/{classroomId}/assignments", apiProtectedChain.ThenFunc(h.Classroom.HandleGetAssignments))
+	mux.Handle("GET /api/classrooms/{classroomId}/assignments/{assignmentId}/submissions", apiProtectedChain.ThenFunc(h.Classroom.HandleGetSubmissions))
+	mux.Handle("GET /api/classrooms/{classroomId}/assignments/{assignmentId}/submissions/{userId}", apiProtectedChain.ThenFunc(h.Classroom.HandleGetSubmission))
+	mux.Handle("POST /api/classrooms/{classroomId}/assignments/{assignmentId}/tests", apiProtectedChain.ThenFunc(h.Classroom.HandleCreateAssignmentTest))
+	mux.Handle("GET /api/classrooms/{classroomId}/assignments/{assignmentId}/tests", apiProtectedChain.ThenFunc(h.Classroom.HandleGetAssignmentTests))
+	mux.Handle("DELETE /api/classrooms/{classroomId}/assignments/{assignmentId}/tests/{testId}", apiProtectedChain.ThenFunc(h.Classroom.HandleDeleteAssignmentTest))
+	mux.Handle("POST /api/classrooms/{classroomId}/assignments/{assignmentId}/grade", apiProtectedChain.ThenFunc(h.Classroom.HandleGradeAssignment))
+	mux.Handle("GET /api/classrooms/{classroomId}/assignments/{assignmentId}/submissions/{userId}/grade", apiProtectedChain.ThenFunc(h.Classroom.HandleGetGrade))
+	mux.Handle("GET /api/classrooms/{classroomId}/assignments/{assignmentId}/gradebook", apiProtectedChain.ThenFunc(h.Classroom.HandleGetGradebook))
 
 	mux.Handle("GET /projects", protectedChain.ThenFunc(h.Projects))
 
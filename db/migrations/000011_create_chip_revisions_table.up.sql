@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS chip_revisions (
+    id SERIAL PRIMARY KEY,
+    chip_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    hdl TEXT,
+    created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_chip_id FOREIGN KEY (chip_id) REFERENCES chips (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS chip_revisions_chip_id_index ON chip_revisions (chip_id, id);
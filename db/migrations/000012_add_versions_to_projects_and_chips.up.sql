@@ -0,0 +1,2 @@
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE chips ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,67 @@
+-- generate_unique_title returns a title for a project of the user that none of
+-- its projects has. Without a label it is the title itself or "title (n)", with
+-- a label "title (label)" or "title (label n)", e.g. "Adder (copy 2)".
+CREATE OR REPLACE FUNCTION generate_unique_title(p_user_id INT, p_title TEXT, p_label TEXT DEFAULT NULL)
+RETURNS TEXT AS $$
+DECLARE
+    new_title TEXT := CASE WHEN p_label IS NULL THEN p_title ELSE p_title || ' (' || p_label || ')' END;
+    counter INT := 1;
+BEGIN
+    -- loop until we find a title that doesn't exist for this user
+    WHILE EXISTS (
+        SELECT 1 FROM projects
+        WHERE projects.user_id = p_user_id
+          AND projects.title = new_title
+    ) LOOP
+        counter := counter + 1;
+        new_title := CASE
+            WHEN p_label IS NULL THEN p_title || ' (' || counter || ')'
+            ELSE p_title || ' (' || p_label || ' ' || counter || ')'
+        END;
+    END LOOP;
+
+    RETURN new_title;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION insert_default_chips()
+RETURNS TRIGGER AS $$
+BEGIN
+  -- projects that are copied or imported get their chips from elsewhere
+  IF current_setting('app.skip_default_chips', true) = 'on' THEN
+    RETURN NEW;
+  END IF;
+
+  INSERT INTO chips (project_id, name, hdl)
+  VALUES (
+    NEW.id,
+    'NotChip',
+    '// A custom Not chip
+CHIP NotChip {
+    IN in;
+    OUT out;
+
+    PARTS:
+    Nand(a = in, b = in, out = out);
+}'
+  );
+
+  INSERT INTO chips (project_id, name, hdl)
+  VALUES (
+    NEW.id,
+    'AndChip',
+    '// A starter AndChip which uses a custom Not chip (NotChip)
+// and a built-in Nand gate to implement the AND function.
+CHIP AndChip {
+    IN a, b;
+    OUT out;
+
+    PARTS:
+    Nand(a = a, b = b, out = nandOut);
+    NotChip(in = nandOut, out = out);
+}'
+  );
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS project_shares (
+    id SERIAL PRIMARY KEY,
+    project_id INTEGER NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    expires TIMESTAMPTZ,
+    created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT project_shares_unique_constraint_token UNIQUE (token),
+    CONSTRAINT fk_project_id FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS project_shares_project_id_index ON project_shares (project_id);
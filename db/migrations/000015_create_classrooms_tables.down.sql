@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS assignment_submissions;
+DROP TABLE IF EXISTS assignments;
+DROP TABLE IF EXISTS classroom_members;
+DROP TABLE IF EXISTS classrooms;
@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS classrooms (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    invite_code VARCHAR(32) NOT NULL,
+    created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT classrooms_unique_constraint_invite_code UNIQUE (invite_code)
+);
+
+CREATE TABLE IF NOT EXISTS classroom_members (
+    classroom_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    joined TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (classroom_id, user_id),
+    CONSTRAINT classroom_members_check_role CHECK (role IN ('teacher', 'student')),
+    CONSTRAINT fk_classroom_id FOREIGN KEY (classroom_id) REFERENCES classrooms (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS classroom_members_user_id_index ON classroom_members (user_id);
+
+CREATE TABLE IF NOT EXISTS assignments (
+    id SERIAL PRIMARY KEY,
+    classroom_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    starter_project_id INTEGER,
+    due TIMESTAMPTZ,
+    created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_classroom_id FOREIGN KEY (classroom_id) REFERENCES classrooms (id) ON DELETE CASCADE,
+    CONSTRAINT fk_starter_project_id FOREIGN KEY (starter_project_id) REFERENCES projects (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS assignments_classroom_id_index ON assignments (classroom_id, id);
+
+-- the project of a student for an assignment, a copy of the starter project
+CREATE TABLE IF NOT EXISTS assignment_submissions (
+    assignment_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (assignment_id, user_id),
+    CONSTRAINT assignment_submissions_unique_constraint_project_id UNIQUE (project_id),
+    CONSTRAINT fk_assignment_id FOREIGN KEY (assignment_id) REFERENCES assignments (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_project_id FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+
+-- the projects that students start for assignments are titled with
+-- generate_unique_title, which 000013 defines together with the copy titles
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS grading_results;
+DROP TABLE IF EXISTS grading_runs;
+DROP TABLE IF EXISTS assignment_tests;
@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS assignment_tests (
+    id SERIAL PRIMARY KEY,
+    assignment_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    chip VARCHAR(100) NOT NULL,
+    script TEXT NOT NULL,
+    compare TEXT NOT NULL,
+    created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT assignment_tests_unique_constraint_assignment_id_name UNIQUE (assignment_id, name),
+    CONSTRAINT fk_assignment_id FOREIGN KEY (assignment_id) REFERENCES assignments (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS grading_runs (
+    id SERIAL PRIMARY KEY,
+    assignment_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'queued',
+    error TEXT,
+    passed INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished TIMESTAMPTZ,
+    CONSTRAINT grading_runs_check_status CHECK (status IN ('queued', 'running', 'done', 'failed')),
+    CONSTRAINT fk_assignment_id FOREIGN KEY (assignment_id) REFERENCES assignments (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_project_id FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS grading_runs_assignment_id_user_id_index ON grading_runs (assignment_id, user_id);
+
+-- a submission is queued at most once, grading it again is only queued
+-- after the queued run has started
+CREATE UNIQUE INDEX IF NOT EXISTS grading_runs_unique_queued ON grading_runs (assignment_id, user_id)
+WHERE status = 'queued';
+
+CREATE TABLE IF NOT EXISTS grading_results (
+    id SERIAL PRIMARY KEY,
+    run_id INTEGER NOT NULL,
+    test_name VARCHAR(100) NOT NULL,
+    passed BOOLEAN NOT NULL,
+    error TEXT,
+    mismatch_line INTEGER,
+    expected TEXT,
+    actual TEXT,
+    CONSTRAINT fk_run_id FOREIGN KEY (run_id) REFERENCES grading_runs (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS grading_results_run_id_index ON grading_results (run_id);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    -- the public part of the token that it is looked up by, the secret part is
+    -- only stored hashed
+    lookup_id VARCHAR(32) NOT NULL,
+    secret_hash TEXT NOT NULL,
+    scope VARCHAR(16) NOT NULL,
+    expires TIMESTAMPTZ,
+    last_used TIMESTAMPTZ,
+    created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT api_tokens_unique_constraint_lookup_id UNIQUE (lookup_id),
+    CONSTRAINT api_tokens_check_scope CHECK (scope IN ('read', 'write')),
+    CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS api_tokens_user_id_index ON api_tokens (user_id);
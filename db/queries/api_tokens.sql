@@ -0,0 +1,25 @@
+-- name: CreateApiToken :one
+INSERT INTO api_tokens (
+    user_id, name, lookup_id, secret_hash, scope, expires
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetApiTokensOfUser :many
+SELECT * FROM api_tokens
+WHERE user_id = $1
+ORDER BY id DESC;
+
+-- name: GetApiTokenByLookupId :one
+SELECT * FROM api_tokens
+WHERE lookup_id = $1
+    AND (expires IS NULL OR expires > NOW());
+
+-- name: UpdateApiTokenLastUsed :exec
+UPDATE api_tokens SET last_used = NOW()
+WHERE id = $1;
+
+-- name: DeleteApiToken :one
+DELETE FROM api_tokens
+WHERE id = $1 AND user_id = $2
+RETURNING *;
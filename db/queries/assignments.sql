@@ -0,0 +1,50 @@
+-- name: CreateAssignment :one
+INSERT INTO assignments (
+    classroom_id, title, description, starter_project_id, due
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetAssignment :one
+SELECT
+    id, classroom_id, title, description, starter_project_id, due, created
+FROM assignments
+WHERE id = $1 AND classroom_id = $2;
+
+-- name: GetAssignmentsByClassroom :many
+SELECT
+    id, classroom_id, title, description, starter_project_id, due, created
+FROM assignments
+WHERE classroom_id = $1
+ORDER BY id ASC;
+
+-- name: GetAssignmentsOfStudent :many
+SELECT sqlc.embed(assignments), assignment_submissions.project_id
+FROM assignments
+LEFT JOIN assignment_submissions ON assignment_submissions.assignment_id = assignments.id
+    AND assignment_submissions.user_id = $2
+WHERE assignments.classroom_id = $1
+ORDER BY assignments.id ASC;
+
+-- name: CreateAssignmentSubmission :one
+INSERT INTO assignment_submissions (
+    assignment_id, user_id, project_id
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetAssignmentSubmission :one
+SELECT
+    assignment_id, user_id, project_id, created
+FROM assignment_submissions
+WHERE assignment_id = $1 AND user_id = $2;
+
+-- name: GetAssignmentSubmissions :many
+SELECT
+    assignment_submissions.user_id, users.username, assignment_submissions.project_id,
+    projects.title, projects.updated
+FROM assignment_submissions
+JOIN users ON users.id = assignment_submissions.user_id
+JOIN projects ON projects.id = assignment_submissions.project_id
+WHERE assignment_submissions.assignment_id = $1
+ORDER BY users.username ASC;
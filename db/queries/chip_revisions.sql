@@ -0,0 +1,36 @@
+-- name: CreateChipRevision :one
+INSERT INTO chip_revisions (
+    chip_id, name, hdl
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetChipRevision :one
+SELECT
+    id, chip_id, name, hdl, created
+FROM chip_revisions
+WHERE id = $1 AND chip_id = $2;
+
+-- name: GetLatestChipRevision :one
+SELECT
+    id, chip_id, name, hdl, created
+FROM chip_revisions
+WHERE chip_id = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: GetChipRevisions :many
+SELECT
+    id, chip_id, name, hdl, created
+FROM chip_revisions
+WHERE chip_id = $1
+ORDER BY id DESC;
+
+-- name: DeleteOldChipRevisions :exec
+DELETE FROM chip_revisions cr
+WHERE cr.chip_id = $1 AND cr.id NOT IN (
+    SELECT r.id FROM chip_revisions r
+    WHERE r.chip_id = $1
+    ORDER BY r.id DESC
+    LIMIT sqlc.arg(keep)::integer
+);
@@ -5,26 +5,39 @@ INSERT INTO chips (
     $1, $2
 ) RETURNING *;
 
+-- name: CreateChipWithHdl :one
+INSERT INTO chips (
+    project_id, name, hdl
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
 -- name: GetChip :one
 SELECT
-    id, project_id, name, hdl, created, updated
+    id, project_id, name, hdl, created, updated, version
 FROM chips
 WHERE id = $1 AND project_id = $2;
 
 -- name: GetChipsByProject :many
 SELECT
-    id, project_id, name, hdl, created, updated
+    id, project_id, name, hdl, created, updated, version
 FROM chips
 WHERE project_id = $1
 ORDER BY name ASC;
 
 -- name: UpdateChip :one
 UPDATE chips SET
-    name = $2, hdl = $3, updated = NOW()
-WHERE id = $1
+    name = $2, hdl = $3, updated = NOW(), version = version + 1
+WHERE id = $1 AND version = $4
 RETURNING *;
 
 -- name: DeleteChip :one
 DELETE FROM chips
 WHERE id = $1
 RETURNING *;
+
+-- name: CopyChips :exec
+INSERT INTO chips (project_id, name, hdl)
+SELECT sqlc.arg(to_project_id)::integer, name, hdl
+FROM chips
+WHERE project_id = sqlc.arg(from_project_id)::integer;
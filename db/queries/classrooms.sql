@@ -0,0 +1,46 @@
+-- name: CreateClassroom :one
+INSERT INTO classrooms (
+    name, invite_code
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetClassroomByInviteCode :one
+SELECT
+    id, name, invite_code, created
+FROM classrooms
+WHERE invite_code = $1;
+
+-- name: GetClassroomOfMember :one
+SELECT sqlc.embed(classrooms), classroom_members.role
+FROM classrooms
+JOIN classroom_members ON classroom_members.classroom_id = classrooms.id
+WHERE classrooms.id = $1 AND classroom_members.user_id = $2;
+
+-- name: GetClassroomsOfMember :many
+SELECT sqlc.embed(classrooms), classroom_members.role
+FROM classrooms
+JOIN classroom_members ON classroom_members.classroom_id = classrooms.id
+WHERE classroom_members.user_id = $1
+ORDER BY classrooms.name ASC;
+
+-- name: AddClassroomMember :one
+INSERT INTO classroom_members (
+    classroom_id, user_id, role
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetClassroomMembers :many
+SELECT
+    classroom_members.user_id, users.username, classroom_members.role, classroom_members.joined
+FROM classroom_members
+JOIN users ON users.id = classroom_members.user_id
+WHERE classroom_members.classroom_id = $1
+ORDER BY classroom_members.role DESC, users.username ASC;
+
+-- name: UpdateClassroomMemberRole :one
+UPDATE classroom_members SET
+    role = $3
+WHERE classroom_id = $1 AND user_id = $2
+RETURNING *;
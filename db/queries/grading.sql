@@ -0,0 +1,89 @@
+-- name: CreateAssignmentTest :one
+INSERT INTO assignment_tests (
+    assignment_id, name, chip, script, compare
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetAssignmentTests :many
+SELECT
+    id, assignment_id, name, chip, script, compare, created
+FROM assignment_tests
+WHERE assignment_id = $1
+ORDER BY id ASC;
+
+-- name: DeleteAssignmentTest :one
+DELETE FROM assignment_tests
+WHERE id = $1 AND assignment_id = $2
+RETURNING *;
+
+-- name: QueueGradingRuns :many
+INSERT INTO grading_runs (
+    assignment_id, user_id, project_id
+)
+SELECT assignment_id, user_id, project_id
+FROM assignment_submissions
+WHERE assignment_submissions.assignment_id = sqlc.arg(assignment_id)
+    AND (sqlc.narg(user_id)::integer IS NULL OR assignment_submissions.user_id = sqlc.narg(user_id)::integer)
+ON CONFLICT (assignment_id, user_id) WHERE status = 'queued' DO NOTHING
+RETURNING *;
+
+-- name: ClaimGradingRun :one
+UPDATE grading_runs SET
+    status = 'running'
+WHERE id = (
+    SELECT id FROM grading_runs
+    WHERE status = 'queued'
+    ORDER BY id ASC
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RequeueRunningGradingRuns :exec
+UPDATE grading_runs SET
+    status = 'queued'
+WHERE status = 'running';
+
+-- name: FinishGradingRun :one
+UPDATE grading_runs SET
+    status = $2,
+    error = $3,
+    passed = $4,
+    total = $5,
+    finished = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateGradingResult :exec
+INSERT INTO grading_results (
+    run_id, test_name, passed, error, mismatch_line, expected, actual
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: GetLatestGradingRun :one
+SELECT
+    id, assignment_id, user_id, project_id, status, error, passed, total, created, finished
+FROM grading_runs
+WHERE assignment_id = $1 AND user_id = $2
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: GetGradingResults :many
+SELECT
+    id, run_id, test_name, passed, error, mismatch_line, expected, actual
+FROM grading_results
+WHERE run_id = $1
+ORDER BY id ASC;
+
+-- name: GetLatestGradingResultsOfAssignment :many
+SELECT latest.user_id, sqlc.embed(grading_results)
+FROM (
+    SELECT DISTINCT ON (grading_runs.user_id) grading_runs.id, grading_runs.user_id
+    FROM grading_runs
+    WHERE grading_runs.assignment_id = $1 AND grading_runs.status = 'done'
+    ORDER BY grading_runs.user_id, grading_runs.id DESC
+) AS latest
+JOIN grading_results ON grading_results.run_id = latest.id
+ORDER BY grading_results.id ASC;
@@ -0,0 +1,24 @@
+-- name: CreateProjectShare :one
+INSERT INTO project_shares (
+    project_id, token, expires
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetProjectShares :many
+SELECT
+    id, project_id, token, expires, created
+FROM project_shares
+WHERE project_id = $1
+ORDER BY id DESC;
+
+-- name: DeleteProjectShare :one
+DELETE FROM project_shares
+WHERE id = $1 AND project_id = $2
+RETURNING *;
+
+-- name: GetSharedProject :one
+SELECT sqlc.embed(projects) FROM projects
+JOIN project_shares ON project_shares.project_id = projects.id
+WHERE project_shares.token = $1
+    AND (project_shares.expires IS NULL OR project_shares.expires > NOW());
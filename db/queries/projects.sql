@@ -5,22 +5,41 @@ INSERT INTO projects (
     $1, $2, $3
 ) RETURNING *;
 
+-- name: CreateProjectWithUniqueTitle :one
+INSERT INTO projects (
+    user_id, title, description
+) VALUES (
+    $1, generate_unique_title($1, sqlc.arg(title)::text), $2
+) RETURNING *;
+
+-- name: DuplicateProject :one
+INSERT INTO projects (
+    user_id, title, description
+)
+SELECT p.user_id, generate_unique_title(p.user_id, p.title, 'copy'), p.description
+FROM projects p
+WHERE p.user_id = $1 AND p.id = $2
+RETURNING *;
+
+-- name: SkipDefaultChips :exec
+SELECT set_config('app.skip_default_chips', 'on', true);
+
 -- name: GetProject :one
 SELECT
-    id, user_id, title, slug, description, created, updated
+    id, user_id, title, slug, description, created, updated, version
 FROM projects
 WHERE user_id = $1 AND id = $2;
 
 -- name: GetProjectBySlug :one
 SELECT
-    id, user_id, title, slug, description, created, updated
+    id, user_id, title, slug, description, created, updated, version
 FROM projects
 WHERE user_id = $1 AND slug = $2;
 
 -- name: UpdateProject :one
 UPDATE projects SET
-    title = $3, description = $4, updated = NOW()
-WHERE user_id = $1 AND id = $2
+    title = $3, description = $4, updated = NOW(), version = version + 1
+WHERE user_id = $1 AND id = $2 AND version = $5
 RETURNING *;
 
 -- name: DeleteProject :one
@@ -30,7 +49,7 @@ RETURNING *;
 
 -- name: GetPaginatedProjects :many
 SELECT
-    id, user_id, title, slug, description, created, updated
+    id, user_id, title, slug, description, created, updated, version
 FROM projects
 WHERE user_id = $1
 ORDER BY updated DESC
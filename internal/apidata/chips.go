@@ -1,6 +1,10 @@
 package apidata
 
-import "time"
+import (
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/textdiff"
+)
 
 type CreateChipRequest struct {
 	Name *string `json:"name"`
@@ -13,9 +17,54 @@ type Chip struct {
 	Hdl       string    `json:"hdl"`
 	Created   time.Time `json:"created"`
 	Updated   time.Time `json:"updated"`
+	Version   int32     `json:"version"`
 }
 
 type UpdateChipRequest struct {
 	Name *string `json:"name"`
 	Hdl  *string `json:"hdl"`
 }
+
+// SimulateChipRequest drives the chip through the steps in order, the inputs
+// keeping their values from one step to the next until they are set again.
+type SimulateChipRequest struct {
+	Steps []SimulationStep `json:"steps"`
+}
+
+type SimulationStep struct {
+	Inputs map[string]int64 `json:"inputs"`
+	// Action is eval, tick, tock or cycle, a tick followed by a tock. It is eval if empty.
+	Action string `json:"action"`
+}
+
+// SimulationStepResult holds the values of the pins after a step, 16-bit
+// values being signed and narrower ones unsigned.
+type SimulationStepResult struct {
+	Outputs   map[string]int64 `json:"outputs"`
+	Internals map[string]int64 `json:"internals"`
+}
+
+type SimulateChipResponse struct {
+	Steps []SimulationStepResult `json:"steps"`
+}
+
+type TestChipRequest struct {
+	Script  string `json:"script"`
+	Compare string `json:"compare"`
+}
+
+type ChipRevision struct {
+	ID      int32     `json:"id"`
+	ChipID  int32     `json:"chipId"`
+	Name    string    `json:"name"`
+	Hdl     string    `json:"hdl"`
+	Created time.Time `json:"created"`
+}
+
+// ChipRevisionDiff is the line diff between two revisions of a chip, a
+// revision id of 0 standing for the current content of the chip.
+type ChipRevisionDiff struct {
+	From  int32           `json:"from"`
+	To    int32           `json:"to"`
+	Lines []textdiff.Line `json:"lines"`
+}
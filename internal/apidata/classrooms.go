@@ -0,0 +1,78 @@
+package apidata
+
+import "time"
+
+// The roles of the members of a classroom. Teachers manage the classroom and
+// its assignments and read the submissions of the students.
+const (
+	ClassroomRoleTeacher = "teacher"
+	ClassroomRoleStudent = "student"
+)
+
+type CreateClassroomRequest struct {
+	Name string `json:"name"`
+}
+
+type JoinClassroomRequest struct {
+	InviteCode string `json:"inviteCode"`
+}
+
+type Classroom struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	// Role is the role of the user in the classroom.
+	Role string `json:"role"`
+	// InviteCode lets students join the classroom, it is only shown to teachers.
+	InviteCode string    `json:"inviteCode,omitempty"`
+	Created    time.Time `json:"created"`
+}
+
+type UpdateClassroomMemberRequest struct {
+	Role string `json:"role"`
+}
+
+type ClassroomMember struct {
+	UserID   int32     `json:"userId"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	Joined   time.Time `json:"joined"`
+}
+
+type CreateAssignmentRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// StarterProjectID is the project of the teacher that every student gets
+	// a copy of. Students start from an empty project if it is nil.
+	StarterProjectID *int32     `json:"starterProjectId"`
+	Due              *time.Time `json:"due"`
+}
+
+type Assignment struct {
+	ID               int32      `json:"id"`
+	ClassroomID      int32      `json:"classroomId"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	StarterProjectID *int32     `json:"starterProjectId,omitempty"`
+	Due              *time.Time `json:"due"`
+	Created          time.Time  `json:"created"`
+	// ProjectID is the project of the student for the assignment, it is nil for teachers.
+	ProjectID *int32 `json:"projectId,omitempty"`
+}
+
+// Submission is the project of a student for an assignment.
+type Submission struct {
+	AssignmentID int32     `json:"assignmentId"`
+	UserID       int32     `json:"userId"`
+	Username     string    `json:"username"`
+	ProjectID    int32     `json:"projectId"`
+	Title        string    `json:"title"`
+	Updated      time.Time `json:"updated"`
+}
+
+// SubmissionDetail is a submission with the chips of the project, for
+// teachers to read but not edit.
+type SubmissionDetail struct {
+	Submission
+	Description string       `json:"description"`
+	Chips       []SharedChip `json:"chips"`
+}
@@ -0,0 +1,56 @@
+package apidata
+
+import "github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testscript"
+
+// The statuses of a chip of the curriculum in a project. A chip is untested if
+// the time for checking the progress ran out before its test could run.
+const (
+	ChipProgressPassed   = "passed"
+	ChipProgressFailed   = "failed"
+	ChipProgressMissing  = "missing"
+	ChipProgressUntested = "untested"
+)
+
+type CurriculumProject struct {
+	Number int              `json:"number"`
+	Title  string           `json:"title"`
+	Chips  []CurriculumChip `json:"chips"`
+}
+
+// CurriculumChip is a chip of the course, with the stub students start from
+// and the reference test, the test script and its compare file.
+type CurriculumChip struct {
+	Name    string `json:"name"`
+	Spec    string `json:"spec"`
+	Stub    string `json:"stub"`
+	Test    string `json:"test"`
+	Compare string `json:"compare"`
+}
+
+type SeedProjectRequest struct {
+	// Project is the number of the project of the course to add the chips of.
+	Project int `json:"project"`
+}
+
+type ProjectProgress struct {
+	Passed   int                         `json:"passed"`
+	Total    int                         `json:"total"`
+	Projects []CurriculumProjectProgress `json:"projects"`
+}
+
+type CurriculumProjectProgress struct {
+	Number int            `json:"number"`
+	Title  string         `json:"title"`
+	Passed int            `json:"passed"`
+	Total  int            `json:"total"`
+	Chips  []ChipProgress `json:"chips"`
+}
+
+type ChipProgress struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// ChipID is the chip of the project, nil if it is missing.
+	ChipID   *int32               `json:"chipId,omitempty"`
+	Error    string               `json:"error,omitempty"`
+	Mismatch *testscript.Mismatch `json:"mismatch,omitempty"`
+}
@@ -0,0 +1,58 @@
+package apidata
+
+import (
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testscript"
+)
+
+// The statuses of a grading run. Runs are queued and graded in the background,
+// a run fails if it could not be graded, not if the chips fail the tests.
+const (
+	GradingStatusQueued  = "queued"
+	GradingStatusRunning = "running"
+	GradingStatusDone    = "done"
+	GradingStatusFailed  = "failed"
+)
+
+type CreateAssignmentTestRequest struct {
+	Name    string `json:"name"`
+	Chip    string `json:"chip"`
+	Script  string `json:"script"`
+	Compare string `json:"compare"`
+}
+
+// AssignmentTest is a reference test script of an assignment, with the
+// compare file the output of the chips of the students has to match.
+type AssignmentTest struct {
+	ID           int32     `json:"id"`
+	AssignmentID int32     `json:"assignmentId"`
+	Name         string    `json:"name"`
+	Chip         string    `json:"chip"`
+	Script       string    `json:"script"`
+	Compare      string    `json:"compare"`
+	Created      time.Time `json:"created"`
+}
+
+type GradingRun struct {
+	ID           int32           `json:"id"`
+	AssignmentID int32           `json:"assignmentId"`
+	UserID       int32           `json:"userId"`
+	ProjectID    int32           `json:"projectId"`
+	Status       string          `json:"status"`
+	Error        string          `json:"error,omitempty"`
+	Passed       int32           `json:"passed"`
+	Total        int32           `json:"total"`
+	Created      time.Time       `json:"created"`
+	Finished     *time.Time      `json:"finished"`
+	Results      []GradingResult `json:"results,omitempty"`
+}
+
+// GradingResult is the result of a test of a grading run, with the first line
+// of the output that differs from the compare file if it failed.
+type GradingResult struct {
+	TestName string               `json:"testName"`
+	Passed   bool                 `json:"passed"`
+	Error    string               `json:"error,omitempty"`
+	Mismatch *testscript.Mismatch `json:"mismatch,omitempty"`
+}
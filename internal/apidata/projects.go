@@ -20,6 +20,7 @@ type Project struct {
 	Description string    `json:"description"`
 	Created     time.Time `json:"created"`
 	Updated     time.Time `json:"updated"`
+	Version     int32     `json:"version"`
 }
 
 type ProjectsResponse struct {
@@ -29,3 +30,30 @@ type ProjectsResponse struct {
 	PageSize   int32     `json:"pageSize"`
 	TotalPages int32     `json:"totalPages"`
 }
+
+type CreateProjectShareRequest struct {
+	// Expires is when the link stops working, it works until revoked if nil.
+	Expires *time.Time `json:"expires"`
+}
+
+type ProjectShare struct {
+	ID        int32      `json:"id"`
+	ProjectID int32      `json:"projectId"`
+	Token     string     `json:"token"`
+	Expires   *time.Time `json:"expires"`
+	Created   time.Time  `json:"created"`
+}
+
+// SharedProject is what a share link shows of a project, without anything
+// about its owner.
+type SharedProject struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Updated     time.Time    `json:"updated"`
+	Chips       []SharedChip `json:"chips"`
+}
+
+type SharedChip struct {
+	Name string `json:"name"`
+	Hdl  string `json:"hdl"`
+}
@@ -0,0 +1,48 @@
+package crypto
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	apiTokenPrefix         = "n2t"
+	apiTokenLookupIdLength = 12
+	apiTokenSecretLength   = 32
+)
+
+// DefaultApiTokenHashParams are the parameters the secrets of API tokens are
+// hashed with. They are random and long unlike passwords, so they are hashed
+// with less memory to keep the authentication of every API request cheap.
+var DefaultApiTokenHashParams = PasswordHashParams{
+	memory:      19 * 1024,
+	iterations:  2,
+	parallelism: 1,
+	saltLength:  16,
+	keyLength:   32,
+}
+
+// GenerateApiToken returns a new personal API token in the format
+// n2t_<lookup id>_<secret>. The lookup id is stored as is to find the token,
+// the secret only hashed.
+func GenerateApiToken() (token, lookupId, secret string) {
+	lookupId = GenerateRandomString(apiTokenLookupIdLength)
+	secret = GenerateRandomString(apiTokenSecretLength)
+	token = fmt.Sprintf("%s_%s_%s", apiTokenPrefix, lookupId, secret)
+	return token, lookupId, secret
+}
+
+// ParseApiToken splits the token into its lookup id and secret.
+func ParseApiToken(token string) (lookupId, secret string, ok bool) {
+	parts := strings.Split(token, "_")
+	if len(parts) != 3 || parts[0] != apiTokenPrefix {
+		return "", "", false
+	}
+
+	lookupId, secret = parts[1], parts[2]
+	if len(lookupId) != apiTokenLookupIdLength || len(secret) != apiTokenSecretLength {
+		return "", "", false
+	}
+
+	return lookupId, secret, true
+}
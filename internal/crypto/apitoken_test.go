@@ -0,0 +1,77 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateApiToken(t *testing.T) {
+	token, lookupId, secret := GenerateApiToken()
+	if !strings.HasPrefix(token, "n2t_") {
+		t.Errorf("token does not start with the prefix. got=%q", token)
+	}
+
+	parsedLookupId, parsedSecret, ok := ParseApiToken(token)
+	if !ok {
+		t.Fatalf("generated token could not be parsed. got=%q", token)
+	}
+	if parsedLookupId != lookupId {
+		t.Errorf("lookup id is invalid. got=%q, want=%q", parsedLookupId, lookupId)
+	}
+	if parsedSecret != secret {
+		t.Errorf("secret is invalid. got=%q, want=%q", parsedSecret, secret)
+	}
+
+	otherToken, _, _ := GenerateApiToken()
+	if otherToken == token {
+		t.Errorf("two generated tokens are the same. got=%q", token)
+	}
+}
+
+func TestParseApiToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Empty", ""},
+		{"Wrong prefix", "abc_abcdefghijkl_abcdefghijklmnopqrstuvwxyz012345"},
+		{"Short lookup id", "n2t_abc_abcdefghijklmnopqrstuvwxyz012345"},
+		{"Short secret", "n2t_abcdefghijkl_abc"},
+		{"Too many parts", "n2t_abcdefghijkl_abcdefghijklmnopqrstuvwxyz012345_abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, ok := ParseApiToken(tt.token)
+			if ok {
+				t.Errorf("invalid token was parsed. got=%q", tt.token)
+			}
+		})
+	}
+}
+
+func TestApiTokenHash(t *testing.T) {
+	var hasher PasswordHasher
+	_, _, secret := GenerateApiToken()
+
+	hash, err := hasher.GenerateFromPassword(secret, DefaultApiTokenHashParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := hasher.ComparePasswordAndHash(secret, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("secret does not match its hash")
+	}
+
+	ok, err = hasher.ComparePasswordAndHash(strings.ToUpper(secret), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Errorf("wrong secret matches the hash")
+	}
+}
@@ -0,0 +1,124 @@
+// Package curriculum holds the chips of the nand2tetris course, with the stub
+// every student starts from and the reference test that the chip has to pass.
+//
+// The files of a chip are in projects/<project>/ like in the software suite
+// of the course: the stub in <Chip>.hdl, with the specification of the chip in
+// its leading comment, the test script in <Chip>.tst and its expected output in
+// <Chip>.cmp. The compare files are generated from the built-in chips of the
+// simulator, see curriculum_test.go.
+package curriculum
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+//go:embed projects
+var files embed.FS
+
+type Chip struct {
+	Name    string
+	Project int
+	// Spec is the specification of the chip, the leading comment of the stub.
+	Spec    string
+	Stub    string
+	Test    string
+	Compare string
+}
+
+type Project struct {
+	Number int
+	Title  string
+	Chips  []Chip
+}
+
+// catalogue lists the projects of the course and their chips in the order
+// they are built. Project 4 is about the machine language and has no chips,
+// and of project 5 only the CPU is included, as the Memory and the Computer
+// are built from the Screen, Keyboard and ROM32K chips, which the simulator
+// does not have.
+var catalogue = []struct {
+	number int
+	title  string
+	chips  []string
+}{
+	{1, "Boolean Logic", []string{
+		"Not", "And", "Or", "Xor", "Mux", "DMux",
+		"Not16", "And16", "Or16", "Mux16", "Or8Way",
+		"Mux4Way16", "Mux8Way16", "DMux4Way", "DMux8Way",
+	}},
+	{2, "Boolean Arithmetic", []string{"HalfAdder", "FullAdder", "Add16", "Inc16", "ALU"}},
+	{3, "Memory", []string{"Bit", "Register", "RAM8", "RAM64", "RAM512", "RAM4K", "RAM16K", "PC"}},
+	{5, "Computer Architecture", []string{"CPU"}},
+}
+
+var projects = sync.OnceValue(func() []Project {
+	projects := make([]Project, 0, len(catalogue))
+	for _, p := range catalogue {
+		project := Project{Number: p.number, Title: p.title}
+		for _, name := range p.chips {
+			project.Chips = append(project.Chips, loadChip(p.number, name))
+		}
+		projects = append(projects, project)
+	}
+	return projects
+})
+
+// Projects returns the projects of the course in order.
+func Projects() []Project {
+	return projects()
+}
+
+// FindProject returns the project with the number.
+func FindProject(number int) (*Project, bool) {
+	for _, project := range projects() {
+		if project.Number == number {
+			return &project, true
+		}
+	}
+	return nil, false
+}
+
+// loadChip reads the files of the chip, panicking if one of them is missing,
+// as they are embedded in the binary.
+func loadChip(project int, name string) Chip {
+	dir := path.Join("projects", fmt.Sprintf("%02d", project))
+	read := func(ext string) string {
+		content, err := files.ReadFile(path.Join(dir, name+ext))
+		if err != nil {
+			panic(err)
+		}
+		return string(content)
+	}
+
+	stub := read(".hdl")
+	return Chip{
+		Name:    name,
+		Project: project,
+		Spec:    spec(stub),
+		Stub:    stub,
+		Test:    read(".tst"),
+		Compare: read(".cmp"),
+	}
+}
+
+// spec returns the text of the /** */ comment of the stub, without the
+// leading asterisks.
+func spec(stub string) string {
+	start := strings.Index(stub, "/**")
+	end := strings.Index(stub, "*/")
+	if start == -1 || end < start {
+		return ""
+	}
+
+	var lines []string
+	for line := range strings.SplitSeq(stub[start+len("/**"):end], "\n") {
+		line = strings.TrimPrefix(line, " *")
+		line = strings.TrimPrefix(line, " ")
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,157 @@
+package curriculum
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/simulator"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testscript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "write the compare files from the output of the reference chips")
+
+// referenceHdl returns the HDL of the reference implementation of the chip:
+// the one in testdata, or the stub with the built-in chip of the same name as
+// its only part.
+func referenceHdl(t *testing.T, name string, stub string) string {
+	t.Helper()
+
+	hdl, err := os.ReadFile(filepath.Join("testdata", name+".hdl"))
+	if err == nil {
+		return string(hdl)
+	}
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	builtin, ok := chips.NewDefaultRegistry().Lookup(name)
+	require.True(t, ok, "%s has no reference implementation", name)
+
+	signature := builtin.Signature()
+	var pins []string
+	for pin := range signature.Inputs {
+		pins = append(pins, pin)
+	}
+	for pin := range signature.Outputs {
+		pins = append(pins, pin)
+	}
+	slices.Sort(pins)
+
+	connections := make([]string, 0, len(pins))
+	for _, pin := range pins {
+		connections = append(connections, fmt.Sprintf("%s = %s", pin, pin))
+	}
+	part := fmt.Sprintf("%s(%s);", name, strings.Join(connections, ", "))
+	return strings.Replace(stub, "//// Replace this comment with your code.", part, 1)
+}
+
+// TestReferenceChips checks that the reference chips pass the tests, which
+// with -update writes the compare files instead.
+func TestReferenceChips(t *testing.T) {
+	references := map[string]string{}
+	for _, project := range catalogue {
+		dir := filepath.Join("projects", fmt.Sprintf("%02d", project.number))
+		for _, name := range project.chips {
+			stub, err := os.ReadFile(filepath.Join(dir, name+".hdl"))
+			require.NoError(t, err)
+			references[name] = referenceHdl(t, name, string(stub))
+		}
+	}
+
+	for _, project := range catalogue {
+		dir := filepath.Join("projects", fmt.Sprintf("%02d", project.number))
+		for _, name := range project.chips {
+			t.Run(name, func(t *testing.T) {
+				src, err := os.ReadFile(filepath.Join(dir, name+".tst"))
+				require.NoError(t, err)
+				script, err := testscript.Parse(string(src))
+				require.NoError(t, err)
+
+				compareFile := filepath.Join(dir, name+".cmp")
+				var compare []byte
+				if !*update {
+					compare, err = os.ReadFile(compareFile)
+					require.NoError(t, err)
+				}
+
+				hs := simulator.New(chips.NewDefaultRegistry())
+				hs.SetChipHDLs(references)
+				result, err := testscript.Run(context.Background(), hs, script, testscript.Options{
+					Compare: string(compare),
+				})
+				require.NoError(t, err)
+
+				if *update {
+					require.NoError(t, os.WriteFile(compareFile, []byte(result.Output), 0o644))
+					return
+				}
+				assert.True(t, result.Passed, "mismatch: %+v", result.Mismatch)
+			})
+		}
+	}
+}
+
+func TestProjects(t *testing.T) {
+	projects := Projects()
+	require.Len(t, projects, len(catalogue))
+
+	names := map[string]bool{}
+	for _, project := range projects {
+		assert.NotEmpty(t, project.Chips)
+		for _, chip := range project.Chips {
+			assert.False(t, names[chip.Name], "%s is in more than one project", chip.Name)
+			names[chip.Name] = true
+			assert.Equal(t, project.Number, chip.Project)
+			assert.NotEmpty(t, chip.Spec, chip.Name)
+			assert.NotEmpty(t, chip.Compare, chip.Name)
+
+			ts, err := lexer.New(chip.Stub).Tokenize()
+			require.NoError(t, err, chip.Name)
+			chipName, err := parser.New(ts).ParseChipHeader()
+			require.NoError(t, err, chip.Name)
+			assert.Equal(t, chip.Name, chipName.Name)
+		}
+	}
+
+	t.Run("Spec", func(t *testing.T) {
+		project, ok := FindProject(1)
+		require.True(t, ok)
+		assert.Equal(t, "Not gate:\nif (in) out = 0, else out = 1", project.Chips[0].Spec)
+	})
+
+	t.Run("Unknown project", func(t *testing.T) {
+		_, ok := FindProject(4)
+		assert.False(t, ok)
+	})
+}
+
+// TestStubsFail checks that the tests are not passed by the stubs, which have
+// no parts.
+func TestStubsFail(t *testing.T) {
+	for _, project := range Projects() {
+		for _, chip := range project.Chips {
+			t.Run(chip.Name, func(t *testing.T) {
+				script, err := testscript.Parse(chip.Test)
+				require.NoError(t, err)
+
+				hs := simulator.New(chips.NewDefaultRegistry())
+				hs.SetChipHDLs(map[string]string{chip.Name: chip.Stub})
+				result, err := testscript.Run(context.Background(), hs, script, testscript.Options{
+					Compare: chip.Compare,
+				})
+				if err == nil {
+					assert.False(t, result.Passed)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,27 @@
+load RAM512.hdl,
+output-file RAM512.out,
+compare-to RAM512.cmp,
+output-list time%S1.4.1 in%D1.6.1 load%B2.1.2 address%D1.5.1 out%D1.6.1;
+
+set in 1111, set load 1, set address 0, tick, output;
+tock, output;
+set in 2222, set load 1, set address 73, tick, output;
+tock, output;
+set in 3333, set load 1, set address 255, tick, output;
+tock, output;
+set in 4444, set load 1, set address 511, tick, output;
+tock, output;
+set in 5555, set load 1, set address 300, tick, output;
+tock, output;
+set in 0, set load 0, set address 0, tick, output;
+tock, output;
+set in 0, set load 0, set address 73, tick, output;
+tock, output;
+set in 0, set load 0, set address 255, tick, output;
+tock, output;
+set in 0, set load 0, set address 511, tick, output;
+tock, output;
+set in 0, set load 0, set address 300, tick, output;
+tock, output;
+set in -1, set load 0, set address 0, tick, output;
+tock, output;
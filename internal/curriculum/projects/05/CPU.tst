@@ -0,0 +1,73 @@
+load CPU.hdl,
+output-file CPU.out,
+compare-to CPU.cmp,
+output-list time%S0.4.0 inM%D0.6.0 instruction%B0.16.0 reset%B2.1.2 outM%D1.6.0 writeM%B3.1.3 addressM%D0.5.0 pc%D0.5.0;
+
+set inM 0, set instruction %B0011000000111001, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110110000010000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B0101101110100000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110000111010000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B0000001111101000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100001000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B0000001111101001, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001110011000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B0000001111101000, set reset 0, tick, output;
+tock, output;
+set inM 11111, set instruction %B1111010011010000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B0000000000001110, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000100, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B0000001111100111, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110110111100000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100001000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B0000000000010101, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110011111000010, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B0000000000000010, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110000010010000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B0000001111101000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110111010010000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000001, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000010, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000011, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000100, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000101, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000110, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000111, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110101010010000, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000001, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000010, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000101, set reset 0, tick, output;
+tock, output;
+set inM 0, set instruction %B1110001100000111, set reset 1, tick, output;
+tock, output;
+set inM 0, set instruction %B0111111111111111, set reset 0, tick, output;
+tock, output;
@@ -0,0 +1,160 @@
+// Package grader grades the chips of a project by running reference test
+// scripts on them and comparing their output with the reference compare files.
+package grader
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/simulator"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testscript"
+)
+
+// Test is a reference test script with the compare file its output has to match.
+type Test struct {
+	Name string
+	// Chip is loaded before running the script, load commands can replace it.
+	Chip    string
+	Script  string
+	Compare string
+}
+
+// Result is the result of a test. A test that could not be run, because the
+// chip is missing or does not compile for example, fails with an Error.
+type Result struct {
+	Name     string
+	Passed   bool
+	Error    string
+	Mismatch *testscript.Mismatch
+}
+
+type Options struct {
+	// MaxSteps limits the number of commands each test script runs, 0 meaning no limit.
+	MaxSteps int
+	// Timeout limits how long each test runs, 0 meaning no limit.
+	Timeout time.Duration
+}
+
+// Grade runs the tests on the chips, given by their HDL by name, and returns
+// the result of each of them. The tests that have not run when the context is
+// done fail.
+func Grade(ctx context.Context, hdls map[string]string, tests []Test, options Options) []Result {
+	results := make([]Result, 0, len(tests))
+	for _, test := range tests {
+		results = append(results, runTest(ctx, hdls, test, options))
+	}
+	return results
+}
+
+func runTest(ctx context.Context, hdls map[string]string, test Test, options Options) Result {
+	result := Result{Name: test.Name}
+
+	script, err := testscript.Parse(test.Script)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	if ctx.Err() != nil {
+		result.Error = "time limit exceeded"
+		return result
+	}
+
+	hs := simulator.New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+	run, err := testscript.Run(ctx, hs, script, testscript.Options{
+		Chip:     test.Chip,
+		Compare:  test.Compare,
+		MaxSteps: options.MaxSteps,
+	})
+	switch {
+	case errors.Is(err, testscript.ErrStepLimitExceeded):
+		result.Error = "step limit exceeded"
+	case ctx.Err() != nil:
+		result.Error = "time limit exceeded"
+	case err != nil:
+		result.Error = err.Error()
+	default:
+		result.Passed = run.Passed
+		result.Mismatch = run.Mismatch
+	}
+	return result
+}
+
+// GradebookRow holds the results of the tests of a student, nil if they have
+// not been graded.
+type GradebookRow struct {
+	Student string
+	Results []Result
+}
+
+// WriteGradebook writes the results of the students as CSV to w, with a row
+// for each student, a column for each of the tests holding pass or fail, and
+// the number of tests passed. The cells of the students who have not been
+// graded are left empty. Names that a spreadsheet would read as a formula are
+// escaped with a leading '.
+func WriteGradebook(w io.Writer, tests []string, rows []GradebookRow) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, 0, len(tests)+2)
+	header = append(header, "student")
+	for _, test := range tests {
+		header = append(header, escapeCell(test))
+	}
+	header = append(header, "passed")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(header))
+		record[0] = escapeCell(row.Student)
+
+		if row.Results != nil {
+			passed := 0
+			for i, test := range tests {
+				for _, result := range row.Results {
+					if result.Name != test {
+						continue
+					}
+					if result.Passed {
+						record[i+1] = "pass"
+						passed++
+					} else {
+						record[i+1] = "fail"
+					}
+					break
+				}
+			}
+			record[len(record)-1] = fmt.Sprintf("%d/%d", passed, len(tests))
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeCell prefixes the cell with ' if it starts with a character that makes
+// spreadsheet applications evaluate it as a formula, or with a tab or carriage
+// return that they may skip before one.
+func escapeCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
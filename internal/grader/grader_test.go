@@ -0,0 +1,132 @@
+package grader
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testscript"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const xorTest = `load XorChip.hdl,
+output-list a%B3.1.3 b%B3.1.3 out%B3.1.3;
+set a 0, set b 0, eval, output;
+set a 0, set b 1, eval, output;
+set a 1, set b 0, eval, output;
+set a 1, set b 1, eval, output;
+`
+
+const xorCmp = `|   a   |   b   |  out  |
+|   0   |   0   |   0   |
+|   0   |   1   |   1   |
+|   1   |   0   |   1   |
+|   1   |   1   |   0   |
+`
+
+// orAsXor is a wrong XorChip, differing from Xor when both inputs are 1.
+const orAsXor = `CHIP XorChip {
+    IN a, b;
+    OUT out;
+
+    PARTS:
+    Or(a = a, b = b, out = out);
+}`
+
+func TestGrade(t *testing.T) {
+	tests := []Test{
+		{Name: "Xor", Script: xorTest, Compare: xorCmp},
+		{Name: "Missing chip", Chip: "AndChip", Script: "eval;", Compare: xorCmp},
+		{Name: "Invalid script", Script: "set a;", Compare: xorCmp},
+		{Name: "Step limit", Chip: "XorChip", Script: "repeat { eval; }", Compare: xorCmp},
+	}
+	options := Options{MaxSteps: 1000}
+
+	t.Run("Correct chips", func(t *testing.T) {
+		results := Grade(context.Background(), testutils.ChipImplementations, tests[:1], options)
+		assert.Equal(t, []Result{{Name: "Xor", Passed: true}}, results)
+	})
+
+	t.Run("Wrong chips", func(t *testing.T) {
+		results := Grade(context.Background(), map[string]string{"XorChip": orAsXor}, tests, options)
+		assert.Len(t, results, len(tests))
+
+		assert.Equal(t, Result{
+			Name: "Xor",
+			Mismatch: &testscript.Mismatch{
+				Line:     5,
+				Expected: "|   1   |   1   |   0   |",
+				Actual:   "|   1   |   1   |   1   |",
+			},
+		}, results[0])
+
+		for _, result := range results[1:] {
+			assert.False(t, result.Passed, result.Name)
+			assert.NotEmpty(t, result.Error, result.Name)
+		}
+		assert.Equal(t, "step limit exceeded", results[3].Error)
+	})
+
+	t.Run("Context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results := Grade(ctx, testutils.ChipImplementations, tests[:1], options)
+		assert.Equal(t, []Result{{Name: "Xor", Error: "time limit exceeded"}}, results)
+	})
+
+	t.Run("Runaway script", func(t *testing.T) {
+		runaway := []Test{{Name: "Runaway", Chip: "XorChip", Script: "repeat { }", Compare: xorCmp}}
+		results := Grade(context.Background(), testutils.ChipImplementations, runaway, Options{Timeout: 50 * time.Millisecond})
+		assert.Equal(t, []Result{{Name: "Runaway", Error: "time limit exceeded"}}, results)
+	})
+}
+
+func TestWriteGradebook(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteGradebook(&buf, []string{"Not", "And"}, []GradebookRow{
+		{Student: "alice", Results: []Result{{Name: "Not", Passed: true}, {Name: "And", Passed: true}}},
+		{Student: "bob", Results: []Result{{Name: "Not", Passed: true}, {Name: "And", Error: "chip not found"}}},
+		{Student: "carol"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "student,Not,And,passed\nalice,pass,pass,2/2\nbob,pass,fail,1/2\ncarol,,,\n", buf.String())
+}
+
+func TestWriteGradebookDuplicateResults(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteGradebook(&buf, []string{"Not", "And"}, []GradebookRow{
+		{Student: "alice", Results: []Result{
+			{Name: "Not", Passed: true},
+			{Name: "Not", Passed: true},
+			{Name: "And", Error: "chip not found"},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "student,Not,And,passed\nalice,pass,fail,1/2\n", buf.String())
+}
+
+func TestWriteGradebookFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteGradebook(&buf, []string{"=Not"}, []GradebookRow{
+		{Student: "=HYPERLINK(\"http://example.com\")", Results: []Result{{Name: "=Not", Passed: true}}},
+		{Student: "+alice"},
+		{Student: "-bob"},
+		{Student: "@carol"},
+		{Student: "\tdave"},
+		{Student: "\rerin"},
+		{Student: "frank"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"student,'=Not,passed\n"+
+			"\"'=HYPERLINK(\"\"http://example.com\"\")\",pass,1/1\n"+
+			"'+alice,,\n"+
+			"'-bob,,\n"+
+			"'@carol,,\n"+
+			"'\tdave,,\n"+
+			"\"'\rerin\",,\n"+
+			"frank,,\n",
+		buf.String())
+}
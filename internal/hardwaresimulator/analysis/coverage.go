@@ -0,0 +1,275 @@
+package analysis
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+)
+
+// selectPinName is the input of the chips that pick between their inputs or
+// outputs, like Mux and DMux.
+const selectPinName = "sel"
+
+const (
+	LineCovered   = "covered"
+	LinePartial   = "partial"
+	LineUncovered = "uncovered"
+)
+
+// SignalCoverage tells which bits of a signal of the chip went from 0 to 1 and
+// from 1 to 0. Line is the line of the part driving the signal, 0 for inputs.
+type SignalCoverage struct {
+	Name    string `json:"name"`
+	Line    int    `json:"line"`
+	Rose    []bool `json:"rose"`
+	Fell    []bool `json:"fell"`
+	Covered bool   `json:"covered"`
+}
+
+// PartCoverage tells how much a part of the chip was exercised. The pin bits
+// of the part, except for the constants, have to toggle both ways, and parts
+// with a sel input have to select every one of their inputs or outputs.
+type PartCoverage struct {
+	Chip        string `json:"chip"`
+	Line        int    `json:"line"`
+	Column      int    `json:"column"`
+	Bits        int    `json:"bits"`
+	ToggledBits int    `json:"toggledBits"`
+	// Selections is the number of values of sel, 0 without a sel input, and
+	// Selected the values it took.
+	Selections int   `json:"selections"`
+	Selected   []int `json:"selected"`
+	Covered    bool  `json:"covered"`
+}
+
+// LineCoverage is the status of a line of the HDL with parts on it.
+type LineCoverage struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+}
+
+type CoverageReport struct {
+	// Samples is the number of times the signals were recorded.
+	Samples int              `json:"samples"`
+	Signals []SignalCoverage `json:"signals"`
+	Parts   []PartCoverage   `json:"parts"`
+	Lines   []LineCoverage   `json:"lines"`
+	// Bits and ToggledBits count the bits of the signals of the chip.
+	Bits        int `json:"bits"`
+	ToggledBits int `json:"toggledBits"`
+}
+
+type bitCoverage struct {
+	sampled bool
+	value   bool
+	rose    bool
+	fell    bool
+}
+
+func (b *bitCoverage) toggled() bool {
+	return b.rose && b.fell
+}
+
+// Coverage records the signals of a chip and of the pins of its parts every
+// time it is sampled, usually after each evaluation.
+type Coverage struct {
+	graph    *graphbuilder.Graph
+	bits     map[*graphbuilder.Bit]*bitCoverage
+	selected map[*graphbuilder.Node]map[int]bool
+	samples  int
+}
+
+func NewCoverage(g *graphbuilder.Graph) *Coverage {
+	c := &Coverage{graph: g}
+	c.Reset()
+	return c
+}
+
+// Reset forgets everything recorded so far.
+func (c *Coverage) Reset() {
+	c.bits = make(map[*graphbuilder.Bit]*bitCoverage)
+	c.selected = make(map[*graphbuilder.Node]map[int]bool)
+	c.samples = 0
+
+	add := func(pin []*graphbuilder.BitRef) {
+		for _, bitRef := range pin {
+			if _, ok := c.bits[bitRef.Bit]; !ok {
+				c.bits[bitRef.Bit] = &bitCoverage{}
+			}
+		}
+	}
+	for _, pin := range c.graph.InputPins {
+		add(pin.Bits)
+	}
+	for _, pin := range c.graph.OutputPins {
+		add(pin.Bits)
+	}
+	for _, node := range c.graph.Nodes {
+		for _, pin := range node.InputPins {
+			add(pin.Bits)
+		}
+		for _, pin := range node.OutputPins {
+			add(pin.Bits)
+		}
+		if _, ok := node.InputPins[selectPinName]; ok {
+			c.selected[node] = make(map[int]bool)
+		}
+	}
+}
+
+// Sample records the current values of the signals. Unknown bits of
+// four-valued simulation are left out.
+func (c *Coverage) Sample() {
+	c.samples++
+	for bit, coverage := range c.bits {
+		if bit.Unknown {
+			continue
+		}
+		if coverage.sampled && coverage.value != bit.Value {
+			coverage.rose = coverage.rose || bit.Value
+			coverage.fell = coverage.fell || !bit.Value
+		}
+		coverage.sampled = true
+		coverage.value = bit.Value
+	}
+
+	for node, selected := range c.selected {
+		sel, known := 0, true
+		for i, bitRef := range node.InputPins[selectPinName].Bits {
+			if bitRef.Bit.Unknown {
+				known = false
+				break
+			}
+			if bitRef.Bit.Value {
+				sel |= 1 << i
+			}
+		}
+		if known {
+			selected[sel] = true
+		}
+	}
+}
+
+// Report returns the coverage of the signals and of the parts of the chip,
+// the parts in the order of the HDL.
+func (c *Coverage) Report() *CoverageReport {
+	report := &CoverageReport{
+		Samples: c.samples,
+		Signals: []SignalCoverage{},
+		Parts:   []PartCoverage{},
+		Lines:   []LineCoverage{},
+	}
+
+	// the inputs of the chip have no driver part
+	drivers := make(map[*graphbuilder.Bit]*graphbuilder.Node)
+	for _, pin := range c.graph.InputPins {
+		for _, bitRef := range pin.Bits {
+			drivers[bitRef.Bit] = nil
+		}
+	}
+	for _, node := range c.graph.Nodes {
+		for _, pin := range node.OutputPins {
+			for _, bitRef := range pin.Bits {
+				drivers[bitRef.Bit] = node
+			}
+		}
+	}
+
+	addSignal := func(name string, bits []*graphbuilder.BitRef) {
+		signal := SignalCoverage{
+			Name:    name,
+			Rose:    make([]bool, len(bits)),
+			Fell:    make([]bool, len(bits)),
+			Covered: true,
+		}
+		for i, bitRef := range bits {
+			coverage := c.bits[bitRef.Bit]
+			signal.Rose[i] = coverage.rose
+			signal.Fell[i] = coverage.fell
+			signal.Covered = signal.Covered && coverage.toggled()
+			if driver := drivers[bitRef.Bit]; driver != nil && signal.Line == 0 {
+				signal.Line = driver.Loc.Line
+			}
+			report.Bits++
+			if coverage.toggled() {
+				report.ToggledBits++
+			}
+		}
+		report.Signals = append(report.Signals, signal)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.graph.InputPins)) {
+		addSignal(name, c.graph.InputPins[name].Bits)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.graph.InternalPins)) {
+		addSignal(name, c.graph.InternalPins[name].Bits)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.graph.OutputPins)) {
+		addSignal(name, c.graph.OutputPins[name].Bits)
+	}
+
+	nodes := slices.Clone(c.graph.Nodes)
+	slices.SortStableFunc(nodes, func(a, b *graphbuilder.Node) int {
+		return cmp.Or(cmp.Compare(a.Loc.Line, b.Loc.Line), cmp.Compare(a.Loc.Column, b.Loc.Column))
+	})
+
+	lines := make(map[int]string)
+	for _, node := range nodes {
+		part := c.partCoverage(node, drivers)
+		report.Parts = append(report.Parts, part)
+
+		status := LinePartial
+		if part.Covered {
+			status = LineCovered
+		} else if part.ToggledBits == 0 && len(part.Selected) <= 1 {
+			status = LineUncovered
+		}
+		if previous, ok := lines[part.Line]; ok && previous != status {
+			status = LinePartial
+		}
+		lines[part.Line] = status
+	}
+	for _, line := range slices.Sorted(maps.Keys(lines)) {
+		report.Lines = append(report.Lines, LineCoverage{Line: line, Status: lines[line]})
+	}
+
+	return report
+}
+
+// partCoverage counts the bits of the pins of the part, leaving out the
+// constants: the bits that are neither inputs of the chip nor driven by a part.
+func (c *Coverage) partCoverage(node *graphbuilder.Node, drivers map[*graphbuilder.Bit]*graphbuilder.Node) PartCoverage {
+	part := PartCoverage{
+		Chip:     node.ChipName,
+		Line:     node.Loc.Line,
+		Column:   node.Loc.Column,
+		Selected: []int{},
+	}
+
+	count := func(pin *graphbuilder.Pin) {
+		for _, bitRef := range pin.Bits {
+			if _, ok := drivers[bitRef.Bit]; !ok {
+				continue
+			}
+			part.Bits++
+			if c.bits[bitRef.Bit].toggled() {
+				part.ToggledBits++
+			}
+		}
+	}
+	for _, pin := range node.InputPins {
+		count(pin)
+	}
+	for _, pin := range node.OutputPins {
+		count(pin)
+	}
+
+	if selected, ok := c.selected[node]; ok {
+		part.Selections = 1 << len(node.InputPins[selectPinName].Bits)
+		part.Selected = slices.Sorted(maps.Keys(selected))
+	}
+
+	part.Covered = part.ToggledBits == part.Bits && len(part.Selected) == part.Selections
+	return part
+}
@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/evaluator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoverage(t *testing.T) {
+	hdls := map[string]string{
+		"Cover": `CHIP Cover {
+	IN a, b, sel;
+	OUT out, n;
+
+	PARTS:
+	Mux(a = a, b = b, sel = sel, out = out);
+	Not(in = true, out = n);
+	And(a = a, b = false, out = x);
+}`,
+	}
+
+	g := mustBuildGraph(t, hdls, "Cover")
+	e := evaluator.New(g)
+	e.InitializeNodeStates()
+	c := NewCoverage(g)
+
+	run := func(inputs ...[3]bool) {
+		for _, in := range inputs {
+			e.SetInputs(map[string][]bool{"a": {in[0]}, "b": {in[1]}, "sel": {in[2]}})
+			e.Evaluate()
+			c.Sample()
+		}
+	}
+
+	t.Run("Nothing toggles with a single sample", func(t *testing.T) {
+		run([3]bool{false, false, false})
+		report := c.Report()
+
+		assert.Equal(t, 1, report.Samples)
+		assert.Equal(t, 6, report.Bits)
+		assert.Equal(t, 0, report.ToggledBits)
+		assert.Equal(t, []LineCoverage{
+			{Line: 6, Status: LineUncovered},
+			{Line: 7, Status: LineUncovered},
+			{Line: 8, Status: LineUncovered},
+		}, report.Lines)
+		assert.Equal(t, PartCoverage{
+			Chip: "Mux", Line: 6, Column: 5,
+			Bits: 4, ToggledBits: 0,
+			Selections: 2, Selected: []int{0},
+		}, report.Parts[0])
+	})
+
+	t.Run("Mux selecting both inputs", func(t *testing.T) {
+		run(
+			[3]bool{true, false, false},
+			[3]bool{false, false, false},
+			[3]bool{false, true, false},
+			[3]bool{false, true, true},
+			[3]bool{false, false, true},
+			[3]bool{false, false, false},
+		)
+		report := c.Report()
+
+		assert.Equal(t, 7, report.Samples)
+		assert.Equal(t, []PartCoverage{
+			{Chip: "Mux", Line: 6, Column: 5, Bits: 4, ToggledBits: 4, Selections: 2, Selected: []int{0, 1}, Covered: true},
+			{Chip: "Not", Line: 7, Column: 5, Bits: 1, ToggledBits: 0, Selected: []int{}},
+			{Chip: "And", Line: 8, Column: 5, Bits: 2, ToggledBits: 1, Selected: []int{}},
+		}, report.Parts)
+		assert.Equal(t, []LineCoverage{
+			{Line: 6, Status: LineCovered},
+			{Line: 7, Status: LineUncovered},
+			{Line: 8, Status: LinePartial},
+		}, report.Lines)
+		assert.Equal(t, []SignalCoverage{
+			{Name: "a", Rose: []bool{true}, Fell: []bool{true}, Covered: true},
+			{Name: "b", Rose: []bool{true}, Fell: []bool{true}, Covered: true},
+			{Name: "sel", Rose: []bool{true}, Fell: []bool{true}, Covered: true},
+			{Name: "x", Line: 8, Rose: []bool{false}, Fell: []bool{false}},
+			{Name: "n", Line: 7, Rose: []bool{false}, Fell: []bool{false}},
+			{Name: "out", Line: 6, Rose: []bool{true}, Fell: []bool{true}, Covered: true},
+		}, report.Signals)
+		assert.Equal(t, 4, report.ToggledBits)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		c.Reset()
+		report := c.Report()
+		assert.Equal(t, 0, report.Samples)
+		assert.Equal(t, 0, report.ToggledBits)
+	})
+}
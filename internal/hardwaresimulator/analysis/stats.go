@@ -0,0 +1,147 @@
+package analysis
+
+import (
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+)
+
+// Nand and DFF are the primitives of the course, every other chip is built from them.
+const (
+	nandChipName = "Nand"
+	dffChipName  = "DFF"
+)
+
+// Cost is the size of a built-in chip in primitives.
+type Cost struct {
+	Nands int `json:"nands"`
+	DFFs  int `json:"dffs"`
+}
+
+// Costs holds the cost of the built-in chips that are used as black boxes, by chip name.
+type Costs map[string]Cost
+
+// DefaultCosts returns the costs of the default built-in chips when they are built
+// the way the course suggests, each from the chips of the previous projects.
+func DefaultCosts() Costs {
+	c := Costs{
+		nandChipName: {Nands: 1},
+		dffChipName:  {DFFs: 1},
+	}
+	c["Not"] = c.sum(1, "Nand")
+	c["And"] = c.sum(1, "Nand", "Not")
+	c["Or"] = c.sum(1, "Nand").add(c.sum(2, "Not"))
+	c["Xor"] = c.sum(4, "Nand")
+	c["Mux"] = c.sum(1, "Not").add(c.sum(3, "Nand"))
+	c["DMux"] = c.sum(1, "Not").add(c.sum(2, "And"))
+	c["Not16"] = c.sum(16, "Not")
+	c["And16"] = c.sum(16, "And")
+	c["Or16"] = c.sum(16, "Or")
+	c["Mux16"] = c.sum(16, "Mux")
+	c["Or8Way"] = c.sum(7, "Or")
+	c["Mux4Way16"] = c.sum(3, "Mux16")
+	c["Mux8Way16"] = c.sum(7, "Mux16")
+	c["DMux4Way"] = c.sum(3, "DMux")
+	c["DMux8Way"] = c.sum(7, "DMux")
+	c["HalfAdder"] = c.sum(1, "Xor", "And")
+	c["FullAdder"] = c.sum(2, "HalfAdder").add(c.sum(1, "Or"))
+	c["Add16"] = c.sum(16, "FullAdder")
+	c["Inc16"] = c.sum(16, "HalfAdder")
+	c["Bit"] = c.sum(1, "Mux", "DFF")
+	c["Register"] = c.sum(16, "Bit")
+	c["PC"] = c.sum(1, "Register", "Inc16").add(c.sum(3, "Mux16"))
+	c["RAM8"] = c.sum(8, "Register").add(c.sum(1, "DMux8Way", "Mux8Way16"))
+	c["RAM64"] = c.sum(8, "RAM8").add(c.sum(1, "DMux8Way", "Mux8Way16"))
+	c["RAM512"] = c.sum(8, "RAM64").add(c.sum(1, "DMux8Way", "Mux8Way16"))
+	c["RAM4K"] = c.sum(8, "RAM512").add(c.sum(1, "DMux8Way", "Mux8Way16"))
+	c["RAM16K"] = c.sum(4, "RAM4K").add(c.sum(1, "DMux4Way", "Mux4Way16"))
+	return c
+}
+
+// sum returns the cost of n of each of the chips.
+func (c Costs) sum(n int, chipNames ...string) Cost {
+	var total Cost
+	for _, chipName := range chipNames {
+		total = total.add(Cost{Nands: n * c[chipName].Nands, DFFs: n * c[chipName].DFFs})
+	}
+	return total
+}
+
+func (c Cost) add(other Cost) Cost {
+	return Cost{Nands: c.Nands + other.Nands, DFFs: c.DFFs + other.DFFs}
+}
+
+// Stats is the size of a chip or part, including all of its parts.
+type Stats struct {
+	Chip  string `json:"chip"`
+	Nands int    `json:"nands"`
+	DFFs  int    `json:"dffs"`
+	// BlackBoxes counts the built-in chips other than Nand and DFF, their cost is
+	// included in Nands and DFFs.
+	BlackBoxes map[string]int `json:"blackBoxes"`
+	// Uncosted counts the black boxes without a cost, they are not included in Nands and DFFs.
+	Uncosted map[string]int `json:"uncosted"`
+	// Instances counts every chip used in the chip, at any depth, by name.
+	Instances map[string]int `json:"instances"`
+	Parts     []Stats        `json:"parts"`
+}
+
+// Analyze counts the gates of the chip built into the graph, expanding the parts
+// that are custom chips. Built-in chips other than Nand and DFF are counted with
+// the given costs, usually DefaultCosts().
+func Analyze(chipName string, g *graphbuilder.Graph, costs Costs) Stats {
+	stats := newStats(chipName)
+	for _, node := range g.Nodes {
+		part := analyzeNode(node, costs)
+		stats.addPart(part)
+		stats.Parts = append(stats.Parts, part)
+	}
+	return stats
+}
+
+func analyzeNode(node *graphbuilder.Node, costs Costs) Stats {
+	if node.SubGraph != nil {
+		return Analyze(node.ChipName, node.SubGraph, costs)
+	}
+
+	stats := newStats(node.ChipName)
+	switch node.ChipName {
+	case nandChipName:
+		stats.Nands = 1
+	case dffChipName:
+		stats.DFFs = 1
+	default:
+		stats.BlackBoxes[node.ChipName] = 1
+		cost, ok := costs[node.ChipName]
+		if !ok {
+			stats.Uncosted[node.ChipName] = 1
+			break
+		}
+		stats.Nands = cost.Nands
+		stats.DFFs = cost.DFFs
+	}
+	return stats
+}
+
+func newStats(chipName string) Stats {
+	return Stats{
+		Chip:       chipName,
+		BlackBoxes: map[string]int{},
+		Uncosted:   map[string]int{},
+		Instances:  map[string]int{},
+		Parts:      []Stats{},
+	}
+}
+
+func (s *Stats) addPart(part Stats) {
+	s.Nands += part.Nands
+	s.DFFs += part.DFFs
+	s.Instances[part.Chip]++
+	for chipName, count := range part.BlackBoxes {
+		s.BlackBoxes[chipName] += count
+	}
+	for chipName, count := range part.Uncosted {
+		s.Uncosted[chipName] += count
+	}
+	for chipName, count := range part.Instances {
+		s.Instances[chipName] += count
+	}
+}
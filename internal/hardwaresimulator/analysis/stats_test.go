@@ -0,0 +1,165 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/resolver"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip":         testutils.ChipImplementations["NotChip"],
+		"AndChip":         testutils.ChipImplementations["AndChip"],
+		"OrChip":          testutils.ChipImplementations["OrChip"],
+		"XorChip":         testutils.ChipImplementations["XorChip"],
+		"MuxChip":         testutils.ChipImplementations["MuxChip"],
+		"BitChip":         testutils.ChipImplementations["BitChip"],
+		"BuiltinNandChip": testutils.ChipImplementations["BuiltinNandChip"],
+		"BuiltinXorChip":  testutils.ChipImplementations["BuiltinXorChip"],
+		"BlackBoxChip": `CHIP BlackBoxChip {
+			IN a[16], b[16], sel, load;
+			OUT out[16];
+
+			PARTS:
+			Mux16(a = a, b = b, sel = sel, out = muxOut);
+			Register(in = muxOut, load = load, out = out);
+		}`,
+	}
+
+	tests := []struct {
+		name       string
+		chipName   string
+		costs      Costs
+		nands      int
+		dffs       int
+		blackBoxes map[string]int
+		uncosted   map[string]int
+		instances  map[string]int
+		parts      []string
+	}{
+		{
+			name:       "Only Nands",
+			chipName:   "XorChip",
+			costs:      DefaultCosts(),
+			nands:      6,
+			blackBoxes: map[string]int{},
+			uncosted:   map[string]int{},
+			instances:  map[string]int{"OrChip": 1, "AndChip": 1, "NotChip": 3, "Nand": 6},
+			parts:      []string{"OrChip", "Nand", "AndChip"},
+		},
+		{
+			name:       "DFF",
+			chipName:   "BitChip",
+			costs:      DefaultCosts(),
+			nands:      8,
+			dffs:       1,
+			blackBoxes: map[string]int{},
+			uncosted:   map[string]int{},
+			instances:  map[string]int{"MuxChip": 1, "NotChip": 5, "AndChip": 2, "OrChip": 1, "Nand": 8, "DFF": 1},
+			parts:      []string{"MuxChip", "DFF"},
+		},
+		{
+			name:       "Custom chips declared with BUILTIN",
+			chipName:   "BuiltinXorChip",
+			costs:      DefaultCosts(),
+			nands:      4,
+			blackBoxes: map[string]int{},
+			uncosted:   map[string]int{},
+			instances:  map[string]int{"Nand": 4},
+			parts:      []string{"Nand", "Nand", "Nand", "Nand"},
+		},
+		{
+			name:       "Black boxes",
+			chipName:   "BlackBoxChip",
+			costs:      DefaultCosts(),
+			nands:      128,
+			dffs:       16,
+			blackBoxes: map[string]int{"Mux16": 1, "Register": 1},
+			uncosted:   map[string]int{},
+			instances:  map[string]int{"Mux16": 1, "Register": 1},
+			parts:      []string{"Mux16", "Register"},
+		},
+		{
+			name:       "Configured costs",
+			chipName:   "BlackBoxChip",
+			costs:      Costs{"Mux16": {Nands: 49}},
+			nands:      49,
+			blackBoxes: map[string]int{"Mux16": 1, "Register": 1},
+			uncosted:   map[string]int{"Register": 1},
+			instances:  map[string]int{"Mux16": 1, "Register": 1},
+			parts:      []string{"Mux16", "Register"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := mustBuildGraph(t, hdls, tt.chipName)
+			stats := Analyze(tt.chipName, g, tt.costs)
+			assert.Equal(t, tt.chipName, stats.Chip)
+			assert.Equal(t, tt.nands, stats.Nands)
+			assert.Equal(t, tt.dffs, stats.DFFs)
+			assert.Equal(t, tt.blackBoxes, stats.BlackBoxes)
+			assert.Equal(t, tt.uncosted, stats.Uncosted)
+			assert.Equal(t, tt.instances, stats.Instances)
+
+			parts := make([]string, 0, len(stats.Parts))
+			nands, dffs := 0, 0
+			for _, part := range stats.Parts {
+				parts = append(parts, part.Chip)
+				nands += part.Nands
+				dffs += part.DFFs
+			}
+			assert.ElementsMatch(t, tt.parts, parts)
+			assert.Equal(t, stats.Nands, nands)
+			assert.Equal(t, stats.DFFs, dffs)
+		})
+	}
+}
+
+func TestDefaultCosts(t *testing.T) {
+	costs := DefaultCosts()
+	registry := chips.NewDefaultRegistry()
+	for _, chipName := range registry.Names() {
+		assert.Contains(t, costs, chipName)
+	}
+
+	assert.Equal(t, Cost{Nands: 4, DFFs: 1}, costs["Bit"])
+	assert.Equal(t, Cost{Nands: 64, DFFs: 16}, costs["Register"])
+	assert.Equal(t, 16*1024*16, costs["RAM16K"].DFFs)
+}
+
+func mustBuildGraph(t *testing.T, hdls map[string]string, chipName string) *graphbuilder.Graph {
+	t.Helper()
+
+	l := lexer.New(hdls[chipName])
+	ts, err := l.Tokenize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := parser.New(ts)
+	chd, err := p.ParseChipDefinition()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := chips.NewDefaultRegistry()
+	r := resolver.New(chd, chipName, hdls, registry)
+	rchd, rchds, err := r.Resolve([]string{}, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rchds[rchd.Name] = rchd
+
+	g, err := graphbuilder.New(rchds, registry).BuildGraph(rchd.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
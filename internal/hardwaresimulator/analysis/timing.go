@@ -0,0 +1,277 @@
+package analysis
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+)
+
+// Delays holds the gate depth of the built-in chips that are used as black boxes,
+// from any input to any combinational output, by chip name. Chips without a delay
+// count as a single gate.
+type Delays map[string]int
+
+// DefaultDelays returns the depth in Nands of the default built-in chips when
+// they are built the way the course suggests.
+func DefaultDelays() Delays {
+	return Delays{
+		"Nand":      1,
+		"Not":       1,
+		"And":       2,
+		"Or":        2,
+		"Xor":       3,
+		"Mux":       3,
+		"DMux":      3,
+		"Not16":     1,
+		"And16":     2,
+		"Or16":      2,
+		"Mux16":     3,
+		"Or8Way":    6,
+		"Mux4Way16": 6,
+		"Mux8Way16": 9,
+		"DMux4Way":  6,
+		"DMux8Way":  9,
+		"HalfAdder": 3,
+		"FullAdder": 7,
+		"Add16":     67,
+		"Inc16":     33,
+		"RAM8":      9,
+		"RAM64":     18,
+		"RAM512":    27,
+		"RAM4K":     36,
+		"RAM16K":    42,
+	}
+}
+
+// PinBit is a single bit of a pin.
+type PinBit struct {
+	Name string `json:"name"`
+	Bit  int    `json:"bit"`
+}
+
+// PartRef is a part in the HDL of the chip that uses it.
+type PartRef struct {
+	Chip   string `json:"chip"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// PathStep is a built-in chip on a path, passing the signal from one of its
+// input bits to one of its output bits.
+type PathStep struct {
+	Chip   string `json:"chip"`
+	Input  PinBit `json:"input"`
+	Output PinBit `json:"output"`
+	Delay  int    `json:"delay"`
+	// Parts are the parts containing the built-in chip, starting with the part
+	// of the analyzed chip and ending with the built-in chip itself.
+	Parts []PartRef `json:"parts"`
+}
+
+// Timing is the result of the timing analysis of a chip.
+type Timing struct {
+	// Depths holds the largest gate depth from each input to each output of the
+	// chip, pairs without a combinational path between them are left out.
+	Depths map[string]map[string]int `json:"depths"`
+	// Depth is the gate depth of the critical path, the longest combinational
+	// path ending in an output of the chip.
+	Depth        int        `json:"depth"`
+	CriticalPath []PathStep `json:"criticalPath"`
+	// From is the input of the chip where the critical path starts, nil if it
+	// starts at a constant or at a sequential output, e.g. of a DFF.
+	From *PinBit `json:"from"`
+	// To is the output of the chip where the critical path ends.
+	To *PinBit `json:"to"`
+}
+
+// leaf is a built-in chip of the flattened graph.
+type leaf struct {
+	node  *graphbuilder.Node
+	parts []*graphbuilder.Node // the parts containing the node, including itself
+}
+
+// driver is the output of a built-in chip that drives a bit.
+type driver struct {
+	leaf   *leaf
+	output PinBit
+}
+
+// arrival is the longest path from the sources to a bit, through the input of
+// its driver stored in input.
+type arrival struct {
+	depth int // -1 if no source reaches the bit
+	input PinBit
+	from  *graphbuilder.Bit
+}
+
+type timingGraph struct {
+	drivers map[*graphbuilder.Bit]driver
+	delays  Delays
+}
+
+// AnalyzeTiming computes the gate depths of the combinational paths of the chip
+// built into the graph. Sequential outputs, like the output of a DFF, start new
+// paths. Built-in chips other than Nand have the depth given in delays, usually
+// DefaultDelays().
+func AnalyzeTiming(g *graphbuilder.Graph, delays Delays) Timing {
+	t := &timingGraph{
+		drivers: map[*graphbuilder.Bit]driver{},
+		delays:  delays,
+	}
+	t.flatten(g, nil)
+
+	timing := Timing{
+		Depths:       map[string]map[string]int{},
+		CriticalPath: []PathStep{},
+	}
+
+	for _, inputName := range slices.Sorted(maps.Keys(g.InputPins)) {
+		sources := map[*graphbuilder.Bit]bool{}
+		for _, bitRef := range g.InputPins[inputName].Bits {
+			sources[bitRef.Bit] = true
+		}
+		arrivals := map[*graphbuilder.Bit]arrival{}
+		for outputName, output := range g.OutputPins {
+			depth := -1
+			for _, bitRef := range output.Bits {
+				depth = max(depth, t.arrival(bitRef.Bit, sources, arrivals).depth)
+			}
+			if depth < 0 {
+				continue
+			}
+			if timing.Depths[inputName] == nil {
+				timing.Depths[inputName] = map[string]int{}
+			}
+			timing.Depths[inputName][outputName] = depth
+		}
+	}
+
+	// every bit that is not driven combinationally starts a path
+	arrivals := map[*graphbuilder.Bit]arrival{}
+	var end *graphbuilder.Bit
+	for _, outputName := range slices.Sorted(maps.Keys(g.OutputPins)) {
+		for i, bitRef := range g.OutputPins[outputName].Bits {
+			a := t.arrival(bitRef.Bit, nil, arrivals)
+			if a.depth > timing.Depth || timing.To == nil {
+				timing.Depth = a.depth
+				timing.To = &PinBit{Name: outputName, Bit: i}
+				end = bitRef.Bit
+			}
+		}
+	}
+	if end == nil {
+		return timing
+	}
+
+	bit := end
+	for {
+		d, driven := t.drivers[bit]
+		if !driven {
+			break
+		}
+		a := arrivals[bit]
+		timing.CriticalPath = append(timing.CriticalPath, PathStep{
+			Chip:   d.leaf.node.ChipName,
+			Input:  a.input,
+			Output: d.output,
+			Delay:  t.delay(d.leaf.node),
+			Parts:  partRefs(d.leaf.parts),
+		})
+		bit = a.from
+	}
+	slices.Reverse(timing.CriticalPath)
+	timing.From = findInput(g, bit)
+
+	return timing
+}
+
+// flatten collects the drivers of the combinational outputs of every built-in chip in the graph.
+func (t *timingGraph) flatten(g *graphbuilder.Graph, parts []*graphbuilder.Node) {
+	for _, node := range g.Nodes {
+		nodeParts := append(slices.Clone(parts), node)
+		if node.SubGraph != nil {
+			t.flatten(node.SubGraph, nodeParts)
+			continue
+		}
+
+		l := &leaf{node: node, parts: nodeParts}
+		for outputName, output := range node.OutputPins {
+			for i, bitRef := range output.Bits {
+				if bitRef.Bit.IsSequential {
+					continue
+				}
+				t.drivers[bitRef.Bit] = driver{leaf: l, output: PinBit{Name: outputName, Bit: i}}
+			}
+		}
+	}
+}
+
+// arrival returns the longest path to the bit from the sources. With nil sources,
+// every bit without a combinational driver is a source.
+func (t *timingGraph) arrival(
+	bit *graphbuilder.Bit,
+	sources map[*graphbuilder.Bit]bool,
+	arrivals map[*graphbuilder.Bit]arrival,
+) arrival {
+	if a, ok := arrivals[bit]; ok {
+		return a
+	}
+
+	d, driven := t.drivers[bit]
+	if sources[bit] || (sources == nil && !driven) {
+		arrivals[bit] = arrival{depth: 0}
+		return arrivals[bit]
+	}
+	if !driven {
+		arrivals[bit] = arrival{depth: -1}
+		return arrivals[bit]
+	}
+
+	// guards against combinational loops
+	arrivals[bit] = arrival{depth: -1}
+
+	best := arrival{depth: -1}
+	inputPins := d.leaf.node.InputPins
+	for _, inputName := range slices.Sorted(maps.Keys(inputPins)) {
+		for i, bitRef := range inputPins[inputName].Bits {
+			a := t.arrival(bitRef.Bit, sources, arrivals)
+			if a.depth > best.depth {
+				best = arrival{depth: a.depth, input: PinBit{Name: inputName, Bit: i}, from: bitRef.Bit}
+			}
+		}
+	}
+	if best.depth >= 0 {
+		best.depth += t.delay(d.leaf.node)
+	}
+
+	arrivals[bit] = best
+	return best
+}
+
+func (t *timingGraph) delay(node *graphbuilder.Node) int {
+	if delay, ok := t.delays[node.ChipName]; ok {
+		return delay
+	}
+	return 1
+}
+
+func partRefs(parts []*graphbuilder.Node) []PartRef {
+	refs := make([]PartRef, 0, len(parts))
+	for _, part := range parts {
+		refs = append(refs, PartRef{Chip: part.ChipName, Line: part.Loc.Line, Column: part.Loc.Column})
+	}
+	return refs
+}
+
+// findInput returns the input bit of the chip that is the given bit, if any.
+func findInput(g *graphbuilder.Graph, bit *graphbuilder.Bit) *PinBit {
+	for _, inputName := range slices.Sorted(maps.Keys(g.InputPins)) {
+		for i, bitRef := range g.InputPins[inputName].Bits {
+			if bitRef.Bit == bit {
+				return &PinBit{Name: inputName, Bit: i}
+			}
+		}
+	}
+	return nil
+}
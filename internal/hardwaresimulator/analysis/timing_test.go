@@ -0,0 +1,136 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeTiming(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip":       testutils.ChipImplementations["NotChip"],
+		"AndChip":       testutils.ChipImplementations["AndChip"],
+		"OrChip":        testutils.ChipImplementations["OrChip"],
+		"DoubleDFFChip": testutils.ChipImplementations["DoubleDFFChip"],
+		"XorChip": `CHIP XorChip {
+IN a, b;
+OUT out;
+PARTS:
+OrChip(a = a, b = b, out = AOrB);
+Nand(a = a, b = b, out = ANandB);
+AndChip(a = AOrB, b = ANandB, out = out);
+}`,
+		"BlackBoxChip": `CHIP BlackBoxChip {
+IN a[16], b[16], sel;
+OUT out[16], notSel;
+PARTS:
+Mux16(a = a, b = b, sel = sel, out = muxOut);
+Not16(in = muxOut, out = out);
+Not(in = sel, out = notSel);
+}`,
+		"ClockedChip": `CHIP ClockedChip {
+IN in;
+OUT out;
+PARTS:
+DFF(in = in, out = dffOut);
+Not(in = dffOut, out = out);
+}`,
+	}
+
+	tests := []struct {
+		name     string
+		chipName string
+		delays   Delays
+		depths   map[string]map[string]int
+		depth    int
+		path     []PathStep
+		from     *PinBit
+		to       *PinBit
+	}{
+		{
+			name:     "Nested chips",
+			chipName: "XorChip",
+			delays:   DefaultDelays(),
+			depths:   map[string]map[string]int{"a": {"out": 4}, "b": {"out": 4}},
+			depth:    4,
+			path: []PathStep{
+				{
+					Chip: "Nand", Input: PinBit{Name: "a"}, Output: PinBit{Name: "out"}, Delay: 1,
+					Parts: []PartRef{{Chip: "OrChip", Line: 5, Column: 1}, {Chip: "NotChip", Line: 6, Column: 9}, {Chip: "Nand", Line: 6, Column: 9}},
+				},
+				{
+					Chip: "Nand", Input: PinBit{Name: "a"}, Output: PinBit{Name: "out"}, Delay: 1,
+					Parts: []PartRef{{Chip: "OrChip", Line: 5, Column: 1}, {Chip: "Nand", Line: 8, Column: 9}},
+				},
+				{
+					Chip: "Nand", Input: PinBit{Name: "a"}, Output: PinBit{Name: "out"}, Delay: 1,
+					Parts: []PartRef{{Chip: "AndChip", Line: 7, Column: 1}, {Chip: "Nand", Line: 6, Column: 9}},
+				},
+				{
+					Chip: "Nand", Input: PinBit{Name: "a"}, Output: PinBit{Name: "out"}, Delay: 1,
+					Parts: []PartRef{{Chip: "AndChip", Line: 7, Column: 1}, {Chip: "NotChip", Line: 7, Column: 9}, {Chip: "Nand", Line: 6, Column: 9}},
+				},
+			},
+			from: &PinBit{Name: "a"},
+			to:   &PinBit{Name: "out"},
+		},
+		{
+			name:     "Black boxes",
+			chipName: "BlackBoxChip",
+			delays:   Delays{"Mux16": 3, "Not16": 1},
+			depths: map[string]map[string]int{
+				"a":   {"out": 4},
+				"b":   {"out": 4},
+				"sel": {"out": 4, "notSel": 1},
+			},
+			depth: 4,
+			path: []PathStep{
+				{
+					Chip: "Mux16", Input: PinBit{Name: "a"}, Output: PinBit{Name: "out"}, Delay: 3,
+					Parts: []PartRef{{Chip: "Mux16", Line: 5, Column: 1}},
+				},
+				{
+					Chip: "Not16", Input: PinBit{Name: "in"}, Output: PinBit{Name: "out"}, Delay: 1,
+					Parts: []PartRef{{Chip: "Not16", Line: 6, Column: 1}},
+				},
+			},
+			from: &PinBit{Name: "a"},
+			to:   &PinBit{Name: "out"},
+		},
+		{
+			name:     "Sequential output starts a path",
+			chipName: "ClockedChip",
+			delays:   DefaultDelays(),
+			depths:   map[string]map[string]int{},
+			depth:    1,
+			path: []PathStep{
+				{
+					Chip: "Not", Input: PinBit{Name: "in"}, Output: PinBit{Name: "out"}, Delay: 1,
+					Parts: []PartRef{{Chip: "Not", Line: 6, Column: 1}},
+				},
+			},
+			to: &PinBit{Name: "out"},
+		},
+		{
+			name:     "No combinational path",
+			chipName: "DoubleDFFChip",
+			delays:   DefaultDelays(),
+			depths:   map[string]map[string]int{},
+			path:     []PathStep{},
+			to:       &PinBit{Name: "dff1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := mustBuildGraph(t, hdls, tt.chipName)
+			timing := AnalyzeTiming(g, tt.delays)
+			assert.Equal(t, tt.depths, timing.Depths)
+			assert.Equal(t, tt.depth, timing.Depth)
+			assert.Equal(t, tt.path, timing.CriticalPath)
+			assert.Equal(t, tt.from, timing.From)
+			assert.Equal(t, tt.to, timing.To)
+		})
+	}
+}
@@ -1,310 +1,417 @@
 package chips
 
-var BuiltInChips = map[string]Chip{
-	"Nand": {
-		Inputs: map[string]IO{
-			"a": {Width: 1},
-			"b": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 1},
-		},
-	},
-	"And": {
-		Inputs: map[string]IO{
-			"a": {Width: 1},
-			"b": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 1},
-		},
-	},
-	"Or": {
-		Inputs: map[string]IO{
-			"a": {Width: 1},
-			"b": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 1},
-		},
-	},
-	"Not": {
-		Inputs: map[string]IO{
-			"in": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 1},
-		},
-	},
-	"Xor": {
-		Inputs: map[string]IO{
-			"a": {Width: 1},
-			"b": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 1},
-		},
-	},
-	"Mux": {
-		Inputs: map[string]IO{
-			"a":   {Width: 1},
-			"b":   {Width: 1},
-			"sel": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 1},
-		},
-	},
-	"DMux": {
-		Inputs: map[string]IO{
-			"in":  {Width: 1},
-			"sel": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"a": {Width: 1},
-			"b": {Width: 1},
-		},
-	},
-	"DMux4Way": {
-		Inputs: map[string]IO{
-			"in":  {Width: 1},
-			"sel": {Width: 2},
-		},
-		Outputs: map[string]IO{
-			"a": {Width: 1},
-			"b": {Width: 1},
-			"c": {Width: 1},
-			"d": {Width: 1},
-		},
-	},
-	"DMux8Way": {
-		Inputs: map[string]IO{
-			"in":  {Width: 1},
-			"sel": {Width: 3},
-		},
-		Outputs: map[string]IO{
-			"a": {Width: 1},
-			"b": {Width: 1},
-			"c": {Width: 1},
-			"d": {Width: 1},
-			"e": {Width: 1},
-			"f": {Width: 1},
-			"g": {Width: 1},
-			"h": {Width: 1},
-		},
-	},
-	"And16": {
-		Inputs: map[string]IO{
-			"a": {Width: 16},
-			"b": {Width: 16},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"Or16": {
-		Inputs: map[string]IO{
-			"a": {Width: 16},
-			"b": {Width: 16},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"Not16": {
-		Inputs: map[string]IO{
-			"in": {Width: 16},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"Or8Way": {
-		Inputs: map[string]IO{
-			"in": {Width: 8},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 1},
-		},
-	},
-	"Mux16": {
-		Inputs: map[string]IO{
-			"a":   {Width: 16},
-			"b":   {Width: 16},
-			"sel": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"Mux4Way16": {
-		Inputs: map[string]IO{
-			"a":   {Width: 16},
-			"b":   {Width: 16},
-			"c":   {Width: 16},
-			"d":   {Width: 16},
-			"sel": {Width: 2},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"Mux8Way16": {
-		Inputs: map[string]IO{
-			"a":   {Width: 16},
-			"b":   {Width: 16},
-			"c":   {Width: 16},
-			"d":   {Width: 16},
-			"e":   {Width: 16},
-			"f":   {Width: 16},
-			"g":   {Width: 16},
-			"h":   {Width: 16},
-			"sel": {Width: 3},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"HalfAdder": {
-		Inputs: map[string]IO{
-			"a": {Width: 1},
-			"b": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"sum":   {Width: 1},
-			"carry": {Width: 1},
-		},
-	},
-	"FullAdder": {
-		Inputs: map[string]IO{
-			"a": {Width: 1},
-			"b": {Width: 1},
-			"c": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"sum":   {Width: 1},
-			"carry": {Width: 1},
-		},
-	},
-	"Add16": {
-		Inputs: map[string]IO{
-			"a": {Width: 16},
-			"b": {Width: 16},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"DFF": {
-		Inputs: map[string]IO{
-			"in": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 1},
-		},
-	},
-	"Bit": {
-		Inputs: map[string]IO{
-			"in":   {Width: 1},
-			"load": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 1},
-		},
-	},
-	"Register": {
-		Inputs: map[string]IO{
-			"in":   {Width: 16},
-			"load": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"Inc16": {
-		Inputs: map[string]IO{
-			"in": {Width: 16},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"PC": {
-		Inputs: map[string]IO{
-			"in":    {Width: 16},
-			"load":  {Width: 1},
-			"inc":   {Width: 1},
-			"reset": {Width: 1},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"RAM8": {
-		Inputs: map[string]IO{
-			"in":      {Width: 16},
-			"load":    {Width: 1},
-			"address": {Width: 3},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"RAM64": {
-		Inputs: map[string]IO{
-			"in":      {Width: 16},
-			"load":    {Width: 1},
-			"address": {Width: 6},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"RAM512": {
-		Inputs: map[string]IO{
-			"in":      {Width: 16},
-			"load":    {Width: 1},
-			"address": {Width: 9},
-		},
-		Outputs: map[string]IO{
-			"out": {Width: 16},
-		},
-	},
-	"RAM4K": {
+// base implements the parts of BuiltinChip that only describe the chip.
+type base struct {
+	name        string
+	description string
+	signature   Chip
+}
+
+func (b base) Name() string {
+	return b.name
+}
+
+func (b base) Signature() Chip {
+	return b.signature
+}
+
+func (b base) GUIMetadata() GUIMetadata {
+	return GUIMetadata{Description: b.description}
+}
+
+// combinational implements the state handling of chips without internal state.
+type combinational struct{}
+
+func (combinational) IsSequentialBit(output string, bit int) bool {
+	return false
+}
+
+func (combinational) Init() State {
+	return nil
+}
+
+func (combinational) Commit(pins Pins, state State) {}
+
+func (combinational) Apply(pins Pins, state State) {}
+
+func defaultChips() []BuiltinChip {
+	return []BuiltinChip{
+		nand{base: base{name: "Nand", description: "Nand gate", signature: twoInputGate(1)}},
+		and{base: base{name: "And", description: "And gate", signature: twoInputGate(1)}},
+		or{base: base{name: "Or", description: "Or gate", signature: twoInputGate(1)}},
+		not{base: base{name: "Not", description: "Not gate", signature: oneInputGate(1)}},
+		xor{base: base{name: "Xor", description: "Xor gate", signature: twoInputGate(1)}},
+		mux{base: base{name: "Mux", description: "Selects between two inputs", signature: Chip{
+			Inputs: map[string]IO{
+				"a":   {Width: 1},
+				"b":   {Width: 1},
+				"sel": {Width: 1},
+			},
+			Outputs: map[string]IO{
+				"out": {Width: 1},
+			},
+		}}},
+		dmux{base: base{name: "DMux", description: "Routes the input to one of two outputs", signature: Chip{
+			Inputs: map[string]IO{
+				"in":  {Width: 1},
+				"sel": {Width: 1},
+			},
+			Outputs: map[string]IO{
+				"a": {Width: 1},
+				"b": {Width: 1},
+			},
+		}}},
+		dmuxNWay{base: base{name: "DMux4Way", description: "Routes the input to one of four outputs", signature: Chip{
+			Inputs: map[string]IO{
+				"in":  {Width: 1},
+				"sel": {Width: 2},
+			},
+			Outputs: map[string]IO{
+				"a": {Width: 1},
+				"b": {Width: 1},
+				"c": {Width: 1},
+				"d": {Width: 1},
+			},
+		}}, outputs: []string{"a", "b", "c", "d"}},
+		dmuxNWay{base: base{name: "DMux8Way", description: "Routes the input to one of eight outputs", signature: Chip{
+			Inputs: map[string]IO{
+				"in":  {Width: 1},
+				"sel": {Width: 3},
+			},
+			Outputs: map[string]IO{
+				"a": {Width: 1},
+				"b": {Width: 1},
+				"c": {Width: 1},
+				"d": {Width: 1},
+				"e": {Width: 1},
+				"f": {Width: 1},
+				"g": {Width: 1},
+				"h": {Width: 1},
+			},
+		}}, outputs: []string{"a", "b", "c", "d", "e", "f", "g", "h"}},
+		and{base: base{name: "And16", description: "16-bit And", signature: twoInputGate(16)}},
+		or{base: base{name: "Or16", description: "16-bit Or", signature: twoInputGate(16)}},
+		not{base: base{name: "Not16", description: "16-bit Not", signature: oneInputGate(16)}},
+		or8Way{base: base{name: "Or8Way", description: "8-way Or", signature: Chip{
+			Inputs: map[string]IO{
+				"in": {Width: 8},
+			},
+			Outputs: map[string]IO{
+				"out": {Width: 1},
+			},
+		}}},
+		muxNWay{base: base{name: "Mux16", description: "Selects between two 16-bit inputs", signature: Chip{
+			Inputs: map[string]IO{
+				"a":   {Width: 16},
+				"b":   {Width: 16},
+				"sel": {Width: 1},
+			},
+			Outputs: map[string]IO{
+				"out": {Width: 16},
+			},
+		}}, inputs: []string{"a", "b"}},
+		muxNWay{base: base{name: "Mux4Way16", description: "Selects between four 16-bit inputs", signature: Chip{
+			Inputs: map[string]IO{
+				"a":   {Width: 16},
+				"b":   {Width: 16},
+				"c":   {Width: 16},
+				"d":   {Width: 16},
+				"sel": {Width: 2},
+			},
+			Outputs: map[string]IO{
+				"out": {Width: 16},
+			},
+		}}, inputs: []string{"a", "b", "c", "d"}},
+		muxNWay{base: base{name: "Mux8Way16", description: "Selects between eight 16-bit inputs", signature: Chip{
+			Inputs: map[string]IO{
+				"a":   {Width: 16},
+				"b":   {Width: 16},
+				"c":   {Width: 16},
+				"d":   {Width: 16},
+				"e":   {Width: 16},
+				"f":   {Width: 16},
+				"g":   {Width: 16},
+				"h":   {Width: 16},
+				"sel": {Width: 3},
+			},
+			Outputs: map[string]IO{
+				"out": {Width: 16},
+			},
+		}}, inputs: []string{"a", "b", "c", "d", "e", "f", "g", "h"}},
+		halfAdder{base: base{name: "HalfAdder", description: "Adds up two bits", signature: Chip{
+			Inputs: map[string]IO{
+				"a": {Width: 1},
+				"b": {Width: 1},
+			},
+			Outputs: map[string]IO{
+				"sum":   {Width: 1},
+				"carry": {Width: 1},
+			},
+		}}},
+		fullAdder{base: base{name: "FullAdder", description: "Adds up three bits", signature: Chip{
+			Inputs: map[string]IO{
+				"a": {Width: 1},
+				"b": {Width: 1},
+				"c": {Width: 1},
+			},
+			Outputs: map[string]IO{
+				"sum":   {Width: 1},
+				"carry": {Width: 1},
+			},
+		}}},
+		add16{base: base{name: "Add16", description: "Adds up two 16-bit two's complement values", signature: twoInputGate(16)}},
+		inc16{base: base{name: "Inc16", description: "Sets out to in + 1", signature: oneInputGate(16)}},
+		dff{base: base{name: "DFF", description: "Data flip-flop gate", signature: Chip{
+			Inputs: map[string]IO{
+				"in": {Width: 1},
+			},
+			Outputs: map[string]IO{
+				"out": {Width: 1},
+			},
+		}}},
+		NewRegister("Bit", "1-bit register", 1),
+		NewRegister("Register", "16-bit register", 16),
+		pc{base: base{name: "PC", description: "Program Counter", signature: Chip{
+			Inputs: map[string]IO{
+				"in":    {Width: 16},
+				"load":  {Width: 1},
+				"inc":   {Width: 1},
+				"reset": {Width: 1},
+			},
+			Outputs: map[string]IO{
+				"out": {Width: 16},
+			},
+		}}},
+		newRAM("RAM8", "8-word RAM", 8, 3, false),
+		newRAM("RAM64", "64-word RAM", 64, 6, true),
+		newRAM("RAM512", "512-word RAM", 512, 9, false),
+		newRAM("RAM4K", "4K RAM", 4096, 12, false),
+		newRAM("RAM16K", "16K-word RAM", 16384, 14, false),
+	}
+}
+
+func oneInputGate(width int) Chip {
+	return Chip{
 		Inputs: map[string]IO{
-			"in":      {Width: 16},
-			"load":    {Width: 1},
-			"address": {Width: 12},
+			"in": {Width: width},
 		},
 		Outputs: map[string]IO{
-			"out": {Width: 16},
+			"out": {Width: width},
 		},
-	},
-	"RAM16K": {
+	}
+}
+
+func twoInputGate(width int) Chip {
+	return Chip{
 		Inputs: map[string]IO{
-			"in":      {Width: 16},
-			"load":    {Width: 1},
-			"address": {Width: 14},
+			"a": {Width: width},
+			"b": {Width: width},
 		},
 		Outputs: map[string]IO{
-			"out": {Width: 16},
+			"out": {Width: width},
 		},
-	},
+	}
+}
+
+// nand, and, or, not work bitwise on pins of any width.
+type nand struct {
+	base
+	combinational
+}
+
+func (nand) Evaluate(pins Pins, state State) {
+	a, b, out := pins.Input("a"), pins.Input("b"), pins.Output("out")
+	for i := range out.Len() {
+		out.Set(i, !(a.Get(i) && b.Get(i)))
+	}
+}
+
+type and struct {
+	base
+	combinational
+}
+
+func (and) Evaluate(pins Pins, state State) {
+	a, b, out := pins.Input("a"), pins.Input("b"), pins.Output("out")
+	for i := range out.Len() {
+		out.Set(i, a.Get(i) && b.Get(i))
+	}
+}
+
+type or struct {
+	base
+	combinational
+}
+
+func (or) Evaluate(pins Pins, state State) {
+	a, b, out := pins.Input("a"), pins.Input("b"), pins.Output("out")
+	for i := range out.Len() {
+		out.Set(i, a.Get(i) || b.Get(i))
+	}
+}
+
+type not struct {
+	base
+	combinational
 }
 
-func IsSequentialBit(chipName string, signalName string, bitIndex int) bool {
-	if _, exists := BuiltInChips[chipName]; !exists {
-		return false
+func (not) Evaluate(pins Pins, state State) {
+	in, out := pins.Input("in"), pins.Output("out")
+	for i := range out.Len() {
+		out.Set(i, !in.Get(i))
 	}
+}
+
+type xor struct {
+	base
+	combinational
+}
+
+func (xor) Evaluate(pins Pins, state State) {
+	a := pins.Input("a").Get(0)
+	b := pins.Input("b").Get(0)
+	pins.Output("out").Set(0, (a || b) && !(a && b))
+}
+
+type mux struct {
+	base
+	combinational
+}
+
+func (mux) Evaluate(pins Pins, state State) {
+	a := pins.Input("a").Get(0)
+	b := pins.Input("b").Get(0)
+	sel := pins.Input("sel").Get(0)
+	if sel {
+		pins.Output("out").Set(0, b)
+	} else {
+		pins.Output("out").Set(0, a)
+	}
+}
+
+type dmux struct {
+	base
+	combinational
+}
+
+func (dmux) Evaluate(pins Pins, state State) {
+	in := pins.Input("in").Get(0)
+	sel := pins.Input("sel").Get(0)
+	pins.Output("a").Set(0, in && !sel)
+	pins.Output("b").Set(0, in && sel)
+}
+
+// dmuxNWay routes the input to the output selected by sel, the others are set to 0.
+type dmuxNWay struct {
+	base
+	combinational
+	outputs []string
+}
+
+func (d dmuxNWay) Evaluate(pins Pins, state State) {
+	in := pins.Input("in").Get(0)
+	address := ToInt(pins.Input("sel"))
+	for i, output := range d.outputs {
+		pins.Output(output).Set(0, i == address && in)
+	}
+}
+
+type or8Way struct {
+	base
+	combinational
+}
+
+func (or8Way) Evaluate(pins Pins, state State) {
+	in := pins.Input("in")
+	result := false
+	for i := range in.Len() {
+		result = result || in.Get(i)
+	}
+	pins.Output("out").Set(0, result)
+}
+
+// muxNWay copies the input selected by sel to the output.
+type muxNWay struct {
+	base
+	combinational
+	inputs []string
+}
+
+func (m muxNWay) Evaluate(pins Pins, state State) {
+	selected := pins.Input(m.inputs[ToInt(pins.Input("sel"))])
+	out := pins.Output("out")
+	for i := range out.Len() {
+		out.Set(i, selected.Get(i))
+	}
+}
+
+type halfAdder struct {
+	base
+	combinational
+}
+
+func (halfAdder) Evaluate(pins Pins, state State) {
+	a := pins.Input("a").Get(0)
+	b := pins.Input("b").Get(0)
+	pins.Output("sum").Set(0, a != b)
+	pins.Output("carry").Set(0, a && b)
+}
+
+type fullAdder struct {
+	base
+	combinational
+}
+
+func (fullAdder) Evaluate(pins Pins, state State) {
+	a := pins.Input("a").Get(0)
+	b := pins.Input("b").Get(0)
+	c := pins.Input("c").Get(0)
+	pins.Output("sum").Set(0, (a != b) != c)
+	pins.Output("carry").Set(0, (a && b) || (c && (a != b)))
+}
+
+type add16 struct {
+	base
+	combinational
+}
+
+func (add16) Evaluate(pins Pins, state State) {
+	aBits, bBits, out := pins.Input("a"), pins.Input("b"), pins.Output("out")
+	carry := false
+	for i := range out.Len() {
+		a := aBits.Get(i)
+		b := bBits.Get(i)
+
+		sum := (a != b) != carry
+		carry = (a && b) || (carry && (a != b))
+		out.Set(i, sum)
+	}
+}
+
+type inc16 struct {
+	base
+	combinational
+}
+
+func (inc16) Evaluate(pins Pins, state State) {
+	inBits, out := pins.Input("in"), pins.Output("out")
+	carry := true
+	for i := range out.Len() {
+		in := inBits.Get(i)
+
+		sum := (in != carry)
+		carry = in && carry
+		out.Set(i, sum)
+	}
+}
 
-	switch chipName {
-	case "DFF":
-		return signalName == "out" && bitIndex == 0
-	case "Bit":
-		return signalName == "out" && bitIndex == 0
-	case "RAM64":
-		return signalName == "out" && bitIndex >= 0 && bitIndex < 16
-	default:
-		return false
+// ToInt interprets the bits as an unsigned number, bit 0 being the least significant.
+func ToInt(bits Bits) int {
+	value := 0
+	for i := range bits.Len() {
+		if bits.Get(i) {
+			value |= (1 << i)
+		}
 	}
+	return value
 }
@@ -8,3 +8,68 @@ type Chip struct {
 type IO struct {
 	Width int
 }
+
+// Bits gives a built-in chip access to the bits of one of its pins.
+type Bits interface {
+	Len() int
+	Get(i int) bool
+	// Set sets a known value, clearing the unknown flag of the bit.
+	Set(i int, value bool)
+	// IsUnknown and SetUnknown are only meaningful in four-valued simulation,
+	// where a bit is 0, 1 or X (unknown).
+	IsUnknown(i int) bool
+	SetUnknown(i int)
+}
+
+// Pins gives a built-in chip access to the pins of one of its instances.
+type Pins interface {
+	Input(name string) Bits
+	Output(name string) Bits
+}
+
+// State is the internal state of a sequential chip instance, nil for combinational chips.
+type State = map[string][]bool
+
+// BuiltinChip is a chip implemented in Go. Each instance of the chip in a graph
+// gets its own State from Init, which is passed back on every call.
+type BuiltinChip interface {
+	Name() string
+	// Signature returns the inputs and outputs of the chip.
+	Signature() Chip
+	// IsSequentialBit reports whether the output bit depends only on the state,
+	// so it does not create a combinational dependency on the inputs.
+	IsSequentialBit(output string, bit int) bool
+	// Init returns the initial state of a new instance.
+	Init() State
+	// Evaluate sets the output pins based on the input pins
+	// or in case of sequential chips, based on the committed state.
+	Evaluate(pins Pins, state State)
+	// Commit updates the state based on the input pins.
+	Commit(pins Pins, state State)
+	// Apply sets the output pins based on the currently committed state.
+	Apply(pins Pins, state State)
+}
+
+// UnknownEvaluator is implemented by built-in chips that handle unknown (X) bits
+// in four-valued simulation. Other chips are handled conservatively: every
+// combinational output is unknown as soon as an input is unknown, and the whole
+// state becomes unknown when a commit sees an unknown input.
+//
+// unknown has the layout of the state returned by Init and marks its unknown
+// bits. Initially every state bit of every chip is unknown, as nothing has been
+// stored yet, so the state of the other chips stays unknown.
+type UnknownEvaluator interface {
+	EvaluateUnknown(pins Pins, state, unknown State)
+	CommitUnknown(pins Pins, state, unknown State)
+	ApplyUnknown(pins Pins, state, unknown State)
+}
+
+// GUIMetadata describes a built-in chip for the GUI.
+type GUIMetadata struct {
+	Description string
+}
+
+// GUIMetadataProvider is implemented by built-in chips that describe themselves for the GUI.
+type GUIMetadataProvider interface {
+	GUIMetadata() GUIMetadata
+}
@@ -0,0 +1,78 @@
+package chips
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Registry holds the built-in chips available to the simulator.
+type Registry struct {
+	chips map[string]BuiltinChip
+}
+
+func NewRegistry() *Registry {
+	return &Registry{chips: make(map[string]BuiltinChip)}
+}
+
+// NewDefaultRegistry returns a registry with the built-in chips of the nand2tetris course.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, chip := range defaultChips() {
+		if err := r.Register(chip); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds a chip to the registry after checking that its signature is consistent.
+func (r *Registry) Register(chip BuiltinChip) error {
+	name := chip.Name()
+	if name == "" {
+		return fmt.Errorf("built-in chip name must not be empty")
+	}
+	if _, exists := r.chips[name]; exists {
+		return fmt.Errorf("built-in chip '%s' is already registered", name)
+	}
+
+	signature := chip.Signature()
+	if len(signature.Outputs) == 0 {
+		return fmt.Errorf("built-in chip '%s' has no outputs", name)
+	}
+	for inputName, input := range signature.Inputs {
+		if input.Width < 1 {
+			return fmt.Errorf("input '%s' of built-in chip '%s' has invalid width %d", inputName, name, input.Width)
+		}
+		if _, exists := signature.Outputs[inputName]; exists {
+			return fmt.Errorf("pin '%s' of built-in chip '%s' is both an input and an output", inputName, name)
+		}
+	}
+	for outputName, output := range signature.Outputs {
+		if output.Width < 1 {
+			return fmt.Errorf("output '%s' of built-in chip '%s' has invalid width %d", outputName, name, output.Width)
+		}
+	}
+
+	r.chips[name] = chip
+	return nil
+}
+
+func (r *Registry) Lookup(name string) (BuiltinChip, bool) {
+	chip, ok := r.chips[name]
+	return chip, ok
+}
+
+func (r *Registry) Has(name string) bool {
+	_, ok := r.chips[name]
+	return ok
+}
+
+// Names returns the names of the registered chips in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.chips))
+	for name := range r.chips {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,108 @@
+package chips
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testChip struct {
+	combinational
+	name      string
+	signature Chip
+}
+
+func (c testChip) Name() string {
+	return c.name
+}
+
+func (c testChip) Signature() Chip {
+	return c.signature
+}
+
+func (c testChip) Evaluate(pins Pins, state State) {}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name          string
+		chip          BuiltinChip
+		expectedError string
+	}{
+		{
+			name: "Valid chip",
+			chip: testChip{name: "Nor", signature: twoInputGate(1)},
+		},
+		{
+			name:          "Empty name",
+			chip:          testChip{signature: twoInputGate(1)},
+			expectedError: "built-in chip name must not be empty",
+		},
+		{
+			name:          "Already registered",
+			chip:          NewRegister("Register", "16-bit register", 16),
+			expectedError: "built-in chip 'Register' is already registered",
+		},
+		{
+			name:          "No outputs",
+			chip:          testChip{name: "Sink", signature: Chip{Inputs: map[string]IO{"in": {Width: 1}}}},
+			expectedError: "built-in chip 'Sink' has no outputs",
+		},
+		{
+			name:          "Invalid input width",
+			chip:          NewRegister("ZeroRegister", "", 0),
+			expectedError: "input 'in' of built-in chip 'ZeroRegister' has invalid width 0",
+		},
+		{
+			name: "Pin is both input and output",
+			chip: testChip{name: "Loop", signature: Chip{
+				Inputs:  map[string]IO{"x": {Width: 1}},
+				Outputs: map[string]IO{"x": {Width: 1}},
+			}},
+			expectedError: "pin 'x' of built-in chip 'Loop' is both an input and an output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewDefaultRegistry()
+			err := r.Register(tt.chip)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			chip, ok := r.Lookup(tt.chip.Name())
+			assert.True(t, ok)
+			assert.Equal(t, tt.chip, chip)
+		})
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	r := NewDefaultRegistry()
+	assert.Len(t, r.Names(), 29)
+
+	for _, name := range r.Names() {
+		chip, _ := r.Lookup(name)
+		assert.Equal(t, name, chip.Name())
+		gui, ok := chip.(GUIMetadataProvider)
+		if assert.True(t, ok, "chip %s has no GUI metadata", name) {
+			assert.NotEmpty(t, gui.GUIMetadata().Description)
+		}
+	}
+}
+
+func TestDefaultRegistrySequentialBits(t *testing.T) {
+	// Register and PC are sequential so that a CPU can feed them back
+	sequential := map[string]bool{"DFF": true, "Bit": true, "Register": true, "PC": true, "RAM64": true}
+
+	r := NewDefaultRegistry()
+	for _, name := range r.Names() {
+		chip, _ := r.Lookup(name)
+		for output, io := range chip.Signature().Outputs {
+			for i := range io.Width {
+				assert.Equal(t, sequential[name], chip.IsSequentialBit(output, i), "%s %s[%d]", name, output, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package chips
+
+import "strconv"
+
+// clocked implements the parts of BuiltinChip shared by chips whose "out" pin
+// is driven only by the committed state, so it can be fed back to their inputs.
+// Before the registry only DFF and Bit were marked sequential, but a CPU feeds
+// the outputs of Register and PC back to them, so every register is.
+type clocked struct{}
+
+func (clocked) IsSequentialBit(output string, bit int) bool {
+	return output == "out"
+}
+
+// Evaluate does nothing, the output only changes when the state is applied.
+func (clocked) Evaluate(pins Pins, state State) {}
+
+func (clocked) Apply(pins Pins, state State) {
+	out := pins.Output("out")
+	for i := range out.Len() {
+		out.Set(i, state["out"][i])
+	}
+}
+
+type dff struct {
+	base
+	clocked
+}
+
+func (dff) Init() State {
+	return State{"out": {false}}
+}
+
+func (dff) Commit(pins Pins, state State) {
+	state["out"][0] = pins.Input("in").Get(0)
+}
+
+// register stores the input when load is set, it implements both Bit and Register.
+type register struct {
+	base
+	clocked
+	width int
+}
+
+// NewRegister returns a register chip with the IO of Register (in, load, out)
+// and the given width, e.g. for course-specific chips like ARegister.
+func NewRegister(name, description string, width int) BuiltinChip {
+	return register{
+		base: base{name: name, description: description, signature: Chip{
+			Inputs: map[string]IO{
+				"in":   {Width: width},
+				"load": {Width: 1},
+			},
+			Outputs: map[string]IO{
+				"out": {Width: width},
+			},
+		}},
+		width: width,
+	}
+}
+
+func (r register) Init() State {
+	return State{"out": make([]bool, r.width)}
+}
+
+func (r register) Commit(pins Pins, state State) {
+	if !pins.Input("load").Get(0) {
+		return // do not store if load is false
+	}
+
+	in := pins.Input("in")
+	for i := range r.width {
+		state["out"][i] = in.Get(i)
+	}
+}
+
+type pc struct {
+	base
+	clocked
+}
+
+func (pc) Init() State {
+	return State{"out": make([]bool, 16)}
+}
+
+func (pc) Commit(pins Pins, state State) {
+	out := state["out"]
+	if pins.Input("reset").Get(0) {
+		for i := range out {
+			out[i] = false
+		}
+		return
+	}
+	if pins.Input("load").Get(0) {
+		in := pins.Input("in")
+		for i := range out {
+			out[i] = in.Get(i)
+		}
+		return
+	}
+	if pins.Input("inc").Get(0) {
+		carry := true
+		for i := range out {
+			in := out[i]
+			sum := (in != carry)
+			carry = in && carry
+			out[i] = sum
+		}
+	}
+}
+
+// ram is a memory of 16-bit words, the word at address i is stored under "out_i".
+// Its output also depends on the address, so it is only marked sequential if
+// sequential is set, which only RAM64 does, as before the registry.
+type ram struct {
+	base
+	size       int
+	sequential bool
+}
+
+func newRAM(name, description string, size, addressWidth int, sequential bool) ram {
+	return ram{
+		base: base{name: name, description: description, signature: Chip{
+			Inputs: map[string]IO{
+				"in":      {Width: 16},
+				"load":    {Width: 1},
+				"address": {Width: addressWidth},
+			},
+			Outputs: map[string]IO{
+				"out": {Width: 16},
+			},
+		}},
+		size:       size,
+		sequential: sequential,
+	}
+}
+
+func (r ram) IsSequentialBit(output string, bit int) bool {
+	return r.sequential && output == "out"
+}
+
+func (r ram) Init() State {
+	state := make(State, r.size)
+	for i := range r.size {
+		state["out_"+strconv.Itoa(i)] = make([]bool, 16)
+	}
+	return state
+}
+
+func (r ram) Evaluate(pins Pins, state State) {
+	r.Apply(pins, state)
+}
+
+func (r ram) Commit(pins Pins, state State) {
+	if !pins.Input("load").Get(0) {
+		return
+	}
+
+	word := state["out_"+strconv.Itoa(ToInt(pins.Input("address")))]
+	in := pins.Input("in")
+	for i := range word {
+		word[i] = in.Get(i)
+	}
+}
+
+func (r ram) Apply(pins Pins, state State) {
+	word := state["out_"+strconv.Itoa(ToInt(pins.Input("address")))]
+	out := pins.Output("out")
+	for i := range word {
+		out.Set(i, word[i])
+	}
+}
@@ -0,0 +1,227 @@
+package chips
+
+import "strconv"
+
+// Four-valued implementations of the default chips. A bit is read as a value
+// and a known flag, a bit that is not known is X.
+
+func getBit(bits Bits, i int) (value, known bool) {
+	return bits.Get(i), !bits.IsUnknown(i)
+}
+
+func setBit(bits Bits, i int, value, known bool) {
+	if known {
+		bits.Set(i, value)
+	} else {
+		bits.SetUnknown(i)
+	}
+}
+
+func and3(a, aKnown, b, bKnown bool) (bool, bool) {
+	if (aKnown && !a) || (bKnown && !b) {
+		return false, true
+	}
+	return a && b, aKnown && bKnown
+}
+
+func or3(a, aKnown, b, bKnown bool) (bool, bool) {
+	if (aKnown && a) || (bKnown && b) {
+		return true, true
+	}
+	return a || b, aKnown && bKnown
+}
+
+// merge combines two possible values of a bit, it stays known only if they agree.
+func merge(a, aKnown, b, bKnown bool) (bool, bool) {
+	return a, aKnown && bKnown && a == b
+}
+
+// candidates returns the indexes in [0, n) that match the known bits of sel.
+func candidates(sel Bits, n int) []int {
+	var result []int
+	for index := range n {
+		matches := true
+		for i := range sel.Len() {
+			value, known := getBit(sel, i)
+			if known && value != (index>>i&1 == 1) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, index)
+		}
+	}
+	return result
+}
+
+func (combinational) CommitUnknown(pins Pins, state, unknown State) {}
+
+func (combinational) ApplyUnknown(pins Pins, state, unknown State) {}
+
+func (nand) EvaluateUnknown(pins Pins, state, unknown State) {
+	a, b, out := pins.Input("a"), pins.Input("b"), pins.Output("out")
+	for i := range out.Len() {
+		aValue, aKnown := getBit(a, i)
+		bValue, bKnown := getBit(b, i)
+		value, known := and3(aValue, aKnown, bValue, bKnown)
+		setBit(out, i, !value, known)
+	}
+}
+
+func (and) EvaluateUnknown(pins Pins, state, unknown State) {
+	a, b, out := pins.Input("a"), pins.Input("b"), pins.Output("out")
+	for i := range out.Len() {
+		aValue, aKnown := getBit(a, i)
+		bValue, bKnown := getBit(b, i)
+		value, known := and3(aValue, aKnown, bValue, bKnown)
+		setBit(out, i, value, known)
+	}
+}
+
+func (or) EvaluateUnknown(pins Pins, state, unknown State) {
+	a, b, out := pins.Input("a"), pins.Input("b"), pins.Output("out")
+	for i := range out.Len() {
+		aValue, aKnown := getBit(a, i)
+		bValue, bKnown := getBit(b, i)
+		value, known := or3(aValue, aKnown, bValue, bKnown)
+		setBit(out, i, value, known)
+	}
+}
+
+func (not) EvaluateUnknown(pins Pins, state, unknown State) {
+	in, out := pins.Input("in"), pins.Output("out")
+	for i := range out.Len() {
+		value, known := getBit(in, i)
+		setBit(out, i, !value, known)
+	}
+}
+
+func (mux) EvaluateUnknown(pins Pins, state, unknown State) {
+	muxNWay{inputs: []string{"a", "b"}}.EvaluateUnknown(pins, state, unknown)
+}
+
+// EvaluateUnknown copies the selected input, an output bit stays known with an
+// unknown sel only if every input that could be selected agrees on it.
+func (m muxNWay) EvaluateUnknown(pins Pins, state, unknown State) {
+	selected := candidates(pins.Input("sel"), len(m.inputs))
+	out := pins.Output("out")
+	for i := range out.Len() {
+		value, known := getBit(pins.Input(m.inputs[selected[0]]), i)
+		for _, index := range selected[1:] {
+			otherValue, otherKnown := getBit(pins.Input(m.inputs[index]), i)
+			value, known = merge(value, known, otherValue, otherKnown)
+		}
+		setBit(out, i, value, known)
+	}
+}
+
+// Sequential chips keep the unknown flags of their state next to it.
+
+func (clocked) EvaluateUnknown(pins Pins, state, unknown State) {}
+
+func (clocked) ApplyUnknown(pins Pins, state, unknown State) {
+	out := pins.Output("out")
+	for i := range out.Len() {
+		setBit(out, i, state["out"][i], !unknown["out"][i])
+	}
+}
+
+func (dff) CommitUnknown(pins Pins, state, unknown State) {
+	value, known := getBit(pins.Input("in"), 0)
+	state["out"][0] = value
+	unknown["out"][0] = !known
+}
+
+func (r register) CommitUnknown(pins Pins, state, unknown State) {
+	load, loadKnown := getBit(pins.Input("load"), 0)
+	if loadKnown && !load {
+		return
+	}
+
+	in := pins.Input("in")
+	for i := range r.width {
+		value, known := getBit(in, i)
+		if !loadKnown {
+			// either stored or kept
+			value, known = merge(value, known, state["out"][i], !unknown["out"][i])
+		}
+		state["out"][i] = value
+		unknown["out"][i] = !known
+	}
+}
+
+func (pc) CommitUnknown(pins Pins, state, unknown State) {
+	out, outUnknown := state["out"], unknown["out"]
+	reset, resetKnown := getBit(pins.Input("reset"), 0)
+	load, loadKnown := getBit(pins.Input("load"), 0)
+	inc, incKnown := getBit(pins.Input("inc"), 0)
+
+	switch {
+	case resetKnown && reset:
+		for i := range out {
+			out[i], outUnknown[i] = false, false
+		}
+	case resetKnown && loadKnown && load:
+		in := pins.Input("in")
+		for i := range out {
+			value, known := getBit(in, i)
+			out[i], outUnknown[i] = value, !known
+		}
+	case resetKnown && loadKnown && incKnown && inc:
+		carry, carryKnown := true, true
+		for i := range out {
+			value, known := out[i], !outUnknown[i]
+			sum, sumKnown := value != carry, known && carryKnown
+			carry, carryKnown = and3(value, known, carry, carryKnown)
+			out[i], outUnknown[i] = sum, !sumKnown
+		}
+	case resetKnown && loadKnown && incKnown:
+		// nothing to do, every control bit is 0
+	default:
+		// a control bit is unknown, the next value cannot be told
+		for i := range outUnknown {
+			outUnknown[i] = true
+		}
+	}
+}
+
+func (r ram) EvaluateUnknown(pins Pins, state, unknown State) {
+	r.ApplyUnknown(pins, state, unknown)
+}
+
+func (r ram) CommitUnknown(pins Pins, state, unknown State) {
+	load, loadKnown := getBit(pins.Input("load"), 0)
+	if loadKnown && !load {
+		return
+	}
+
+	addresses := candidates(pins.Input("address"), r.size)
+	in := pins.Input("in")
+	for _, address := range addresses {
+		key := "out_" + strconv.Itoa(address)
+		word, wordUnknown := state[key], unknown[key]
+		for i := range word {
+			value, known := getBit(in, i)
+			if !loadKnown || len(addresses) > 1 {
+				// either stored or kept
+				value, known = merge(value, known, word[i], !wordUnknown[i])
+			}
+			word[i], wordUnknown[i] = value, !known
+		}
+	}
+}
+
+func (r ram) ApplyUnknown(pins Pins, state, unknown State) {
+	addresses := candidates(pins.Input("address"), r.size)
+	out := pins.Output("out")
+	for i := range out.Len() {
+		key := "out_" + strconv.Itoa(addresses[0])
+		value, known := state[key][i], !unknown[key][i]
+		for _, address := range addresses[1:] {
+			key = "out_" + strconv.Itoa(address)
+			value, known = merge(value, known, state[key][i], !unknown[key][i])
+		}
+		setBit(out, i, value, known)
+	}
+}
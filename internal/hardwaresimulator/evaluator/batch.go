@@ -0,0 +1,42 @@
+package evaluator
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+)
+
+// EvaluateBatch evaluates independent input vectors on copies of the graph,
+// splitting them between workers that run in parallel, and returns the outputs
+// in the order of the vectors. Every vector is evaluated from the current state
+// of the graph without clocking it, and the graph itself is left untouched.
+// workers <= 0 uses one worker per CPU.
+func EvaluateBatch(g *graphbuilder.Graph, vectors []map[string][]bool, workers int) []map[string][]bool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(vectors))
+
+	results := make([]map[string][]bool, len(vectors))
+	var wg sync.WaitGroup
+	for i := range workers {
+		start := i * len(vectors) / workers
+		end := (i + 1) * len(vectors) / workers
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := New(g.Clone())
+			e.InitializeNodeStates()
+			for j := start; j < end; j++ {
+				e.SetInputs(vectors[j])
+				e.Evaluate()
+				results[j], _ = e.GetOutputsAndInternalPins()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
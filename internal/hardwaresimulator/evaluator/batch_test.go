@@ -0,0 +1,66 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateBatch(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip": testutils.ChipImplementations["NotChip"],
+		"AndChip": testutils.ChipImplementations["AndChip"],
+		"OrChip":  testutils.ChipImplementations["OrChip"],
+		"XorChip": testutils.ChipImplementations["XorChip"],
+		"Adder": `CHIP Adder {
+			IN a[8], b[8];
+			OUT out[8], xor;
+
+			PARTS:
+			Add16(a[0..7] = a, b[0..7] = b, out[0..7] = out);
+			XorChip(a = a[0], b = b[0], out = xor);
+		}`,
+	}
+	g := mustBuildGraph(t, hdls, "Adder")
+
+	var vectors []map[string][]bool
+	for a := range 256 {
+		for _, b := range []int{0, 1, 37, 255} {
+			vectors = append(vectors, map[string][]bool{"a": toBits(a, 8), "b": toBits(b, 8)})
+		}
+	}
+
+	for _, workers := range []int{0, 1, 3, len(vectors) + 1} {
+		results := EvaluateBatch(g, vectors, workers)
+		if !assert.Len(t, results, len(vectors)) {
+			continue
+		}
+		for i, result := range results {
+			a, b := fromBits(vectors[i]["a"]), fromBits(vectors[i]["b"])
+			assert.Equal(t, toBits((a+b)%256, 8), result["out"], "%d + %d with %d workers", a, b, workers)
+			assert.Equal(t, []bool{a%2 != b%2}, result["xor"], "%d xor %d with %d workers", a, b, workers)
+		}
+	}
+
+	assert.Empty(t, EvaluateBatch(g, nil, 4))
+	assert.False(t, g.InputPins["a"].Bits[0].Bit.Value, "expected the graph to be left untouched")
+}
+
+func toBits(value int, width int) []bool {
+	bits := make([]bool, width)
+	for i := range bits {
+		bits[i] = value>>i&1 == 1
+	}
+	return bits
+}
+
+func fromBits(bits []bool) int {
+	value := 0
+	for i, bit := range bits {
+		if bit {
+			value |= 1 << i
+		}
+	}
+	return value
+}
@@ -1,11 +1,19 @@
 package evaluator
 
 import (
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
 )
 
 type Evaluator struct {
 	Graph *graphbuilder.Graph
+	// FourValued enables 0/1/X simulation, it has to be set before the node states are initialized.
+	FourValued bool
+
+	// faults holds the values forced on bits by InjectFault, shared with the
+	// evaluators of the sub-graphs, and original the bits as they were before.
+	faults   map[*graphbuilder.Bit]bool
+	original map[*graphbuilder.Bit]graphbuilder.Bit
 }
 
 func New(graph *graphbuilder.Graph) *Evaluator {
@@ -14,6 +22,15 @@ func New(graph *graphbuilder.Graph) *Evaluator {
 	}
 }
 
+func (e *Evaluator) newSubEvaluator(graph *graphbuilder.Graph) *Evaluator {
+	return &Evaluator{
+		Graph:      graph,
+		FourValued: e.FourValued,
+		faults:     e.faults,
+		original:   e.original,
+	}
+}
+
 func (e *Evaluator) InitializeNodeStates() {
 	if e.Graph.StatesInitialized {
 		return
@@ -24,11 +41,77 @@ func (e *Evaluator) InitializeNodeStates() {
 	e.Graph.StatesInitialized = true
 }
 
+// ResetNodeStates puts the clocked parts of the whole graph back in their initial state.
+func (e *Evaluator) ResetNodeStates() {
+	clearStatesInitialized(e.Graph)
+	e.InitializeNodeStates()
+}
+
+func clearStatesInitialized(g *graphbuilder.Graph) {
+	g.StatesInitialized = false
+	for _, node := range g.Nodes {
+		if node.SubGraph != nil {
+			clearStatesInitialized(node.SubGraph)
+		}
+	}
+}
+
 func (e *Evaluator) SetInputs(inputs map[string][]bool) {
 	for inputName, input := range e.Graph.InputPins {
 		for i, bit := range input.Bits {
 			bit.Bit.Value = inputs[inputName][i]
 		}
+		e.forceFaults(input)
+	}
+}
+
+// InjectFault forces the bit to the value whatever drives it, a stuck-at-0 or
+// stuck-at-1 fault, until the faults are cleared.
+func (e *Evaluator) InjectFault(bit *graphbuilder.Bit, value bool) {
+	if e.faults == nil {
+		e.faults = make(map[*graphbuilder.Bit]bool)
+		e.original = make(map[*graphbuilder.Bit]graphbuilder.Bit)
+	}
+	if _, ok := e.faults[bit]; !ok {
+		e.original[bit] = *bit
+	}
+	e.faults[bit] = value
+	bit.Value = value
+	bit.Unknown = false
+}
+
+// ClearFaults removes the injected faults, restoring the values of the bits
+// that are not driven by parts, like constants and unconnected (X) inputs.
+func (e *Evaluator) ClearFaults() {
+	for bit, original := range e.original {
+		bit.Value = original.Value
+		bit.Unknown = original.Unknown
+	}
+	e.faults = nil
+	e.original = nil
+}
+
+// forceFaults sets the faulty bits of the pins after they were written.
+func (e *Evaluator) forceFaults(pins ...*graphbuilder.Pin) {
+	if len(e.faults) == 0 {
+		return
+	}
+	for _, pin := range pins {
+		for _, bitRef := range pin.Bits {
+			if value, ok := e.faults[bitRef.Bit]; ok {
+				bitRef.Bit.Value = value
+				bitRef.Bit.Unknown = false
+			}
+		}
+	}
+}
+
+func (e *Evaluator) forceNodeFaults(node *graphbuilder.Node) {
+	if len(e.faults) == 0 {
+		return
+	}
+	for _, output := range node.OutputPins {
+		e.forceFaults(output)
 	}
 }
 
@@ -54,6 +137,20 @@ func (e *Evaluator) GetOutputsAndInternalPins() (map[string][]bool, map[string][
 	return outputs, internals
 }
 
+// UnknownOutputs returns the indexes of the output bits that are X, by output name.
+// Outputs without unknown bits are left out.
+func (e *Evaluator) UnknownOutputs() map[string][]int {
+	unknowns := make(map[string][]int)
+	for outputName, output := range e.Graph.OutputPins {
+		for i, bit := range output.Bits {
+			if bit.Bit.Unknown {
+				unknowns[outputName] = append(unknowns[outputName], i)
+			}
+		}
+	}
+	return unknowns
+}
+
 func (e *Evaluator) EvaluateAndCommit() {
 	for _, node := range e.Graph.Nodes {
 		e.evaluateAndCommitNode(node)
@@ -73,59 +170,146 @@ func (e *Evaluator) Evaluate() {
 }
 
 func (e *Evaluator) initializeNodeState(node *graphbuilder.Node) {
-	if initializeState, ok := BuiltinChipStateInitializerFns[node.ChipName]; ok {
-		initializeState(node)
+	if node.Builtin != nil {
+		node.State = node.Builtin.Init()
+		if e.FourValued {
+			// nothing has been stored yet, so the whole state starts unknown
+			node.UnknownState = make(map[string][]bool, len(node.State))
+			for key, bits := range node.State {
+				node.UnknownState[key] = make([]bool, len(bits))
+				for i := range bits {
+					node.UnknownState[key][i] = true
+				}
+			}
+		}
 		return
 	}
 
 	if node.SubGraph != nil {
-		subEvaluator := New(node.SubGraph)
+		subEvaluator := e.newSubEvaluator(node.SubGraph)
 		subEvaluator.InitializeNodeStates()
 	}
 }
 
 func (e *Evaluator) evaluateAndCommitNode(node *graphbuilder.Node) {
-	commit, committerExists := BuiltinChipComitterFns[node.ChipName]
-	evaluate, evaluatorExists := BuiltinChipEvaluatorFns[node.ChipName]
-
-	if evaluatorExists {
-		evaluate(node)
-	}
-
-	if committerExists {
-		commit(node)
-	}
-
-	if evaluatorExists || committerExists {
+	if node.Builtin != nil {
+		e.evaluateBuiltin(node)
+		e.commitBuiltin(node)
 		return
 	}
 
 	if node.SubGraph != nil {
-		subEvaluator := New(node.SubGraph)
+		subEvaluator := e.newSubEvaluator(node.SubGraph)
 		subEvaluator.EvaluateAndCommit()
 	}
 }
 
 func (e *Evaluator) evaluateNode(node *graphbuilder.Node) {
-	if evaluate, ok := BuiltinChipEvaluatorFns[node.ChipName]; ok {
-		evaluate(node)
+	if node.Builtin != nil {
+		e.evaluateBuiltin(node)
 		return
 	}
 
 	if node.SubGraph != nil {
-		subEvaluator := New(node.SubGraph)
+		subEvaluator := e.newSubEvaluator(node.SubGraph)
 		subEvaluator.Evaluate()
 	}
 }
 
 func (e *Evaluator) applyNodeState(node *graphbuilder.Node) {
-	if apply, ok := BuiltinChipApplierFns[node.ChipName]; ok {
-		apply(node)
+	if node.Builtin != nil {
+		e.applyBuiltin(node)
 		return
 	}
 
 	if node.SubGraph != nil {
-		subEvaluator := New(node.SubGraph)
+		subEvaluator := e.newSubEvaluator(node.SubGraph)
 		subEvaluator.Apply()
 	}
 }
+
+func (e *Evaluator) evaluateBuiltin(node *graphbuilder.Node) {
+	defer e.forceNodeFaults(node)
+	if !e.FourValued {
+		node.Builtin.Evaluate(node, node.State)
+		return
+	}
+	if x, ok := node.Builtin.(chips.UnknownEvaluator); ok {
+		x.EvaluateUnknown(node, node.State, node.UnknownState)
+		return
+	}
+
+	node.Builtin.Evaluate(node, node.State)
+	if hasUnknownInput(node) {
+		for outputName, output := range node.OutputPins {
+			for i, bit := range output.Bits {
+				if !node.Builtin.IsSequentialBit(outputName, i) {
+					bit.Bit.Unknown = true
+				}
+			}
+		}
+	}
+}
+
+func (e *Evaluator) commitBuiltin(node *graphbuilder.Node) {
+	if !e.FourValued {
+		node.Builtin.Commit(node, node.State)
+		return
+	}
+	if x, ok := node.Builtin.(chips.UnknownEvaluator); ok {
+		x.CommitUnknown(node, node.State, node.UnknownState)
+		return
+	}
+
+	node.Builtin.Commit(node, node.State)
+	if hasUnknownInput(node) {
+		for _, bits := range node.UnknownState {
+			for i := range bits {
+				bits[i] = true
+			}
+		}
+	}
+}
+
+func (e *Evaluator) applyBuiltin(node *graphbuilder.Node) {
+	defer e.forceNodeFaults(node)
+	if !e.FourValued {
+		node.Builtin.Apply(node, node.State)
+		return
+	}
+	if x, ok := node.Builtin.(chips.UnknownEvaluator); ok {
+		x.ApplyUnknown(node, node.State, node.UnknownState)
+		return
+	}
+
+	node.Builtin.Apply(node, node.State)
+	if hasUnknownState(node) {
+		for _, output := range node.OutputPins {
+			for _, bit := range output.Bits {
+				bit.Bit.Unknown = true
+			}
+		}
+	}
+}
+
+func hasUnknownInput(node *graphbuilder.Node) bool {
+	for _, input := range node.InputPins {
+		for _, bit := range input.Bits {
+			if bit.Bit.Unknown {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasUnknownState(node *graphbuilder.Node) bool {
+	for _, bits := range node.UnknownState {
+		for _, unknown := range bits {
+			if unknown {
+				return true
+			}
+		}
+	}
+	return false
+}
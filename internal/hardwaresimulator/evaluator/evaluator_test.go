@@ -3,6 +3,7 @@ package evaluator
 import (
 	"testing"
 
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
@@ -147,6 +148,80 @@ func TestEvaluate(t *testing.T) {
 				assert.Equal(t, map[string][]bool{"out": {false}}, outputs, "expected: in = false -> out = false")
 			},
 		},
+		{
+			name:         "A NotNotGate with a stuck-at fault injected into an internal signal",
+			chipFileName: "NotNotGate",
+			hdls: map[string]string{
+				"NotNotGate": `CHIP NotNotGate {
+					IN in;
+					OUT out;
+					PARTS:
+					Not(in=in, out=not1out);
+					Not(in=not1out, out=out);
+				}`,
+			},
+			afterGraphBuild: func(t *testing.T, g *graphbuilder.Graph) {
+				e := New(g)
+				// not1out stuck at 1 -> out = 0 whatever the input
+				e.InjectFault(g.InternalPins["not1out"].Bits[0].Bit, true)
+				e.SetInputs(map[string][]bool{"in": {true}})
+				e.Evaluate()
+				outputs, internals := e.GetOutputsAndInternalPins()
+				assert.Equal(t, map[string][]bool{"out": {false}}, outputs, "expected: in = true -> out = false with the fault")
+				assert.Equal(t, map[string][]bool{"not1out": {true}}, internals, "expected not1out to stay true")
+
+				e.ClearFaults()
+
+				// in stuck at 0 is forced when the inputs are set
+				e.InjectFault(g.InputPins["in"].Bits[0].Bit, false)
+				e.SetInputs(map[string][]bool{"in": {true}})
+				e.Evaluate()
+				outputs, _ = e.GetOutputsAndInternalPins()
+				assert.Equal(t, map[string][]bool{"out": {false}}, outputs, "expected: in = true -> out = false with the fault")
+
+				e.ClearFaults()
+				e.SetInputs(map[string][]bool{"in": {true}})
+				e.Evaluate()
+				outputs, _ = e.GetOutputsAndInternalPins()
+				assert.Equal(t, map[string][]bool{"out": {true}}, outputs, "expected: in = true -> out = true without faults")
+			},
+		},
+		{
+			name:         "An AndGate with a stuck-at fault injected into an unconnected input in four-valued simulation",
+			chipFileName: "AndGate",
+			hdls: map[string]string{
+				"AndGate": `CHIP AndGate {
+					IN a;
+					OUT out;
+					PARTS:
+					And(a=a, out=out);
+				}`,
+			},
+			afterGraphBuild: func(t *testing.T, g *graphbuilder.Graph) {
+				e := New(g)
+				e.FourValued = true
+				e.InitializeNodeStates()
+
+				// b is X, so out = X
+				e.SetInputs(map[string][]bool{"a": {true}})
+				e.Evaluate()
+				assert.Equal(t, map[string][]int{"out": {0}}, e.UnknownOutputs(), "expected out to be X with b unconnected")
+
+				// b stuck at 1 -> out = a
+				b := g.Nodes[0].InputPins["b"].Bits[0].Bit
+				e.InjectFault(b, true)
+				e.Evaluate()
+				outputs, _ := e.GetOutputsAndInternalPins()
+				assert.Equal(t, map[string][]bool{"out": {true}}, outputs, "expected: a = true -> out = true with the fault")
+				assert.Empty(t, e.UnknownOutputs(), "expected out to be known with the fault")
+
+				// b is X again once the fault is cleared
+				e.ClearFaults()
+				e.Evaluate()
+				assert.True(t, b.Unknown, "expected b to be X again")
+				assert.Equal(t, map[string][]int{"out": {0}}, e.UnknownOutputs(), "expected out to be X without the fault")
+			},
+		},
 		{
 			name:         "A DFFGate using a single Built-in DFF gate",
 			chipFileName: "DFFGate",
@@ -196,6 +271,16 @@ func TestEvaluate(t *testing.T) {
 				e.Evaluate()
 				outputs, _ = e.GetOutputsAndInternalPins()
 				assert.Equal(t, map[string][]bool{"out": {false}}, outputs, "after tick-tock with input false, expected out to be false again")
+
+				// store true again, then go back to the initial state
+				e.SetInputs(map[string][]bool{"in": {true}})
+				e.EvaluateAndCommit()
+				e.Apply()
+				e.ResetNodeStates()
+				e.Apply()
+				e.Evaluate()
+				outputs, _ = e.GetOutputsAndInternalPins()
+				assert.Equal(t, map[string][]bool{"out": {false}}, outputs, "after resetting the node states, expected out to be false")
 			},
 		},
 	}
@@ -226,14 +311,14 @@ func mustBuildGraph(t *testing.T, hdls map[string]string, chipFileName string) *
 		t.Fatal(err)
 	}
 
-	r := resolver.New(chd, chipFileName, hdls)
+	r := resolver.New(chd, chipFileName, hdls, chips.NewDefaultRegistry())
 	rchd, rchds, err := r.Resolve([]string{}, []string{})
 	if err != nil {
 		t.Fatal(err)
 	}
 	rchds[rchd.Name] = rchd
 
-	gb := graphbuilder.New(rchds)
+	gb := graphbuilder.New(rchds, chips.NewDefaultRegistry())
 	graph, err := gb.BuildGraph(chipFileName)
 	if err != nil {
 		t.Fatal(err)
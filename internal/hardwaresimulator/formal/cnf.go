@@ -0,0 +1,35 @@
+package formal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CNF is a boolean formula in conjunctive normal form. Variables are numbered
+// from 1, a literal is a variable or its negation, as in the DIMACS format.
+type CNF struct {
+	NumVars int
+	Clauses [][]int
+}
+
+func (c *CNF) NewVar() int {
+	c.NumVars++
+	return c.NumVars
+}
+
+func (c *CNF) AddClause(literals ...int) {
+	c.Clauses = append(c.Clauses, literals)
+}
+
+// DIMACS returns the formula in the DIMACS CNF format read by most SAT solvers.
+func (c *CNF) DIMACS() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("p cnf %d %d\n", c.NumVars, len(c.Clauses)))
+	for _, clause := range c.Clauses {
+		for _, literal := range clause {
+			sb.WriteString(fmt.Sprintf("%d ", literal))
+		}
+		sb.WriteString("0\n")
+	}
+	return sb.String()
+}
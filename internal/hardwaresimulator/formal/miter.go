@@ -0,0 +1,136 @@
+package formal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+)
+
+// Result is the outcome of an equivalence proof.
+type Result struct {
+	Equivalent bool
+	// Counterexample holds the inputs for which the outputs differ, nil if the chips are equivalent.
+	Counterexample map[string][]bool
+	// Output is the first output, in alphabetical order, that differs for the counterexample.
+	Output   string
+	Expected []bool // output of the reference chip
+	Actual   []bool // output of the chip
+}
+
+// Miter is a formula of two chips driven by the same inputs, which is
+// satisfiable exactly when an output of the chips can differ.
+type Miter struct {
+	CNF *CNF
+
+	inputs           map[string][]int // variables by input name, shared by the chips
+	outputs          map[string][]int
+	referenceOutputs map[string][]int
+}
+
+// NewMiter encodes the combinational chips built into the graphs. The chips
+// have to have the same inputs and outputs, and consist of Nands and of the
+// combinational default built-in chips.
+func NewMiter(g, reference *graphbuilder.Graph) (*Miter, error) {
+	if !samePins(g.InputPins, reference.InputPins) || !samePins(g.OutputPins, reference.OutputPins) {
+		return nil, fmt.Errorf("Chips do not have the same inputs and outputs")
+	}
+
+	e := newEncoder()
+	m := &Miter{
+		CNF:              e.cnf,
+		inputs:           map[string][]int{},
+		outputs:          map[string][]int{},
+		referenceOutputs: map[string][]int{},
+	}
+
+	inputBits := map[*graphbuilder.Bit]bool{}
+	for _, name := range slices.Sorted(maps.Keys(g.InputPins)) {
+		for i, bitRef := range g.InputPins[name].Bits {
+			v := e.bit(bitRef.Bit)
+			referenceBit := reference.InputPins[name].Bits[i].Bit
+			e.vars[referenceBit] = v
+			inputBits[bitRef.Bit] = true
+			inputBits[referenceBit] = true
+			m.inputs[name] = append(m.inputs[name], v)
+		}
+	}
+
+	if err := e.encodeGraph(g); err != nil {
+		return nil, err
+	}
+	if err := e.encodeGraph(reference); err != nil {
+		return nil, err
+	}
+
+	var differences []int
+	for _, name := range slices.Sorted(maps.Keys(g.OutputPins)) {
+		for i, bitRef := range g.OutputPins[name].Bits {
+			v := e.bit(bitRef.Bit)
+			referenceV := e.bit(reference.OutputPins[name].Bits[i].Bit)
+			m.outputs[name] = append(m.outputs[name], v)
+			m.referenceOutputs[name] = append(m.referenceOutputs[name], referenceV)
+			differences = append(differences, e.xor(v, referenceV))
+		}
+	}
+	e.cnf.AddClause(differences...)
+	e.fixUndrivenBits(inputBits)
+
+	return m, nil
+}
+
+// Prove solves the formula, the chips are equivalent if it is unsatisfiable.
+func (m *Miter) Prove() *Result {
+	model, sat := Solve(m.CNF)
+	if !sat {
+		return &Result{Equivalent: true}
+	}
+
+	result := &Result{Counterexample: map[string][]bool{}}
+	for name, vars := range m.inputs {
+		result.Counterexample[name] = modelBits(model, vars)
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.outputs)) {
+		actual := modelBits(model, m.outputs[name])
+		expected := modelBits(model, m.referenceOutputs[name])
+		if !slices.Equal(actual, expected) {
+			result.Output = name
+			result.Actual = actual
+			result.Expected = expected
+			break
+		}
+	}
+	return result
+}
+
+// ProveEquivalence proves that the chips built into the graphs compute the same
+// outputs for every input, or finds inputs for which they differ.
+func ProveEquivalence(g, reference *graphbuilder.Graph) (*Result, error) {
+	m, err := NewMiter(g, reference)
+	if err != nil {
+		return nil, err
+	}
+	return m.Prove(), nil
+}
+
+func samePins(pins, otherPins map[string]*graphbuilder.Pin) bool {
+	if len(pins) != len(otherPins) {
+		return false
+	}
+	for name, pin := range pins {
+		otherPin, ok := otherPins[name]
+		if !ok || len(pin.Bits) != len(otherPin.Bits) {
+			return false
+		}
+	}
+	return true
+}
+
+func modelBits(model []bool, vars []int) []bool {
+	bits := make([]bool, len(vars))
+	for i, v := range vars {
+		bits[i] = model[v]
+	}
+	return bits
+}
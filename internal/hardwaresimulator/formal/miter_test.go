@@ -0,0 +1,194 @@
+package formal
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/evaluator"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/resolver"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var builtinALU = `CHIP BuiltinALU {
+	IN x[16], y[16], zx, nx, zy, ny, f, no;
+	OUT out[16], zr, ng;
+
+	PARTS:
+	Mux16(a = x, b = false, sel = zx, out = zxOut);
+	Not16(in = zxOut, out = notZx);
+	Mux16(a = zxOut, b = notZx, sel = nx, out = nxOut);
+	Mux16(a = y, b = false, sel = zy, out = zyOut);
+	Not16(in = zyOut, out = notZy);
+	Mux16(a = zyOut, b = notZy, sel = ny, out = nyOut);
+	And16(a = nxOut, b = nyOut, out = andOut);
+	Add16(a = nxOut, b = nyOut, out = addOut);
+	Mux16(a = andOut, b = addOut, sel = f, out = fOut);
+	Not16(in = fOut, out = notF);
+	Mux16(a = fOut, b = notF, sel = no, out = out, out[0..7] = low, out[8..15] = high, out[15] = ng);
+	Or8Way(in = low, out = lowOr);
+	Or8Way(in = high, out = highOr);
+	Or(a = lowOr, b = highOr, out = nonZero);
+	Not(in = nonZero, out = zr);
+}`
+
+func TestProveEquivalence(t *testing.T) {
+	hdls := maps.Clone(testutils.ChipImplementations)
+	hdls["BuiltinALU"] = builtinALU
+	hdls["BuggyMux8Way16Chip"] = `CHIP BuggyMux8Way16Chip {
+		IN a[16], b[16], c[16], d[16], e[16], f[16], g[16], h[16], sel[3];
+		OUT out[16];
+
+		PARTS:
+		Mux4Way16(a = a, b = b, c = c, d = g, sel = sel[0..1], out = abcd);
+		Mux4Way16(a = e, b = f, c = g, d = h, sel = sel[0..1], out = efgh);
+		Mux16(a = abcd, b = efgh, sel = sel[2], out = out);
+	}`
+	hdls["BuggyAdd16Chip"] = `CHIP BuggyAdd16Chip {
+		IN a[16], b[16];
+		OUT out[16];
+
+		PARTS:
+		Add16(a = a, b = b, out[0..14] = out[0..14]);
+		Xor(a = a[15], b = b[15], out = out[15]);
+	}`
+
+	tests := []struct {
+		name       string
+		chipName   string
+		reference  string
+		equivalent bool
+	}{
+		{name: "Xor", chipName: "XorChip", reference: "Xor", equivalent: true},
+		{name: "Mux", chipName: "MuxChip", reference: "Mux", equivalent: true},
+		{name: "DMux", chipName: "DMuxChip", reference: "DMux", equivalent: true},
+		{name: "Mux16", chipName: "Mux16Chip", reference: "Mux16", equivalent: true},
+		{name: "Mux4Way16", chipName: "Mux4Way16Chip", reference: "Mux4Way16", equivalent: true},
+		{name: "Mux8Way16", chipName: "Mux8Way16Chip", reference: "Mux8Way16", equivalent: true},
+		{name: "DMux4Way", chipName: "DMux4WayChip", reference: "DMux4Way", equivalent: true},
+		{name: "DMux8Way", chipName: "DMux8WayChip", reference: "DMux8Way", equivalent: true},
+		{name: "Or8Way", chipName: "Or8WayChip", reference: "Or8Way", equivalent: true},
+		{name: "FullAdder", chipName: "FullAdderChip", reference: "FullAdder", equivalent: true},
+		{name: "Add16", chipName: "Add16Chip", reference: "Add16", equivalent: true},
+		{name: "Inc16", chipName: "Inc16Chip", reference: "Inc16", equivalent: true},
+		{name: "ALU", chipName: "ALUChip", reference: "BuiltinALU", equivalent: true},
+		{name: "Wrong select", chipName: "BuggyMux8Way16Chip", reference: "Mux8Way16", equivalent: false},
+		{name: "Missing carry", chipName: "BuggyAdd16Chip", reference: "Add16", equivalent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := mustBuildGraph(t, hdls, tt.chipName)
+			var reference *graphbuilder.Graph
+			if _, ok := hdls[tt.reference]; ok {
+				reference = mustBuildGraph(t, hdls, tt.reference)
+			} else {
+				reference = mustBuildBuiltinGraph(t, tt.reference)
+			}
+
+			result, err := ProveEquivalence(g, reference)
+			require.NoError(t, err)
+			require.Equal(t, tt.equivalent, result.Equivalent)
+			if tt.equivalent {
+				assert.Nil(t, result.Counterexample)
+				return
+			}
+
+			// the counterexample has to make the chips differ in simulation too
+			actual := evaluate(g, result.Counterexample)
+			expected := evaluate(reference, result.Counterexample)
+			assert.Equal(t, result.Actual, actual[result.Output])
+			assert.Equal(t, result.Expected, expected[result.Output])
+			assert.NotEqual(t, actual[result.Output], expected[result.Output])
+		})
+	}
+}
+
+func TestProveEquivalenceErrors(t *testing.T) {
+	hdls := maps.Clone(testutils.ChipImplementations)
+
+	_, err := ProveEquivalence(mustBuildGraph(t, hdls, "XorChip"), mustBuildBuiltinGraph(t, "Add16"))
+	assert.EqualError(t, err, "Chips do not have the same inputs and outputs")
+
+	_, err = ProveEquivalence(mustBuildGraph(t, hdls, "BitChip"), mustBuildBuiltinGraph(t, "Bit"))
+	assert.EqualError(t, err, "Chip 'DFF' is sequential, only combinational chips can be proven equivalent")
+}
+
+func TestMiterDIMACS(t *testing.T) {
+	g := mustBuildGraph(t, testutils.ChipImplementations, "NotChip")
+	m, err := NewMiter(g, mustBuildBuiltinGraph(t, "Not"))
+	require.NoError(t, err)
+
+	dimacs := m.CNF.DIMACS()
+	assert.True(t, strings.HasPrefix(dimacs, fmt.Sprintf("p cnf %d %d\n", m.CNF.NumVars, len(m.CNF.Clauses))))
+	assert.Equal(t, len(m.CNF.Clauses)+1, strings.Count(dimacs, "\n"))
+}
+
+func evaluate(g *graphbuilder.Graph, inputs map[string][]bool) map[string][]bool {
+	e := evaluator.New(g)
+	e.InitializeNodeStates()
+	e.SetInputs(inputs)
+	e.Evaluate()
+	outputs, _ := e.GetOutputsAndInternalPins()
+	return outputs
+}
+
+func mustBuildGraph(t *testing.T, hdls map[string]string, chipName string) *graphbuilder.Graph {
+	t.Helper()
+
+	l := lexer.New(hdls[chipName])
+	ts, err := l.Tokenize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := parser.New(ts)
+	chd, err := p.ParseChipDefinition()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := chips.NewDefaultRegistry()
+	r := resolver.New(chd, chipName, hdls, registry)
+	rchd, rchds, err := r.Resolve([]string{}, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rchds[rchd.Name] = rchd
+
+	g, err := graphbuilder.New(rchds, registry).BuildGraph(rchd.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func mustBuildBuiltinGraph(t *testing.T, chipName string) *graphbuilder.Graph {
+	t.Helper()
+
+	registry := chips.NewDefaultRegistry()
+	builtin, ok := registry.Lookup(chipName)
+	if !ok {
+		t.Fatalf("no built-in chip named %s", chipName)
+	}
+	signature := builtin.Signature()
+	rchd := &resolver.ResolvedChipDefinition{
+		Name:        chipName,
+		Inputs:      signature.Inputs,
+		Outputs:     signature.Outputs,
+		BuiltinName: chipName,
+	}
+
+	g, err := graphbuilder.New(map[string]*resolver.ResolvedChipDefinition{chipName: rchd}, registry).BuildGraph(chipName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
@@ -0,0 +1,347 @@
+package formal
+
+import "slices"
+
+// A small CDCL solver: two watched literals, first UIP clause learning with
+// non-chronological backjumping, VSIDS-like variable activities, phase saving
+// and Luby restarts. It keeps every learnt clause, which is fine for the size
+// of the formulas of a single chip.
+
+const (
+	unassigned    int8 = 0
+	assignedTrue  int8 = 1
+	assignedFalse int8 = -1
+
+	noReason = -1
+
+	restartUnit   = 100
+	activityDecay = 0.95
+)
+
+// Internally the literal of variable v is 2*(v-1), its negation 2*(v-1)+1.
+func toLit(literal int) int {
+	if literal < 0 {
+		return 2*(-literal-1) + 1
+	}
+	return 2 * (literal - 1)
+}
+
+func litVar(lit int) int {
+	return lit >> 1
+}
+
+func negate(lit int) int {
+	return lit ^ 1
+}
+
+type solver struct {
+	clauses  [][]int
+	watchers [][]int // literal -> clauses watching it, visited when it becomes false
+
+	assigns  []int8 // variable -> value
+	levels   []int
+	reasons  []int // variable -> index of the clause that implied it
+	phases   []bool
+	activity []float64
+	varInc   float64
+	seen     []bool
+
+	trail    []int
+	trailLim []int // trail length at the start of each decision level
+	qhead    int
+
+	unsat bool
+}
+
+// Solve looks for an assignment satisfying the formula. The returned model is
+// indexed by variable, index 0 is unused.
+func Solve(cnf *CNF) (model []bool, sat bool) {
+	s := newSolver(cnf.NumVars)
+	for _, clause := range cnf.Clauses {
+		s.addClause(clause)
+	}
+	if !s.solve() {
+		return nil, false
+	}
+
+	model = make([]bool, cnf.NumVars+1)
+	for v := range cnf.NumVars {
+		model[v+1] = s.assigns[v] == assignedTrue
+	}
+	return model, true
+}
+
+func newSolver(numVars int) *solver {
+	return &solver{
+		watchers: make([][]int, 2*numVars),
+		assigns:  make([]int8, numVars),
+		levels:   make([]int, numVars),
+		reasons:  make([]int, numVars),
+		phases:   make([]bool, numVars),
+		activity: make([]float64, numVars),
+		varInc:   1,
+		seen:     make([]bool, numVars),
+	}
+}
+
+func (s *solver) value(lit int) int8 {
+	value := s.assigns[litVar(lit)]
+	if lit&1 == 1 {
+		return -value
+	}
+	return value
+}
+
+func (s *solver) decisionLevel() int {
+	return len(s.trailLim)
+}
+
+// addClause adds a clause of the formula, before solving.
+func (s *solver) addClause(literals []int) {
+	if s.unsat {
+		return
+	}
+
+	clause := make([]int, 0, len(literals))
+	for _, literal := range literals {
+		lit := toLit(literal)
+		if slices.Contains(clause, negate(lit)) {
+			return // always true
+		}
+		if !slices.Contains(clause, lit) {
+			clause = append(clause, lit)
+		}
+	}
+
+	switch len(clause) {
+	case 0:
+		s.unsat = true
+	case 1:
+		switch s.value(clause[0]) {
+		case assignedFalse:
+			s.unsat = true
+		case unassigned:
+			s.enqueue(clause[0], noReason)
+		}
+	default:
+		s.attach(clause)
+	}
+}
+
+func (s *solver) attach(clause []int) int {
+	index := len(s.clauses)
+	s.clauses = append(s.clauses, clause)
+	s.watchers[clause[0]] = append(s.watchers[clause[0]], index)
+	s.watchers[clause[1]] = append(s.watchers[clause[1]], index)
+	return index
+}
+
+func (s *solver) enqueue(lit int, reason int) {
+	v := litVar(lit)
+	if lit&1 == 1 {
+		s.assigns[v] = assignedFalse
+	} else {
+		s.assigns[v] = assignedTrue
+	}
+	s.levels[v] = s.decisionLevel()
+	s.reasons[v] = reason
+	s.trail = append(s.trail, lit)
+}
+
+// propagate assigns the literals implied by the trail, it returns the index of
+// a conflicting clause or noReason. The implied literal of a reason clause is
+// always its first literal.
+func (s *solver) propagate() int {
+	for s.qhead < len(s.trail) {
+		falseLit := negate(s.trail[s.qhead])
+		s.qhead++
+
+		watchers := s.watchers[falseLit]
+		kept := 0
+		for i := 0; i < len(watchers); i++ {
+			index := watchers[i]
+			clause := s.clauses[index]
+			if clause[0] == falseLit {
+				clause[0], clause[1] = clause[1], clause[0]
+			}
+
+			if s.value(clause[0]) == assignedTrue {
+				watchers[kept] = index
+				kept++
+				continue
+			}
+
+			moved := false
+			for k := 2; k < len(clause); k++ {
+				if s.value(clause[k]) != assignedFalse {
+					clause[1], clause[k] = clause[k], clause[1]
+					s.watchers[clause[1]] = append(s.watchers[clause[1]], index)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+
+			watchers[kept] = index
+			kept++
+			if s.value(clause[0]) == assignedFalse {
+				kept += copy(watchers[kept:], watchers[i+1:])
+				s.watchers[falseLit] = watchers[:kept]
+				return index
+			}
+			s.enqueue(clause[0], index)
+		}
+		s.watchers[falseLit] = watchers[:kept]
+	}
+	return noReason
+}
+
+// analyze derives a clause from the conflict with the first unique implication
+// point, with the asserting literal first and a literal of the backjump level second.
+func (s *solver) analyze(conflict int) (learnt []int, backjumpLevel int) {
+	learnt = []int{0} // room for the asserting literal
+	pathCount := 0
+	lit := -1
+	index := len(s.trail) - 1
+
+	for {
+		clause := s.clauses[conflict]
+		start := 0
+		if lit != -1 {
+			start = 1 // skip the literal implied by the clause
+		}
+		for _, q := range clause[start:] {
+			v := litVar(q)
+			if s.seen[v] || s.levels[v] == 0 {
+				continue
+			}
+			s.bump(v)
+			s.seen[v] = true
+			if s.levels[v] >= s.decisionLevel() {
+				pathCount++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+
+		for !s.seen[litVar(s.trail[index])] {
+			index--
+		}
+		lit = s.trail[index]
+		index--
+		conflict = s.reasons[litVar(lit)]
+		s.seen[litVar(lit)] = false
+		pathCount--
+		if pathCount == 0 {
+			break
+		}
+	}
+	learnt[0] = negate(lit)
+
+	for _, q := range learnt[1:] {
+		s.seen[litVar(q)] = false
+	}
+
+	for i := 1; i < len(learnt); i++ {
+		if s.levels[litVar(learnt[i])] > backjumpLevel {
+			backjumpLevel = s.levels[litVar(learnt[i])]
+			learnt[1], learnt[i] = learnt[i], learnt[1]
+		}
+	}
+	return learnt, backjumpLevel
+}
+
+func (s *solver) bump(v int) {
+	s.activity[v] += s.varInc
+	if s.activity[v] > 1e100 {
+		for i := range s.activity {
+			s.activity[i] *= 1e-100
+		}
+		s.varInc *= 1e-100
+	}
+}
+
+func (s *solver) cancelUntil(level int) {
+	if s.decisionLevel() <= level {
+		return
+	}
+	for i := len(s.trail) - 1; i >= s.trailLim[level]; i-- {
+		v := litVar(s.trail[i])
+		s.phases[v] = s.assigns[v] == assignedTrue
+		s.assigns[v] = unassigned
+	}
+	s.trail = s.trail[:s.trailLim[level]]
+	s.trailLim = s.trailLim[:level]
+	s.qhead = len(s.trail)
+}
+
+// pickBranch returns the unassigned variable with the highest activity, or -1.
+func (s *solver) pickBranch() int {
+	best := -1
+	for v, value := range s.assigns {
+		if value == unassigned && (best == -1 || s.activity[v] > s.activity[best]) {
+			best = v
+		}
+	}
+	return best
+}
+
+func (s *solver) solve() bool {
+	if s.unsat {
+		return false
+	}
+
+	conflicts := 0
+	restart := 1
+	restartLimit := restartUnit * luby(restart)
+	for {
+		conflict := s.propagate()
+		if conflict != noReason {
+			if s.decisionLevel() == 0 {
+				return false
+			}
+			learnt, backjumpLevel := s.analyze(conflict)
+			s.cancelUntil(backjumpLevel)
+			if len(learnt) == 1 {
+				s.enqueue(learnt[0], noReason)
+			} else {
+				s.enqueue(learnt[0], s.attach(learnt))
+			}
+			s.varInc /= activityDecay
+
+			conflicts++
+			if conflicts >= restartLimit {
+				conflicts = 0
+				restart++
+				restartLimit = restartUnit * luby(restart)
+				s.cancelUntil(0)
+			}
+			continue
+		}
+
+		v := s.pickBranch()
+		if v == -1 {
+			return true
+		}
+		s.trailLim = append(s.trailLim, len(s.trail))
+		lit := 2 * v
+		if !s.phases[v] {
+			lit = negate(lit)
+		}
+		s.enqueue(lit, noReason)
+	}
+}
+
+// luby returns the i-th element (from 1) of the Luby sequence 1 1 2 1 1 2 4 1 1 2 ...
+func luby(i int) int {
+	for k := 1; ; k++ {
+		if i == (1<<k)-1 {
+			return 1 << (k - 1)
+		}
+		if i < (1<<k)-1 {
+			return luby(i - (1 << (k - 1)) + 1)
+		}
+	}
+}
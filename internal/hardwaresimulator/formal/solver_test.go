@@ -0,0 +1,107 @@
+package formal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name string
+		cnf  *CNF
+		sat  bool
+	}{
+		{
+			name: "Empty formula",
+			cnf:  &CNF{},
+			sat:  true,
+		},
+		{
+			name: "Empty clause",
+			cnf:  &CNF{NumVars: 1, Clauses: [][]int{{}}},
+			sat:  false,
+		},
+		{
+			name: "Contradicting units",
+			cnf:  &CNF{NumVars: 1, Clauses: [][]int{{1}, {-1}}},
+			sat:  false,
+		},
+		{
+			name: "Implication chain",
+			cnf:  &CNF{NumVars: 4, Clauses: [][]int{{1}, {-1, 2}, {-2, 3}, {-3, 4}, {-4, -1, 2}}},
+			sat:  true,
+		},
+		{
+			name: "All assignments of two variables excluded",
+			cnf:  &CNF{NumVars: 2, Clauses: [][]int{{1, 2}, {1, -2}, {-1, 2}, {-1, -2}}},
+			sat:  false,
+		},
+		{
+			name: "Tautologies and duplicates",
+			cnf:  &CNF{NumVars: 2, Clauses: [][]int{{1, -1}, {2, 2}, {-2, 1, 1}}},
+			sat:  true,
+		},
+		{
+			name: "Four pigeons in three holes",
+			cnf:  pigeonhole(4, 3),
+			sat:  false,
+		},
+		{
+			name: "Three pigeons in three holes",
+			cnf:  pigeonhole(3, 3),
+			sat:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, sat := Solve(tt.cnf)
+			require.Equal(t, tt.sat, sat)
+			if !sat {
+				assert.Nil(t, model)
+				return
+			}
+			for _, clause := range tt.cnf.Clauses {
+				satisfied := false
+				for _, literal := range clause {
+					if literal > 0 == model[max(literal, -literal)] {
+						satisfied = true
+					}
+				}
+				assert.True(t, satisfied, "clause %v is not satisfied", clause)
+			}
+		})
+	}
+}
+
+// pigeonhole returns the formula stating that the pigeons sit in different holes.
+func pigeonhole(pigeons, holes int) *CNF {
+	cnf := &CNF{}
+	sits := make([][]int, pigeons)
+	for p := range pigeons {
+		sits[p] = make([]int, holes)
+		for h := range holes {
+			sits[p][h] = cnf.NewVar()
+		}
+		cnf.AddClause(sits[p]...)
+	}
+	for h := range holes {
+		for p := range pigeons {
+			for other := p + 1; other < pigeons; other++ {
+				cnf.AddClause(-sits[p][h], -sits[other][h])
+			}
+		}
+	}
+	return cnf
+}
+
+func TestDIMACS(t *testing.T) {
+	cnf := &CNF{}
+	a, b := cnf.NewVar(), cnf.NewVar()
+	cnf.AddClause(a, -b)
+	cnf.AddClause(b)
+
+	assert.Equal(t, "p cnf 2 2\n1 -2 0\n2 0\n", cnf.DIMACS())
+}
@@ -0,0 +1,276 @@
+package formal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+)
+
+// gateEncoder adds the clauses tying the outputs of a built-in chip to its inputs.
+type gateEncoder func(e *encoder, node *graphbuilder.Node)
+
+// encoders of the combinational default built-in chips, by chip name
+var encoders = map[string]gateEncoder{
+	"Nand":      bitwise(func(e *encoder, a, b int) int { return -e.and(a, b) }),
+	"And":       bitwise((*encoder).and),
+	"And16":     bitwise((*encoder).and),
+	"Or":        bitwise((*encoder).or),
+	"Or16":      bitwise((*encoder).or),
+	"Xor":       bitwise((*encoder).xor),
+	"Not":       encodeNot,
+	"Not16":     encodeNot,
+	"Mux":       encodeMux,
+	"Mux16":     encodeMux,
+	"Mux4Way16": encodeMux,
+	"Mux8Way16": encodeMux,
+	"DMux":      encodeDMux,
+	"DMux4Way":  encodeDMux,
+	"DMux8Way":  encodeDMux,
+	"Or8Way":    encodeOr8Way,
+	"HalfAdder": encodeHalfAdder,
+	"FullAdder": encodeFullAdder,
+	"Add16":     encodeAdd16,
+	"Inc16":     encodeInc16,
+}
+
+// encoder translates graphs into CNF with the Tseitin transformation, every bit
+// of the graph and every intermediate gate getting a variable.
+type encoder struct {
+	cnf     *CNF
+	vars    map[*graphbuilder.Bit]int
+	bits    []*graphbuilder.Bit // in the order of their variables
+	driven  map[*graphbuilder.Bit]bool
+	trueVar int // variable fixed to true, 0 until needed
+}
+
+func newEncoder() *encoder {
+	return &encoder{
+		cnf:    &CNF{},
+		vars:   map[*graphbuilder.Bit]int{},
+		driven: map[*graphbuilder.Bit]bool{},
+	}
+}
+
+func (e *encoder) bit(bit *graphbuilder.Bit) int {
+	if v, ok := e.vars[bit]; ok {
+		return v
+	}
+	v := e.cnf.NewVar()
+	e.vars[bit] = v
+	e.bits = append(e.bits, bit)
+	return v
+}
+
+func (e *encoder) constant(value bool) int {
+	if e.trueVar == 0 {
+		e.trueVar = e.cnf.NewVar()
+		e.cnf.AddClause(e.trueVar)
+	}
+	if value {
+		return e.trueVar
+	}
+	return -e.trueVar
+}
+
+func (e *encoder) and(a, b int) int {
+	z := e.cnf.NewVar()
+	e.cnf.AddClause(-z, a)
+	e.cnf.AddClause(-z, b)
+	e.cnf.AddClause(z, -a, -b)
+	return z
+}
+
+func (e *encoder) or(a, b int) int {
+	return -e.and(-a, -b)
+}
+
+func (e *encoder) xor(a, b int) int {
+	z := e.cnf.NewVar()
+	e.cnf.AddClause(-z, a, b)
+	e.cnf.AddClause(-z, -a, -b)
+	e.cnf.AddClause(z, -a, b)
+	e.cnf.AddClause(z, a, -b)
+	return z
+}
+
+// mux returns b if sel is true, a otherwise.
+func (e *encoder) mux(sel, a, b int) int {
+	z := e.cnf.NewVar()
+	e.cnf.AddClause(sel, -a, z)
+	e.cnf.AddClause(sel, a, -z)
+	e.cnf.AddClause(-sel, -b, z)
+	e.cnf.AddClause(-sel, b, -z)
+	return z
+}
+
+// equal ties the variable of an output bit to a literal.
+func (e *encoder) equal(v, literal int) {
+	e.cnf.AddClause(-v, literal)
+	e.cnf.AddClause(v, -literal)
+}
+
+func (e *encoder) input(node *graphbuilder.Node, name string) []int {
+	bits := node.InputPins[name].Bits
+	literals := make([]int, len(bits))
+	for i, bitRef := range bits {
+		literals[i] = e.bit(bitRef.Bit)
+	}
+	return literals
+}
+
+func (e *encoder) drive(node *graphbuilder.Node, name string, literals []int) {
+	for i, bitRef := range node.OutputPins[name].Bits {
+		e.equal(e.bit(bitRef.Bit), literals[i])
+		e.driven[bitRef.Bit] = true
+	}
+}
+
+// encodeGraph adds the clauses of every built-in chip in the graph, expanding
+// the parts that are custom chips.
+func (e *encoder) encodeGraph(g *graphbuilder.Graph) error {
+	for _, node := range g.Nodes {
+		if node.SubGraph != nil {
+			if err := e.encodeGraph(node.SubGraph); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if node.Builtin.Init() != nil {
+			return fmt.Errorf("Chip '%s' is sequential, only combinational chips can be proven equivalent", node.ChipName)
+		}
+		encode, ok := encoders[node.ChipName]
+		if !ok {
+			return fmt.Errorf("Built-in chip '%s' cannot be translated to CNF", node.ChipName)
+		}
+		encode(e, node)
+	}
+	return nil
+}
+
+// fixUndrivenBits sets the bits that are neither inputs nor driven by a chip,
+// like constants and unconnected pins, to the value they have in the simulation.
+func (e *encoder) fixUndrivenBits(inputs map[*graphbuilder.Bit]bool) {
+	for _, bit := range e.bits {
+		if e.driven[bit] || inputs[bit] {
+			continue
+		}
+		if bit.Value {
+			e.cnf.AddClause(e.vars[bit])
+		} else {
+			e.cnf.AddClause(-e.vars[bit])
+		}
+	}
+}
+
+func bitwise(gate func(e *encoder, a, b int) int) gateEncoder {
+	return func(e *encoder, node *graphbuilder.Node) {
+		a, b := e.input(node, "a"), e.input(node, "b")
+		out := make([]int, len(a))
+		for i := range a {
+			out[i] = gate(e, a[i], b[i])
+		}
+		e.drive(node, "out", out)
+	}
+}
+
+func encodeNot(e *encoder, node *graphbuilder.Node) {
+	in := e.input(node, "in")
+	out := make([]int, len(in))
+	for i := range in {
+		out[i] = -in[i]
+	}
+	e.drive(node, "out", out)
+}
+
+// encodeMux selects between the inputs other than sel, in alphabetical order,
+// with a tree of two-way multiplexers.
+func encodeMux(e *encoder, node *graphbuilder.Node) {
+	sel := e.input(node, "sel")
+	var inputs [][]int
+	for _, name := range slices.Sorted(maps.Keys(node.InputPins)) {
+		if name != "sel" {
+			inputs = append(inputs, e.input(node, name))
+		}
+	}
+
+	for _, s := range sel {
+		var selected [][]int
+		for i := 0; i < len(inputs); i += 2 {
+			bits := make([]int, len(inputs[i]))
+			for j := range bits {
+				bits[j] = e.mux(s, inputs[i][j], inputs[i+1][j])
+			}
+			selected = append(selected, bits)
+		}
+		inputs = selected
+	}
+	e.drive(node, "out", inputs[0])
+}
+
+// encodeDMux routes in to the output selected by sel, the outputs in alphabetical order.
+func encodeDMux(e *encoder, node *graphbuilder.Node) {
+	in := e.input(node, "in")[0]
+	sel := e.input(node, "sel")
+	for i, name := range slices.Sorted(maps.Keys(node.OutputPins)) {
+		out := in
+		for j, s := range sel {
+			if i>>j&1 == 1 {
+				out = e.and(out, s)
+			} else {
+				out = e.and(out, -s)
+			}
+		}
+		e.drive(node, name, []int{out})
+	}
+}
+
+func encodeOr8Way(e *encoder, node *graphbuilder.Node) {
+	in := e.input(node, "in")
+	out := in[0]
+	for _, bit := range in[1:] {
+		out = e.or(out, bit)
+	}
+	e.drive(node, "out", []int{out})
+}
+
+func encodeHalfAdder(e *encoder, node *graphbuilder.Node) {
+	a, b := e.input(node, "a")[0], e.input(node, "b")[0]
+	e.drive(node, "sum", []int{e.xor(a, b)})
+	e.drive(node, "carry", []int{e.and(a, b)})
+}
+
+func encodeFullAdder(e *encoder, node *graphbuilder.Node) {
+	a, b, c := e.input(node, "a")[0], e.input(node, "b")[0], e.input(node, "c")[0]
+	sum, carry := e.fullAdder(a, b, c)
+	e.drive(node, "sum", []int{sum})
+	e.drive(node, "carry", []int{carry})
+}
+
+func (e *encoder) fullAdder(a, b, c int) (sum, carry int) {
+	ab := e.xor(a, b)
+	return e.xor(ab, c), e.or(e.and(a, b), e.and(ab, c))
+}
+
+func encodeAdd16(e *encoder, node *graphbuilder.Node) {
+	a, b := e.input(node, "a"), e.input(node, "b")
+	out := make([]int, len(a))
+	carry := e.constant(false)
+	for i := range a {
+		out[i], carry = e.fullAdder(a[i], b[i], carry)
+	}
+	e.drive(node, "out", out)
+}
+
+func encodeInc16(e *encoder, node *graphbuilder.Node) {
+	in := e.input(node, "in")
+	out := make([]int, len(in))
+	carry := e.constant(true)
+	for i := range in {
+		out[i] = e.xor(in[i], carry)
+		carry = e.and(in[i], carry)
+	}
+	e.drive(node, "out", out)
+}
@@ -0,0 +1,127 @@
+package graphbuilder
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the graph, with the states of its nodes, that
+// shares nothing with it but the built-in chips, which are stateless. The copy
+// can be evaluated independently, e.g. on another goroutine.
+func (g *Graph) Clone() *Graph {
+	c := &cloner{
+		bits:    make(map[*Bit]*Bit),
+		bitRefs: make(map[*BitRef]*BitRef),
+		nodes:   make(map[*Node]*Node),
+	}
+	return c.graph(g)
+}
+
+// cloner keeps track of the copies, as bits, bit references and nodes are
+// shared between the pins of a graph and the ones of its sub-graphs.
+type cloner struct {
+	bits    map[*Bit]*Bit
+	bitRefs map[*BitRef]*BitRef
+	nodes   map[*Node]*Node
+}
+
+func (c *cloner) graph(g *Graph) *Graph {
+	clone := &Graph{
+		Nodes:             make([]*Node, len(g.Nodes)),
+		InputPins:         c.pins(g.InputPins),
+		OutputPins:        c.pins(g.OutputPins),
+		InternalPins:      make(map[string]*InternalPin, len(g.InternalPins)),
+		Edges:             make(map[*Node][]*Node, len(g.Edges)),
+		StatesInitialized: g.StatesInitialized,
+	}
+
+	for i, node := range g.Nodes {
+		clone.Nodes[i] = c.node(node)
+	}
+
+	for name, internalPin := range g.InternalPins {
+		dependentNodes := make(map[*Node][]int, len(internalPin.DependentNodes))
+		for node, indexes := range internalPin.DependentNodes {
+			dependentNodes[c.nodes[node]] = slices.Clone(indexes)
+		}
+		clone.InternalPins[name] = &InternalPin{
+			Name:           internalPin.Name,
+			Bits:           c.bitRefSlice(internalPin.Bits),
+			SourceNode:     c.nodes[internalPin.SourceNode],
+			DependentNodes: dependentNodes,
+		}
+	}
+
+	for node, dependentNodes := range g.Edges {
+		clonedNodes := make([]*Node, len(dependentNodes))
+		for i, dependentNode := range dependentNodes {
+			clonedNodes[i] = c.nodes[dependentNode]
+		}
+		clone.Edges[c.nodes[node]] = clonedNodes
+	}
+
+	return clone
+}
+
+func (c *cloner) node(node *Node) *Node {
+	clone := &Node{
+		ChipName:     node.ChipName,
+		Builtin:      node.Builtin,
+		Loc:          node.Loc,
+		InputPins:    c.pins(node.InputPins),
+		OutputPins:   c.pins(node.OutputPins),
+		State:        cloneState(node.State),
+		UnknownState: cloneState(node.UnknownState),
+	}
+	c.nodes[node] = clone
+
+	if node.SubGraph != nil {
+		clone.SubGraph = c.graph(node.SubGraph)
+	}
+	return clone
+}
+
+func (c *cloner) pins(pins map[string]*Pin) map[string]*Pin {
+	clone := make(map[string]*Pin, len(pins))
+	for name, pin := range pins {
+		clone[name] = &Pin{Name: pin.Name, Bits: c.bitRefSlice(pin.Bits)}
+	}
+	return clone
+}
+
+func (c *cloner) bitRefSlice(bitRefs []*BitRef) []*BitRef {
+	clone := make([]*BitRef, len(bitRefs))
+	for i, bitRef := range bitRefs {
+		clone[i] = c.bitRef(bitRef)
+	}
+	return clone
+}
+
+func (c *cloner) bitRef(bitRef *BitRef) *BitRef {
+	if clone, ok := c.bitRefs[bitRef]; ok {
+		return clone
+	}
+	clone := &BitRef{Bit: c.bit(bitRef.Bit)}
+	c.bitRefs[bitRef] = clone
+	return clone
+}
+
+func (c *cloner) bit(bit *Bit) *Bit {
+	if clone, ok := c.bits[bit]; ok {
+		return clone
+	}
+	clone := *bit
+	c.bits[bit] = &clone
+	return &clone
+}
+
+func cloneState(state map[string][]bool) map[string][]bool {
+	if state == nil {
+		return nil
+	}
+	clone := maps.Clone(state)
+	for name, bits := range clone {
+		clone[name] = slices.Clone(bits)
+	}
+	return clone
+}
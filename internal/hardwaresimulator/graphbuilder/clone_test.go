@@ -0,0 +1,73 @@
+package graphbuilder
+
+import (
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip": `CHIP NotChip {
+			IN in;
+			OUT out;
+
+			PARTS:
+			Nand(a = in, b = in, out = out);
+		}`,
+		"LatchChip": `CHIP LatchChip {
+			IN in;
+			OUT out, notOut;
+
+			PARTS:
+			NotChip(in = dffOut, out = notOut);
+			DFF(in = in, out = dffOut, out = out);
+		}`,
+	}
+
+	chd, chds := mustLexParseAndResolve(t, hdls, "LatchChip")
+	chds[chd.Name] = chd
+	g, err := New(chds, chips.NewDefaultRegistry()).BuildGraph("LatchChip")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, node := range g.Nodes {
+		if node.ChipName == "DFF" {
+			node.State = map[string][]bool{"out": {true}}
+		}
+	}
+
+	clone := g.Clone()
+
+	var notChip, dff, clonedNotChip, clonedDff *Node
+	for i := range g.Nodes {
+		switch g.Nodes[i].ChipName {
+		case "NotChip":
+			notChip, clonedNotChip = g.Nodes[i], clone.Nodes[i]
+		case "DFF":
+			dff, clonedDff = g.Nodes[i], clone.Nodes[i]
+		}
+	}
+
+	assert.Equal(t, "NotChip", clonedNotChip.ChipName)
+	assert.Equal(t, notChip.Loc, clonedNotChip.Loc)
+	assert.Equal(t, dff.Builtin, clonedDff.Builtin)
+	assert.NotSame(t, dff, clonedDff)
+
+	// the bits are copied, but still shared the same way
+	assert.NotSame(t, g.InputPins["in"].Bits[0].Bit, clone.InputPins["in"].Bits[0].Bit)
+	assert.Same(t, clone.InputPins["in"].Bits[0].Bit, clonedDff.InputPins["in"].Bits[0].Bit)
+	assert.Same(t, clone.InternalPins["dffOut"].Bits[0].Bit, clonedNotChip.SubGraph.InputPins["in"].Bits[0].Bit)
+	assert.Same(t, clonedNotChip.InputPins["in"].Bits[0].Bit, clonedNotChip.SubGraph.Nodes[0].InputPins["a"].Bits[0].Bit)
+	assert.Same(t, clonedDff, clone.InternalPins["dffOut"].SourceNode)
+	assert.Contains(t, clone.InternalPins["dffOut"].DependentNodes, clonedNotChip)
+
+	// the state is copied
+	assert.Equal(t, []bool{true}, clonedDff.State["out"])
+	clonedDff.State["out"][0] = false
+	assert.Equal(t, []bool{true}, dff.State["out"])
+
+	clone.InputPins["in"].Bits[0].Bit.Value = true
+	assert.False(t, g.InputPins["in"].Bits[0].Bit.Value)
+}
@@ -3,6 +3,9 @@ package graphbuilder
 import (
 	"fmt"
 	"strings"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
 )
 
 type Graph struct {
@@ -18,11 +21,15 @@ type Graph struct {
 
 type Node struct {
 	ChipName   string
+	Builtin    chips.BuiltinChip // nil if custom chip
+	Loc        parser.Loc        // location of the part in the HDL of the chip that uses it
 	InputPins  map[string]*Pin
 	OutputPins map[string]*Pin
 
 	// for sequential chips (eg. DFF) to keep track of the state
 	State map[string][]bool // signal name -> bits
+	// unknown flags of the state bits, only used in four-valued simulation
+	UnknownState map[string][]bool
 
 	SubGraph *Graph // nil if built-in chip
 }
@@ -32,6 +39,37 @@ type Pin struct {
 	Bits []*BitRef
 }
 
+// Input and Output make Node usable as chips.Pins.
+func (n *Node) Input(name string) chips.Bits {
+	return n.InputPins[name]
+}
+
+func (n *Node) Output(name string) chips.Bits {
+	return n.OutputPins[name]
+}
+
+// Len, Get, Set, IsUnknown and SetUnknown make Pin usable as chips.Bits.
+func (p *Pin) Len() int {
+	return len(p.Bits)
+}
+
+func (p *Pin) Get(i int) bool {
+	return p.Bits[i].Bit.Value
+}
+
+func (p *Pin) Set(i int, value bool) {
+	p.Bits[i].Bit.Value = value
+	p.Bits[i].Bit.Unknown = false
+}
+
+func (p *Pin) IsUnknown(i int) bool {
+	return p.Bits[i].Bit.Unknown
+}
+
+func (p *Pin) SetUnknown(i int) {
+	p.Bits[i].Bit.Unknown = true
+}
+
 type InternalPin struct {
 	Name           string
 	Bits           []*BitRef
@@ -46,6 +84,7 @@ type BitRef struct {
 type Bit struct {
 	IsSequential bool
 	Value        bool
+	Unknown      bool // X in four-valued simulation, set for bits that are not driven by anything
 }
 
 func (g *Graph) String() string {
@@ -11,12 +11,14 @@ import (
 type GraphBuilder struct {
 	chipDefinition  *resolver.ResolvedChipDefinition
 	chipDefinitions map[string]*resolver.ResolvedChipDefinition
+	registry        *chips.Registry
 	graph           *Graph
 }
 
-func New(chipDefinitions map[string]*resolver.ResolvedChipDefinition) *GraphBuilder {
+func New(chipDefinitions map[string]*resolver.ResolvedChipDefinition, registry *chips.Registry) *GraphBuilder {
 	return &GraphBuilder{
 		chipDefinitions: chipDefinitions,
+		registry:        registry,
 	}
 }
 
@@ -36,7 +38,7 @@ func (gb *GraphBuilder) BuildGraph(chipName string) (*Graph, error) {
 	for outputName, output := range chd.Outputs {
 		outputPins[outputName] = &Pin{
 			Name: outputName,
-			Bits: createBitsArray(output.Width),
+			Bits: createUndrivenBitsArray(output.Width),
 		}
 	}
 
@@ -65,6 +67,24 @@ func (gb *GraphBuilder) BuildGraphWithExistingIOPins(chipName string, inputPins,
 		Edges:        map[*Node][]*Node{},
 	}
 
+	if chd.BuiltinName != "" {
+		// the chip itself is declared with BUILTIN, so the graph is a single
+		// node of the Go implementation wired directly to the IO pins
+		builtin, _ := gb.registry.Lookup(chd.BuiltinName)
+		for outputName, output := range outputPins {
+			for i, bit := range output.Bits {
+				bit.Bit.IsSequential = builtin.IsSequentialBit(outputName, i)
+			}
+		}
+		gb.graph.Nodes = append(gb.graph.Nodes, &Node{
+			ChipName:   chd.BuiltinName,
+			Builtin:    builtin,
+			InputPins:  inputPins,
+			OutputPins: outputPins,
+		})
+		return gb.graph, nil
+	}
+
 	for _, part := range chd.Parts {
 		err := gb.buildNodeFromPart(&part)
 		if err != nil {
@@ -98,20 +118,28 @@ func (gb *GraphBuilder) buildNodeFromPart(part *resolver.Part) error {
 	inputPins := make(map[string]*Pin)
 	outputPins := make(map[string]*Pin)
 
-	builtinChipDef, isBuiltin := chips.BuiltInChips[part.Name]
+	chipName := part.Name
+	builtin, isBuiltin := gb.registry.Lookup(part.Name)
 	customChipDef, _ := gb.chipDefinitions[part.Name]
 
+	if !isBuiltin && customChipDef.BuiltinName != "" {
+		// custom chip declared with BUILTIN, bind the part to the Go implementation
+		chipName = customChipDef.BuiltinName
+		builtin, isBuiltin = gb.registry.Lookup(chipName)
+	}
+
 	if isBuiltin {
+		builtinChipDef := builtin.Signature()
 		for inputName, input := range builtinChipDef.Inputs {
 			inputPins[inputName] = &Pin{
 				Name: inputName,
-				Bits: createBitsArray(input.Width),
+				Bits: createUndrivenBitsArray(input.Width),
 			}
 		}
 		for outputName, output := range builtinChipDef.Outputs {
-			bits := createBitsArray(output.Width)
+			bits := createUndrivenBitsArray(output.Width)
 			for i, bit := range bits {
-				bit.Bit.IsSequential = chips.IsSequentialBit(part.Name, outputName, i)
+				bit.Bit.IsSequential = builtin.IsSequentialBit(outputName, i)
 			}
 			outputPins[outputName] = &Pin{
 				Name: outputName,
@@ -122,13 +150,13 @@ func (gb *GraphBuilder) buildNodeFromPart(part *resolver.Part) error {
 		for inputName, input := range customChipDef.Inputs {
 			inputPins[inputName] = &Pin{
 				Name: inputName,
-				Bits: createBitsArray(input.Width),
+				Bits: createUndrivenBitsArray(input.Width),
 			}
 		}
 		for outputName, output := range customChipDef.Outputs {
 			outputPins[outputName] = &Pin{
 				Name: outputName,
-				Bits: createBitsArray(output.Width),
+				Bits: createUndrivenBitsArray(output.Width),
 			}
 		}
 	}
@@ -136,7 +164,12 @@ func (gb *GraphBuilder) buildNodeFromPart(part *resolver.Part) error {
 	for _, inputConnection := range part.InputConnections {
 		signalName := inputConnection.Signal.Name
 		isBooleanConstant := signalName == "true" || signalName == "false"
-		if isBooleanConstant {
+		if inputConnection.Signal.IsNumericConstant {
+			bits := createBitsArrayFromValue(inputConnection.Signal.Range.End-inputConnection.Signal.Range.Start+1, inputConnection.Signal.Value)
+			for i, bit := range bits {
+				inputPins[inputConnection.Pin.Name].Bits[inputConnection.Pin.Range.Start+i] = bit
+			}
+		} else if isBooleanConstant {
 			bits := createBitsArraWithValues(inputConnection.Signal.Range.End-inputConnection.Signal.Range.Start+1, signalName == "true")
 			for i, bit := range bits {
 				inputPins[inputConnection.Pin.Name].Bits[inputConnection.Pin.Range.Start+i] = bit
@@ -163,7 +196,7 @@ func (gb *GraphBuilder) buildNodeFromPart(part *resolver.Part) error {
 	}
 
 	if !isBuiltin {
-		subGraphBuilder := New(gb.chipDefinitions)
+		subGraphBuilder := New(gb.chipDefinitions, gb.registry)
 		subGraph, err := subGraphBuilder.BuildGraphWithExistingIOPins(part.Name, inputPins, outputPins)
 		if err != nil {
 			return err
@@ -188,7 +221,9 @@ func (gb *GraphBuilder) buildNodeFromPart(part *resolver.Part) error {
 		}
 	}
 
-	node.ChipName = part.Name
+	node.ChipName = chipName
+	node.Builtin = builtin
+	node.Loc = part.Loc
 	node.InputPins = inputPins
 	node.OutputPins = outputPins
 
@@ -204,6 +239,16 @@ func createBitsArray(width int) []*BitRef {
 	return bits
 }
 
+// createUndrivenBitsArray creates the bits of IO pins that are replaced when a
+// signal gets connected, so the ones left are read as X in four-valued simulation.
+func createUndrivenBitsArray(width int) []*BitRef {
+	bits := make([]*BitRef, width)
+	for i := range bits {
+		bits[i] = &BitRef{Bit: &Bit{Value: false, Unknown: true}}
+	}
+	return bits
+}
+
 func createBitsArraWithValues(width int, val bool) []*BitRef {
 	bits := make([]*BitRef, width)
 	for i := range bits {
@@ -212,6 +257,14 @@ func createBitsArraWithValues(width int, val bool) []*BitRef {
 	return bits
 }
 
+func createBitsArrayFromValue(width int, value uint64) []*BitRef {
+	bits := make([]*BitRef, width)
+	for i := range bits {
+		bits[i] = &BitRef{Bit: &Bit{Value: i < 64 && value>>i&1 == 1}}
+	}
+	return bits
+}
+
 func getNodesInTopologicalOrder(g *Graph) ([]*Node, error) {
 	indegrees := make(map[*Node]int)
 	for _, dependentNodes := range g.Edges {
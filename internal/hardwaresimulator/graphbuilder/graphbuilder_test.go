@@ -3,6 +3,7 @@ package graphbuilder
 import (
 	"testing"
 
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/resolver"
@@ -51,6 +52,50 @@ func TestBuildGraph(t *testing.T) {
 				assert.Equal(t, 2, len(g.Nodes), "expected 2 nodes in the graph")
 			},
 		},
+		{
+			name:         "Binds parts declared with BUILTIN to the built-in implementation",
+			chipFileName: "CustomChip",
+			hdls: map[string]string{
+				"CustomChip": `CHIP CustomChip {
+                    IN a, b;
+                    OUT out;
+
+                    PARTS:
+                    Nand(a=nandout2, b=b, out=nandout1);
+                    MyDFF(in=nandout1, out=nandout2);
+                }`,
+				"MyDFF": `CHIP MyDFF {
+                    IN in;
+                    OUT out;
+                    BUILTIN DFF;
+                    CLOCKED in;
+                }`,
+			},
+			after: func(t *testing.T, g *Graph) {
+				assert.Equal(t, 2, len(g.Nodes), "expected 2 nodes in the graph")
+				for _, node := range g.Nodes {
+					assert.Nil(t, node.SubGraph)
+				}
+				assert.True(t, g.InternalPins["nandout2"].Bits[0].Bit.IsSequential)
+			},
+		},
+		{
+			name:         "Builds a single node graph for a chip declared with BUILTIN",
+			chipFileName: "Mux16",
+			hdls: map[string]string{
+				"Mux16": `CHIP Mux16 {
+                    IN a[16], b[16], sel;
+                    OUT out[16];
+                    BUILTIN Mux16;
+                }`,
+			},
+			after: func(t *testing.T, g *Graph) {
+				assert.Equal(t, 1, len(g.Nodes), "expected 1 node in the graph")
+				assert.Equal(t, "Mux16", g.Nodes[0].ChipName)
+				assert.Same(t, g.InputPins["sel"], g.Nodes[0].InputPins["sel"])
+				assert.Same(t, g.OutputPins["out"], g.Nodes[0].OutputPins["out"])
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -58,7 +103,7 @@ func TestBuildGraph(t *testing.T) {
 			chd, chds := mustLexParseAndResolve(t, tt.hdls, tt.chipFileName)
 			chds[chd.Name] = chd
 
-			gb := New(chds)
+			gb := New(chds, chips.NewDefaultRegistry())
 			graph, err := gb.BuildGraph(tt.chipFileName)
 
 			if tt.expectedError != "" {
@@ -102,7 +147,7 @@ func mustLexParseAndResolve(t *testing.T, hdls map[string]string, chipFileName s
 		t.Fatal(err)
 	}
 
-	r := resolver.New(chd, chipFileName, hdls)
+	r := resolver.New(chd, chipFileName, hdls, chips.NewDefaultRegistry())
 	resolvedChipDef, resolvedUsedChipDefs, err := r.Resolve([]string{}, []string{})
 	if err != nil {
 		t.Fatal(err)
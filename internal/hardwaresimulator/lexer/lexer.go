@@ -75,7 +75,11 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '.' {
 			l.readChar() // read the first '.'
 			// the second '.' will be read at the end of the function
-			tok = token.Token{TokenType: token.RANGE, Literal: "..", Line: l.line, Column: l.column - 1}
+			tok = token.Token{
+				TokenType: token.RANGE, Literal: "..",
+				Line: l.line, Column: l.column - 1,
+				EndLine: l.line, EndColumn: l.column + 1,
+			}
 		} else {
 			tok = newToken(token.ILLEGAL, l.currentChar, l.line, l.column)
 		}
@@ -86,7 +90,11 @@ func (l *Lexer) NextToken() token.Token {
 			for l.currentChar != '\n' && l.currentChar != 0 {
 				l.readChar()
 			}
-			tok = token.Token{TokenType: token.LINE_COMMENT, Literal: "", Line: l.line, Column: starterColumn}
+			tok = token.Token{
+				TokenType: token.LINE_COMMENT, Literal: "",
+				Line: l.line, Column: starterColumn,
+				EndLine: l.line, EndColumn: l.column,
+			}
 			if l.currentChar == '\n' {
 				l.line++
 				l.column = 0
@@ -103,13 +111,21 @@ func (l *Lexer) NextToken() token.Token {
 				}
 				if l.peekChar() == 0 {
 					// EOF reached without closing '*/'
-					tok = token.Token{TokenType: token.ILLEGAL, Literal: "EOF", Line: starterLine, Column: starterColumn}
+					tok = token.Token{
+						TokenType: token.ILLEGAL, Literal: "EOF",
+						Line: starterLine, Column: starterColumn,
+						EndLine: l.line, EndColumn: l.column + 1,
+					}
 					break
 				}
 
 				if l.currentChar == '*' && l.peekChar() == '/' {
 					l.readChar()
-					tok = token.Token{TokenType: token.BLOCK_COMMENT, Literal: "", Line: starterLine, Column: starterColumn}
+					tok = token.Token{
+						TokenType: token.BLOCK_COMMENT, Literal: "",
+						Line: starterLine, Column: starterColumn,
+						EndLine: l.line, EndColumn: l.column + 1,
+					}
 					break
 				}
 
@@ -126,18 +142,24 @@ func (l *Lexer) NextToken() token.Token {
 		tok.TokenType = token.EOF
 		tok.Line = l.line
 		tok.Column = l.column
+		tok.EndLine = l.line
+		tok.EndColumn = l.column
 	default:
 		if isLetter(l.currentChar) {
 			tok.Literal = l.readIdentifier()
 			tok.TokenType = token.LookupTokenType(tok.Literal)
 			tok.Line = l.line
 			tok.Column = l.column - len(tok.Literal)
+			tok.EndLine = l.line
+			tok.EndColumn = l.column
 			return tok
 		} else if isDigit(l.currentChar) {
 			tok.Literal = l.readNumber()
 			tok.TokenType = token.NUMBER
 			tok.Line = l.line
 			tok.Column = l.column - len(tok.Literal)
+			tok.EndLine = l.line
+			tok.EndColumn = l.column
 			return tok
 		} else {
 			tok = newToken(token.ILLEGAL, l.currentChar, l.line, l.column)
@@ -169,6 +191,15 @@ func (l *Lexer) readIdentifier() string {
 
 func (l *Lexer) readNumber() string {
 	starterPosition := l.currentPosition
+	if l.currentChar == '0' && isNumberPrefix(l.peekChar()) {
+		// hex (0x7FFF) or binary (0b0101) literal, the digits are validated by the parser
+		l.readChar()
+		l.readChar()
+		for isLetter(l.currentChar) || isDigit(l.currentChar) {
+			l.readChar()
+		}
+		return l.input[starterPosition:l.currentPosition]
+	}
 	for isDigit(l.currentChar) {
 		l.readChar()
 	}
@@ -198,7 +229,11 @@ func (l *Lexer) peekChar() byte {
 }
 
 func newToken(tokenType token.TokenType, literal byte, line int, column int) token.Token {
-	return token.Token{TokenType: tokenType, Literal: string(literal), Line: line, Column: column}
+	return token.Token{
+		TokenType: tokenType, Literal: string(literal),
+		Line: line, Column: column,
+		EndLine: line, EndColumn: column + 1,
+	}
 }
 
 func isLetter(ch byte) bool {
@@ -208,3 +243,7 @@ func isLetter(ch byte) bool {
 func isDigit(ch byte) bool {
 	return '0' <= ch && ch <= '9'
 }
+
+func isNumberPrefix(ch byte) bool {
+	return ch == 'x' || ch == 'X' || ch == 'b' || ch == 'B'
+}
@@ -87,6 +87,18 @@ func TestNextToken(t *testing.T) {
 				{token.EOF, "", 1, 9},
 			},
 		},
+		{
+			name:  "Numeric constants",
+			input: `0x7FFF 0b0101 0 32767 0b`,
+			expectedTokens: []expectedToken{
+				{token.NUMBER, "0x7FFF", 1, 1},
+				{token.NUMBER, "0b0101", 1, 8},
+				{token.NUMBER, "0", 1, 15},
+				{token.NUMBER, "32767", 1, 17},
+				{token.NUMBER, "0b", 1, 23},
+				{token.EOF, "", 1, 25},
+			},
+		},
 		{
 			name: "Whitespace handling",
 			input: `    foo
@@ -5,6 +5,8 @@ type ParsedChipDefinition struct {
 	Inputs   []IO
 	Outputs  []IO
 	Parts    []Part
+	Builtin  Builtin      // set instead of Parts for chips implemented in Go
+	Clocked  []ClockedPin // pins listed in the CLOCKED clause of a built-in chip
 }
 
 type ChipName struct {
@@ -23,6 +25,17 @@ type IO struct {
 	Loc   Loc
 }
 
+type Builtin struct {
+	IsSpecified bool
+	Name        string
+	Loc         Loc
+}
+
+type ClockedPin struct {
+	Name string
+	Loc  Loc
+}
+
 type Part struct {
 	Name        string
 	Connections []Connection
@@ -42,9 +55,11 @@ type Pin struct {
 }
 
 type Signal struct {
-	Name  string
-	Range Range
-	Loc   Loc
+	Name              string
+	Range             Range
+	Loc               Loc
+	IsNumericConstant bool   // decimal, binary (0b) or hex (0x) literal, Name holds the literal
+	Value             uint64 // value of the numeric constant
 }
 
 type Range struct {
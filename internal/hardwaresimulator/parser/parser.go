@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/errors"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
@@ -29,7 +30,11 @@ func (p *Parser) ParseChipDefinition() (*ParsedChipDefinition, error) {
 		return nil, err
 	}
 
-	err = p.parseChipParts()
+	if p.curTokenIs(token.BUILTIN) {
+		err = p.parseChipBuiltin()
+	} else {
+		err = p.parseChipParts()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +54,16 @@ func (p *Parser) ParseChipDefinition() (*ParsedChipDefinition, error) {
 	return p.chip, nil
 }
 
+// ParseChipHeader parses only the CHIP keyword, the name of the chip and the
+// opening brace, so that the name can be read from an unfinished definition.
+func (p *Parser) ParseChipHeader() (ChipName, error) {
+	err := p.parseChipName()
+	if err != nil {
+		return ChipName{}, err
+	}
+	return p.chip.ChipName, nil
+}
+
 func (p *Parser) parseChipName() error {
 	if !p.curTokenIs(token.CHIP) {
 		message := fmt.Sprintf("expected CHIP keyword, got [%s] => %s", p.ts.Current().TokenType, p.ts.Current().Literal)
@@ -149,8 +164,7 @@ func (p *Parser) parseIOList() ([]IO, error) {
 				return nil, newError(message, p.ts.Current().Line, p.ts.Current().Column)
 			}
 
-			var width int
-			_, err := fmt.Sscanf(p.ts.Current().Literal, "%d", &width)
+			width, err := strconv.Atoi(p.ts.Current().Literal)
 			if err != nil {
 				message := fmt.Sprintf("invalid number for width: %v", p.ts.Current().Literal)
 				return nil, newError(message, p.ts.Current().Line, p.ts.Current().Column)
@@ -186,7 +200,7 @@ func (p *Parser) parseIOList() ([]IO, error) {
 
 func (p *Parser) parseChipParts() error {
 	if !p.curTokenIs(token.PARTS) {
-		message := fmt.Sprintf("expected PARTS keyword, got [%s] => %s", p.ts.Current().TokenType, p.ts.Current().Literal)
+		message := fmt.Sprintf("expected PARTS or BUILTIN keyword, got [%s] => %s", p.ts.Current().TokenType, p.ts.Current().Literal)
 		return newError(message, p.ts.Current().Line, p.ts.Current().Column)
 	}
 	p.ts.Next()
@@ -247,6 +261,65 @@ func (p *Parser) parseChipParts() error {
 	return nil
 }
 
+func (p *Parser) parseChipBuiltin() error {
+	if !p.curTokenIs(token.BUILTIN) {
+		message := fmt.Sprintf("expected BUILTIN keyword, got [%s] => %s", p.ts.Current().TokenType, p.ts.Current().Literal)
+		return newError(message, p.ts.Current().Line, p.ts.Current().Column)
+	}
+	p.ts.Next()
+
+	if !p.curTokenIs(token.IDENTIFIER) {
+		message := fmt.Sprintf("expected built-in chip name, got [%s] => %s", p.ts.Current().TokenType, p.ts.Current().Literal)
+		return newError(message, p.ts.Current().Line, p.ts.Current().Column)
+	}
+
+	p.chip.Builtin = Builtin{
+		IsSpecified: true,
+		Name:        p.ts.Current().Literal,
+		Loc:         getLoc(p.ts.Current()),
+	}
+	p.ts.Next()
+
+	if !p.curTokenIs(token.SEMICOLON) {
+		message := fmt.Sprintf("expected ';', got [%s] => %s", p.ts.Current().TokenType, p.ts.Current().Literal)
+		return newError(message, p.ts.Current().Line, p.ts.Current().Column)
+	}
+	p.ts.Next()
+
+	if !p.curTokenIs(token.CLOCKED) {
+		return nil
+	}
+	p.ts.Next()
+
+	var clocked []ClockedPin
+	for {
+		if !p.curTokenIs(token.IDENTIFIER) {
+			message := fmt.Sprintf("expected clocked pin name, got [%s] => %s", p.ts.Current().TokenType, p.ts.Current().Literal)
+			return newError(message, p.ts.Current().Line, p.ts.Current().Column)
+		}
+		clocked = append(clocked, ClockedPin{
+			Name: p.ts.Current().Literal,
+			Loc:  getLoc(p.ts.Current()),
+		})
+		p.ts.Next()
+
+		if p.curTokenIs(token.COMMA) {
+			p.ts.Next()
+			continue
+		}
+
+		if p.curTokenIs(token.SEMICOLON) {
+			p.ts.Next()
+			break
+		}
+
+		message := fmt.Sprintf("expected ',' or ';', got [%s] => %s", p.ts.Current().TokenType, p.ts.Current().Literal)
+		return newError(message, p.ts.Current().Line, p.ts.Current().Column)
+	}
+	p.chip.Clocked = clocked
+	return nil
+}
+
 func (p *Parser) parsePartConnections(part *Part) error {
 	var connections []Connection
 	var currentConnection Connection
@@ -290,9 +363,9 @@ func (p *Parser) parsePartConnections(part *Part) error {
 
 		p.ts.Next()
 
-		isBooleanConstant := p.curTokenIs(token.TRUE) || p.curTokenIs(token.FALSE)
+		isConstant := p.curTokenIs(token.TRUE) || p.curTokenIs(token.FALSE) || p.curTokenIs(token.NUMBER)
 
-		if !p.curTokenIs(token.IDENTIFIER) && !p.curTokenIs(token.TRUE) && !p.curTokenIs(token.FALSE) {
+		if !p.curTokenIs(token.IDENTIFIER) && !isConstant {
 			message := fmt.Sprintf("expected signal name, got [%s] => %s", p.ts.Current().TokenType, p.ts.Current().Literal)
 			return newError(message, p.ts.Current().Line, p.ts.Current().Column)
 		}
@@ -302,11 +375,20 @@ func (p *Parser) parsePartConnections(part *Part) error {
 			Range: Range{Start: 0, End: 0, Loc: getLoc(p.ts.Current())},
 			Loc:   getLoc(p.ts.Current()),
 		}
+		if p.curTokenIs(token.NUMBER) {
+			value, err := parseNumber(p.ts.Current().Literal)
+			if err != nil {
+				message := fmt.Sprintf("invalid numeric constant: %v", p.ts.Current().Literal)
+				return newError(message, p.ts.Current().Line, p.ts.Current().Column)
+			}
+			currentConnection.Signal.IsNumericConstant = true
+			currentConnection.Signal.Value = value
+		}
 		p.ts.Next()
 
-		if p.curTokenIs(token.LBRACKET) && isBooleanConstant {
+		if p.curTokenIs(token.LBRACKET) && isConstant {
 			message := fmt.Sprintf(
-				"unexpected range for boolean constant, got [%s] => %s",
+				"unexpected range for constant, got [%s] => %s",
 				p.ts.Current().TokenType, p.ts.Current().Literal,
 			)
 			return newError(message, p.ts.Current().Line, p.ts.Current().Column)
@@ -358,10 +440,7 @@ func (p *Parser) parseRange(r *Range) error {
 		return newError(message, p.ts.Current().Line, p.ts.Current().Column)
 	}
 
-	var start int
-	var end int
-
-	_, err := fmt.Sscanf(p.ts.Current().Literal, "%d", &start)
+	start, err := strconv.Atoi(p.ts.Current().Literal)
 	if err != nil {
 		message := fmt.Sprintf("invalid number for range: %v", p.ts.Current().Literal)
 		return newError(message, p.ts.Current().Line, p.ts.Current().Column)
@@ -371,6 +450,7 @@ func (p *Parser) parseRange(r *Range) error {
 	p.ts.Next() // advance to next token which can be '..' or ']'
 	// later will be checked by the caller
 
+	var end int
 	if p.curTokenIs(token.RANGE) {
 		p.ts.Next()
 		if !p.curTokenIs(token.NUMBER) {
@@ -378,7 +458,7 @@ func (p *Parser) parseRange(r *Range) error {
 			return newError(message, p.ts.Current().Line, p.ts.Current().Column)
 		}
 
-		_, err := fmt.Sscanf(p.ts.Current().Literal, "%d", &end)
+		end, err = strconv.Atoi(p.ts.Current().Literal)
 		if err != nil {
 			message := fmt.Sprintf("invalid number for range end: %v", p.ts.Current().Literal)
 			return newError(message, p.ts.Current().Line, p.ts.Current().Column)
@@ -393,6 +473,19 @@ func (p *Parser) parseRange(r *Range) error {
 	return nil
 }
 
+// parseNumber parses a decimal, binary (0b0101) or hex (0x7FFF) literal.
+func parseNumber(literal string) (uint64, error) {
+	if len(literal) > 2 && literal[0] == '0' {
+		switch literal[1] {
+		case 'x', 'X':
+			return strconv.ParseUint(literal[2:], 16, 64)
+		case 'b', 'B':
+			return strconv.ParseUint(literal[2:], 2, 64)
+		}
+	}
+	return strconv.ParseUint(literal, 10, 64)
+}
+
 func (p *Parser) curTokenIs(t token.TokenType) bool {
 	return p.ts.Current() != nil && p.ts.Current().TokenType == t
 }
@@ -151,7 +151,7 @@ func TestParseChipDefinition(t *testing.T) {
 		{
 			name:          "Missing PARTS keyword",
 			input:         `CHIP HalfAdder { IN a, b; OUT out; nand(a=a, b=b); }`,
-			expectedError: "Parser error at line 1, column 36: expected PARTS keyword, got [IDENTIFIER] => nand",
+			expectedError: "Parser error at line 1, column 36: expected PARTS or BUILTIN keyword, got [IDENTIFIER] => nand",
 		},
 		{
 			name:          "Missing ':' after PARTS keyword",
@@ -326,6 +326,157 @@ func TestParseChipDefinition(t *testing.T) {
 	}
 }
 
+func TestParseBuiltinChipDefinition(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		expectedError   string
+		expectedBuiltin Builtin
+		expectedClocked []ClockedPin
+	}{
+		{
+			name: "Combinational built-in chip",
+			input: `/** Nand gate */
+CHIP Nand {
+    IN a, b;
+    OUT out;
+
+    BUILTIN Nand;
+}`,
+			expectedBuiltin: Builtin{IsSpecified: true, Name: "Nand", Loc: Loc{Line: 6, Column: 13}},
+		},
+		{
+			name: "Clocked built-in chip",
+			input: `CHIP Bit {
+    IN in, load;
+    OUT out;
+
+    BUILTIN Bit;
+    CLOCKED in, load;
+}`,
+			expectedBuiltin: Builtin{IsSpecified: true, Name: "Bit", Loc: Loc{Line: 5, Column: 13}},
+			expectedClocked: []ClockedPin{
+				{Name: "in", Loc: Loc{Line: 6, Column: 13}},
+				{Name: "load", Loc: Loc{Line: 6, Column: 17}},
+			},
+		},
+		{
+			name:          "Missing built-in chip name",
+			input:         `CHIP Nand { IN a, b; OUT out; BUILTIN; }`,
+			expectedError: "Parser error at line 1, column 38: expected built-in chip name, got [;] => ;",
+		},
+		{
+			name:          "Missing ';' after built-in chip name",
+			input:         `CHIP Nand { IN a, b; OUT out; BUILTIN Nand }`,
+			expectedError: "Parser error at line 1, column 44: expected ';', got [}] => }",
+		},
+		{
+			name:          "Missing clocked pin name",
+			input:         `CHIP DFF { IN in; OUT out; BUILTIN DFF; CLOCKED; }`,
+			expectedError: "Parser error at line 1, column 48: expected clocked pin name, got [;] => ;",
+		},
+		{
+			name:          "Missing ';' after clocked pins",
+			input:         `CHIP DFF { IN in; OUT out; BUILTIN DFF; CLOCKED in }`,
+			expectedError: "Parser error at line 1, column 52: expected ',' or ';', got [}] => }",
+		},
+		{
+			name:          "Parts after built-in clause",
+			input:         `CHIP DFF { IN in; OUT out; BUILTIN DFF; PARTS: }`,
+			expectedError: "Parser error at line 1, column 41: expected '}', got [PARTS] => PARTS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l := lexer.New(tt.input)
+			ts, err := l.Tokenize()
+			if err != nil {
+				t.Fatalf("Failed to tokenize input: %v", err)
+			}
+
+			chip, err := New(ts).ParseChipDefinition()
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Fatalf("Expected error '%s', got nil", tt.expectedError)
+				}
+				assert.Equal(t, tt.expectedError, err.Error(), "Error message mismatch")
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Failed to parse chip definition: %v", err)
+			}
+
+			assert.Equal(t, tt.expectedBuiltin, chip.Builtin)
+			assert.Equal(t, tt.expectedClocked, chip.Clocked)
+			assert.Empty(t, chip.Parts)
+		})
+	}
+}
+
+func TestParseNumericConstants(t *testing.T) {
+	tests := []struct {
+		name          string
+		signal        string
+		expectedError string
+		expectedValue uint64
+	}{
+		{name: "Decimal constant", signal: "32767", expectedValue: 32767},
+		{name: "Hex constant", signal: "0x7FFF", expectedValue: 0x7FFF},
+		{name: "Lowercase hex constant", signal: "0xbeef", expectedValue: 0xBEEF},
+		{name: "Binary constant", signal: "0b0000000000000101", expectedValue: 5},
+		{name: "Zero", signal: "0", expectedValue: 0},
+		{
+			name:          "Invalid hex digit",
+			signal:        "0x7G",
+			expectedError: "Parser error at line 1, column 48: invalid numeric constant: 0x7G",
+		},
+		{
+			name:          "Invalid binary digit",
+			signal:        "0b102",
+			expectedError: "Parser error at line 1, column 48: invalid numeric constant: 0b102",
+		},
+		{
+			name:          "Range on constant",
+			signal:        "0x1[0]",
+			expectedError: "Parser error at line 1, column 51: unexpected range for constant, got [[] => [",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := `CHIP C { IN a[16]; OUT out[16]; PARTS: Mux16(a=` + tt.signal + `, b=a, sel=false, out=out); }`
+			l := lexer.New(input)
+			ts, err := l.Tokenize()
+			if err != nil {
+				t.Fatalf("Failed to tokenize input: %v", err)
+			}
+
+			chip, err := New(ts).ParseChipDefinition()
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Fatalf("Expected error '%s', got nil", tt.expectedError)
+				}
+				assert.Equal(t, tt.expectedError, err.Error(), "Error message mismatch")
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Failed to parse chip definition: %v", err)
+			}
+
+			signal := chip.Parts[0].Connections[0].Signal
+			assert.Equal(t, tt.signal, signal.Name)
+			assert.True(t, signal.IsNumericConstant)
+			assert.Equal(t, tt.expectedValue, signal.Value)
+			assert.False(t, chip.Parts[0].Connections[2].Signal.IsNumericConstant, "boolean constants are not numeric")
+		})
+	}
+}
+
 func locsMustEqual(t *testing.T, context string, got, expected Loc) {
 	assert.Equal(t, expected.Line, got.Line, context+" line mismatch")
 	assert.Equal(t, expected.Column, got.Column, context+" column mismatch")
@@ -337,3 +488,46 @@ func rangesMustEqual(t *testing.T, context string, got, expected Range) {
 	assert.Equal(t, expected.IsSpecified, got.IsSpecified, context+" range IsSpecified mismatch")
 	locsMustEqual(t, context+" range", got.Loc, expected.Loc)
 }
+
+func TestParseChipHeader(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedError string
+		expectedName  ChipName
+	}{
+		{
+			name:         "Unfinished chip",
+			input:        "// Stub\nCHIP Add16 {\n    IN a[16], b[16];\n    PARTS:\n}",
+			expectedName: ChipName{Name: "Add16", Loc: Loc{Line: 2, Column: 6}},
+		},
+		{
+			name:          "Missing chip name",
+			input:         "CHIP {",
+			expectedError: "Parser error at line 1, column 6: expected chip name, got [{] => {",
+		},
+		{
+			name:          "Missing CHIP keyword",
+			input:         "Add16 {",
+			expectedError: "Parser error at line 1, column 1: expected CHIP keyword, got [IDENTIFIER] => Add16",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := lexer.New(tt.input).Tokenize()
+			if err != nil {
+				t.Fatalf("Failed to tokenize input: %v", err)
+			}
+
+			chipName, err := New(ts).ParseChipHeader()
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedName, chipName)
+		})
+	}
+}
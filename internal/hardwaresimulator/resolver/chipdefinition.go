@@ -1,6 +1,9 @@
 package resolver
 
-import "github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+import (
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
+)
 
 type ResolvedChipDefinition struct {
 	Name            string
@@ -8,12 +11,14 @@ type ResolvedChipDefinition struct {
 	Outputs         map[string]chips.IO
 	Parts           []Part
 	InternalSignals map[string]InternalSignal
+	BuiltinName     string // name of the Go implementation, empty if the chip is built from parts
 }
 
 type Part struct {
 	Name              string
 	InputConnections  []Connection
 	OutputConnections []Connection
+	Loc               parser.Loc
 }
 
 type Connection struct {
@@ -27,8 +32,10 @@ type Pin struct {
 }
 
 type Signal struct {
-	Name  string
-	Range Range
+	Name              string
+	Range             Range
+	IsNumericConstant bool
+	Value             uint64 // bit i of the value drives bit Range.Start+i of the pin
 }
 
 type Range struct {
@@ -20,6 +20,7 @@ type Resolver struct {
 	chd                       *parser.ParsedChipDefinition
 	chipFileName              string
 	hdls                      map[string]string
+	registry                  *chips.Registry
 	resolvedChipDef           *ResolvedChipDefinition
 	resolvedUsedChipDefs      map[string]*ResolvedChipDefinition
 	chipOutputSignalCoverages map[string]signalCoverage
@@ -37,7 +38,7 @@ type connection struct {
 type signalCoverage map[int]bool
 type pinCoverage map[int]bool
 
-func New(chd *parser.ParsedChipDefinition, chipFileName string, hdls map[string]string) *Resolver {
+func New(chd *parser.ParsedChipDefinition, chipFileName string, hdls map[string]string, registry *chips.Registry) *Resolver {
 	resolvedChipDef := &ResolvedChipDefinition{
 		Inputs:          make(map[string]chips.IO),
 		Outputs:         make(map[string]chips.IO),
@@ -54,6 +55,7 @@ func New(chd *parser.ParsedChipDefinition, chipFileName string, hdls map[string]
 		chipOutputSignalCoverages: chipOutputSignalCoverages,
 		partInputPinCoverages:     partInputPinCoverages,
 		hdls:                      hdls,
+		registry:                  registry,
 	}
 }
 
@@ -76,6 +78,13 @@ func (r *Resolver) Resolve(resolvedChipNames []string, resolvingChipNames []stri
 		return nil, map[string]*ResolvedChipDefinition{}, err
 	}
 
+	if r.chd.Builtin.IsSpecified {
+		if err := r.resolveBuiltin(); err != nil {
+			return nil, map[string]*ResolvedChipDefinition{}, err
+		}
+		return r.resolvedChipDef, r.resolvedUsedChipDefs, nil
+	}
+
 	if err := r.validateNumberOfParts(); err != nil {
 		return nil, map[string]*ResolvedChipDefinition{}, err
 	}
@@ -87,6 +96,7 @@ func (r *Resolver) Resolve(resolvedChipNames []string, resolvingChipNames []stri
 	for _, part := range r.chd.Parts {
 		r.resolvedChipDef.Parts = append(r.resolvedChipDef.Parts, Part{
 			Name: part.Name,
+			Loc:  part.Loc,
 		})
 	}
 
@@ -188,6 +198,94 @@ func (r *Resolver) resolveIO() error {
 	return nil
 }
 
+// resolveBuiltin binds a chip declared with the BUILTIN clause to the Go implementation
+// with the same name. The declared IO has to match the implementation exactly.
+func (r *Resolver) resolveBuiltin() error {
+	builtin := r.chd.Builtin
+	builtinImpl, ok := r.registry.Lookup(builtin.Name)
+	if !ok {
+		return r.newResolutionError(
+			fmt.Sprintf("Built-in chip '%s' does not exist", builtin.Name),
+			builtin.Loc.Line, builtin.Loc.Column,
+		)
+	}
+	builtinChip := builtinImpl.Signature()
+
+	for _, input := range r.chd.Inputs {
+		builtinInput, ok := builtinChip.Inputs[input.Name]
+		if !ok {
+			return r.newResolutionError(
+				fmt.Sprintf("Built-in chip '%s' has no input '%s'", builtin.Name, input.Name),
+				input.Loc.Line, input.Loc.Column,
+			)
+		}
+		if builtinInput.Width != input.Width {
+			return r.newResolutionError(
+				fmt.Sprintf("Input '%s' width does not match built-in chip '%s'", input.Name, builtin.Name),
+				input.Loc.Line, input.Loc.Column,
+			)
+		}
+	}
+
+	for _, output := range r.chd.Outputs {
+		builtinOutput, ok := builtinChip.Outputs[output.Name]
+		if !ok {
+			return r.newResolutionError(
+				fmt.Sprintf("Built-in chip '%s' has no output '%s'", builtin.Name, output.Name),
+				output.Loc.Line, output.Loc.Column,
+			)
+		}
+		if builtinOutput.Width != output.Width {
+			return r.newResolutionError(
+				fmt.Sprintf("Output '%s' width does not match built-in chip '%s'", output.Name, builtin.Name),
+				output.Loc.Line, output.Loc.Column,
+			)
+		}
+	}
+
+	for inputName := range builtinChip.Inputs {
+		if _, ok := r.resolvedChipDef.Inputs[inputName]; !ok {
+			return r.newResolutionError(
+				fmt.Sprintf("Input '%s' of built-in chip '%s' is not declared", inputName, builtin.Name),
+				builtin.Loc.Line, builtin.Loc.Column,
+			)
+		}
+	}
+
+	for outputName := range builtinChip.Outputs {
+		if _, ok := r.resolvedChipDef.Outputs[outputName]; !ok {
+			return r.newResolutionError(
+				fmt.Sprintf("Output '%s' of built-in chip '%s' is not declared", outputName, builtin.Name),
+				builtin.Loc.Line, builtin.Loc.Column,
+			)
+		}
+	}
+
+	// the CLOCKED clause is only validated, which outputs are sequential is
+	// decided by the Go implementation
+	var clockedPins []string
+	for _, clocked := range r.chd.Clocked {
+		_, isInput := r.resolvedChipDef.Inputs[clocked.Name]
+		_, isOutput := r.resolvedChipDef.Outputs[clocked.Name]
+		if !isInput && !isOutput {
+			return r.newResolutionError(
+				fmt.Sprintf("Clocked pin '%s' is neither an input nor an output", clocked.Name),
+				clocked.Loc.Line, clocked.Loc.Column,
+			)
+		}
+		if slices.Contains(clockedPins, clocked.Name) {
+			return r.newResolutionError(
+				fmt.Sprintf("Duplicate clocked pin '%s'", clocked.Name),
+				clocked.Loc.Line, clocked.Loc.Column,
+			)
+		}
+		clockedPins = append(clockedPins, clocked.Name)
+	}
+
+	r.resolvedChipDef.BuiltinName = builtin.Name
+	return nil
+}
+
 func (r *Resolver) validateNumberOfParts() error {
 	if len(r.chd.Parts) > MAX_NUMBER_OF_PARTS {
 		return r.newResolutionError(
@@ -222,7 +320,7 @@ func (r *Resolver) resolveUsedChips(resolvedChipNames []string, resolvingChipNam
 			return err
 		}
 
-		r2 := New(chd, chipName, r.hdls)
+		r2 := New(chd, chipName, r.hdls, r.registry)
 		r2.SetResolvedUsedChipDefs(r.resolvedUsedChipDefs)
 		resolvedChipDef, resolvedUsedChipDefs, err := r2.Resolve(resolvedChipNames, resolvingChipNames)
 		if err != nil {
@@ -246,8 +344,7 @@ func (r *Resolver) resolveUsedChips(resolvedChipNames []string, resolvingChipNam
 
 func (r *Resolver) groupUsedChipNames(usedChipNames []string) (builtInChipNames []string, customChipNames []string, err error) {
 	for _, name := range usedChipNames {
-		_, ok := chips.BuiltInChips[name]
-		if ok {
+		if r.registry.Has(name) {
 			builtInChipNames = append(builtInChipNames, name)
 			continue
 		}
@@ -293,9 +390,10 @@ func (r *Resolver) getInputAndOutputConnections() (inputConnections []connection
 		} else {
 			// part is a built-in chip
 			// don't need to validate that it exists, as it was already validated in groupUsedChipNames
-			builtInChip := chips.BuiltInChips[part.Name]
-			partInputs = builtInChip.Inputs
-			partOutputs = builtInChip.Outputs
+			builtInChip, _ := r.registry.Lookup(part.Name)
+			signature := builtInChip.Signature()
+			partInputs = signature.Inputs
+			partOutputs = signature.Outputs
 		}
 
 		for _, conn := range part.Connections {
@@ -379,6 +477,12 @@ func (r *Resolver) resolveOutputConnectionPin(resolvedConnection *Connection, co
 func (r *Resolver) resolveOutputConnectionSignal(resolvedConn *Connection, conn connection) error {
 	signal := Signal{}
 	signal.Name = conn.Signal.Name
+	if conn.Signal.IsNumericConstant || signal.Name == "true" || signal.Name == "false" {
+		return r.newResolutionError(
+			fmt.Sprintf("Constant '%s' cannot be used as an output signal", conn.Signal.Name),
+			conn.Signal.Loc.Line, conn.Signal.Loc.Column,
+		)
+	}
 	if conn.Signal.Range.IsSpecified {
 		if _, isChipOutput := r.resolvedChipDef.Outputs[signal.Name]; !isChipOutput {
 			return r.newResolutionError(
@@ -516,6 +620,10 @@ func (r *Resolver) resolveInputConnectionSignal(resolvedConn *Connection, conn c
 	_ = internalSignal
 	_ = inputIO
 
+	if conn.Signal.IsNumericConstant {
+		return r.resolveNumericConstant(resolvedConn, conn)
+	}
+
 	if !isInternalSignal && !isChipInput && !isBooleanConstant {
 		return r.newResolutionError(
 			fmt.Sprintf("Signal '%s' is neither an internal signal nor a chip input", conn.Signal.Name),
@@ -596,6 +704,25 @@ func (r *Resolver) resolveInputConnectionSignal(resolvedConn *Connection, conn c
 	return nil
 }
 
+// resolveNumericConstant sizes the constant to the connected pin range and
+// checks that its value fits in it.
+func (r *Resolver) resolveNumericConstant(resolvedConn *Connection, conn connection) error {
+	width := resolvedConn.Pin.Range.End - resolvedConn.Pin.Range.Start + 1
+	if width < 64 && conn.Signal.Value>>width != 0 {
+		return r.newResolutionError(
+			fmt.Sprintf("Constant '%s' does not fit in pin '%s' of width %d", conn.Signal.Name, resolvedConn.Pin.Name, width),
+			conn.Signal.Loc.Line, conn.Signal.Loc.Column,
+		)
+	}
+	resolvedConn.Signal = Signal{
+		Name:              conn.Signal.Name,
+		Range:             Range{Start: 0, End: width - 1},
+		IsNumericConstant: true,
+		Value:             conn.Signal.Value,
+	}
+	return nil
+}
+
 func addRangeToSignalCoverages(signalCoverages map[string]signalCoverage, signalName string, rng Range) bool {
 	if _, ok := signalCoverages[signalName]; !ok {
 		signalCoverages[signalName] = make(signalCoverage)
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
 	"github.com/stretchr/testify/assert"
@@ -675,6 +676,170 @@ func TestResolve(t *testing.T) {
 			},
 			expectedError: "Resolution error at line 6, column 14: Signal 'c' range width does not match pin 'a' range width",
 		},
+		{
+			name:         "Built-in chip",
+			chipFileName: "Bit",
+			hdls: map[string]string{
+				"Bit": `CHIP Bit {
+    IN in, load;
+    OUT out;
+    BUILTIN Bit;
+    CLOCKED in, load;
+}`,
+			},
+			expectedResolvedChipDef: ResolvedChipDefinition{
+				Name:        "Bit",
+				BuiltinName: "Bit",
+			},
+			expectedResolvedUsedChipDefs: map[string]ResolvedChipDefinition{},
+		},
+		{
+			name:         "Custom chip using a built-in chip declaration",
+			chipFileName: "And",
+			hdls: map[string]string{
+				"And": `CHIP And {
+    IN a, b;
+    OUT out;
+
+    PARTS:
+    MyNand(a=a, b=b, out=nandOut);
+    MyNand(a=nandOut, b=nandOut, out=out);
+}`,
+				"MyNand": `CHIP MyNand {
+    IN a, b;
+    OUT out;
+    BUILTIN Nand;
+}`,
+			},
+			expectedResolvedChipDef: ResolvedChipDefinition{
+				Name: "And",
+			},
+			expectedResolvedUsedChipDefs: map[string]ResolvedChipDefinition{
+				"MyNand": {
+					Name: "MyNand",
+				},
+			},
+		},
+		{
+			name:         "Unknown built-in chip",
+			chipFileName: "Screen",
+			hdls: map[string]string{
+				"Screen": `CHIP Screen {
+    IN in[16], load, address[13];
+    OUT out[16];
+    BUILTIN Screen;
+}`,
+			},
+			expectedError: "Resolution error at line 4, column 13: Built-in chip 'Screen' does not exist",
+		},
+		{
+			name:         "Built-in chip with unknown input",
+			chipFileName: "Not",
+			hdls: map[string]string{
+				"Not": `CHIP Not {
+    IN a;
+    OUT out;
+    BUILTIN Not;
+}`,
+			},
+			expectedError: "Resolution error at line 2, column 8: Built-in chip 'Not' has no input 'a'",
+		},
+		{
+			name:         "Built-in chip with wrong output width",
+			chipFileName: "Not16",
+			hdls: map[string]string{
+				"Not16": `CHIP Not16 {
+    IN in[16];
+    OUT out[8];
+    BUILTIN Not16;
+}`,
+			},
+			expectedError: "Resolution error at line 3, column 9: Output 'out' width does not match built-in chip 'Not16'",
+		},
+		{
+			name:         "Built-in chip with missing input",
+			chipFileName: "And",
+			hdls: map[string]string{
+				"And": `CHIP And {
+    IN a;
+    OUT out;
+    BUILTIN And;
+}`,
+			},
+			expectedError: "Resolution error at line 4, column 13: Input 'b' of built-in chip 'And' is not declared",
+		},
+		{
+			name:         "Built-in chip with unknown clocked pin",
+			chipFileName: "DFF",
+			hdls: map[string]string{
+				"DFF": `CHIP DFF {
+    IN in;
+    OUT out;
+    BUILTIN DFF;
+    CLOCKED load;
+}`,
+			},
+			expectedError: "Resolution error at line 5, column 13: Clocked pin 'load' is neither an input nor an output",
+		},
+		{
+			name:         "Numeric constants on full pins and pin ranges",
+			chipFileName: "Const",
+			hdls: map[string]string{
+				"Const": `CHIP Const {
+    IN sel;
+    OUT out[16];
+
+    PARTS:
+    Mux16(a=0x7FFF, b[0..7]=0b00000001, b[8..15]=255, sel=sel, out=out);
+}`,
+			},
+			expectedResolvedChipDef: ResolvedChipDefinition{
+				Name: "Const",
+			},
+			expectedResolvedUsedChipDefs: map[string]ResolvedChipDefinition{},
+		},
+		{
+			name:         "Numeric constant does not fit in pin",
+			chipFileName: "Const",
+			hdls: map[string]string{
+				"Const": `CHIP Const {
+    IN sel;
+    OUT out[16];
+
+    PARTS:
+    Mux16(a=0x7FFF, b[0..3]=16, sel=sel, out=out);
+}`,
+			},
+			expectedError: "Resolution error at line 6, column 29: Constant '16' does not fit in pin 'b' of width 4",
+		},
+		{
+			name:         "Numeric constant wider than a single bit pin",
+			chipFileName: "Const",
+			hdls: map[string]string{
+				"Const": `CHIP Const {
+    IN a;
+    OUT out;
+
+    PARTS:
+    And(a=a, b=0b10, out=out);
+}`,
+			},
+			expectedError: "Resolution error at line 6, column 16: Constant '0b10' does not fit in pin 'b' of width 1",
+		},
+		{
+			name:         "Numeric constant as output signal",
+			chipFileName: "Const",
+			hdls: map[string]string{
+				"Const": `CHIP Const {
+    IN a;
+    OUT out;
+
+    PARTS:
+    Not(in=a, out=1);
+}`,
+			},
+			expectedError: "Resolution error at line 6, column 19: Constant '1' cannot be used as an output signal",
+		},
 	}
 
 	for _, tt := range tests {
@@ -682,7 +847,7 @@ func TestResolve(t *testing.T) {
 			// t.Parallel()
 
 			chd := mustLexAndParse(t, tt.hdls[tt.chipFileName])
-			r := New(chd, tt.chipFileName, tt.hdls)
+			r := New(chd, tt.chipFileName, tt.hdls, chips.NewDefaultRegistry())
 			resolvedChipDef, resolvedUsedChipDefs, err := r.Resolve([]string{}, []string{})
 			if tt.expectedError != "" {
 				if err == nil {
@@ -697,6 +862,7 @@ func TestResolve(t *testing.T) {
 
 			// Compare resolvedChipDef
 			assert.Equal(t, tt.expectedResolvedChipDef.Name, resolvedChipDef.Name, "resolvedChipDef.Name mismatch")
+			assert.Equal(t, tt.expectedResolvedChipDef.BuiltinName, resolvedChipDef.BuiltinName, "resolvedChipDef.BuiltinName mismatch")
 
 			// Compare resolvedUsedChipDefs
 			assert.Equal(t, len(tt.expectedResolvedUsedChipDefs), len(resolvedUsedChipDefs), "resolvedUsedChipDefs length mismatch")
@@ -712,6 +878,42 @@ func TestResolve(t *testing.T) {
 	}
 }
 
+func TestResolveNumericConstants(t *testing.T) {
+	hdl := `CHIP Const {
+    IN sel;
+    OUT out[16];
+
+    PARTS:
+    Mux16(a=0x7FFF, b[0..7]=0b00000101, b[8..15]=1, sel=sel, out=out);
+}`
+	chd := mustLexAndParse(t, hdl)
+	r := New(chd, "Const", map[string]string{"Const": hdl}, chips.NewDefaultRegistry())
+	resolvedChipDef, _, err := r.Resolve([]string{}, []string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Connection{
+		{
+			Pin:    Pin{Name: "a", Range: Range{Start: 0, End: 15}},
+			Signal: Signal{Name: "0x7FFF", Range: Range{Start: 0, End: 15}, IsNumericConstant: true, Value: 0x7FFF},
+		},
+		{
+			Pin:    Pin{Name: "b", Range: Range{Start: 0, End: 7}},
+			Signal: Signal{Name: "0b00000101", Range: Range{Start: 0, End: 7}, IsNumericConstant: true, Value: 5},
+		},
+		{
+			Pin:    Pin{Name: "b", Range: Range{Start: 8, End: 15}},
+			Signal: Signal{Name: "1", Range: Range{Start: 0, End: 7}, IsNumericConstant: true, Value: 1},
+		},
+		{
+			Pin:    Pin{Name: "sel", Range: Range{Start: 0, End: 0}},
+			Signal: Signal{Name: "sel", Range: Range{Start: 0, End: 0}},
+		},
+	}
+	assert.Equal(t, expected, resolvedChipDef.Parts[0].InputConnections)
+}
+
 func mustLexAndParse(t *testing.T, hdl string) *parser.ParsedChipDefinition {
 	t.Helper()
 
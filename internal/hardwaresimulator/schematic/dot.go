@@ -0,0 +1,164 @@
+package schematic
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DOT renders the schematic as a Graphviz digraph flowing from left to right.
+// Parts are records with their inputs on the left and outputs on the right,
+// expanded parts are clusters. Nets wider than a bit are drawn bold and
+// labeled with their width, ranges of buses are shown at the ends of the nets.
+func (s *Schematic) DOT() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("digraph %q {\n", s.Chip))
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [shape=record];\n")
+	s.writeDOT(&sb, "", "\t")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// writeDOT writes the nodes and edges of the schematic, prefixing the node ids
+// so that those of expanded parts don't collide with the ones of their parent.
+func (s *Schematic) writeDOT(sb *strings.Builder, prefix string, indent string) {
+	for _, port := range s.Inputs {
+		sb.WriteString(fmt.Sprintf("%s%q [label=%q, shape=rarrow];\n", indent, prefix+"in_"+port.Name, portLabel(port)))
+	}
+	for _, port := range s.Outputs {
+		sb.WriteString(fmt.Sprintf("%s%q [label=%q, shape=rarrow];\n", indent, prefix+"out_"+port.Name, portLabel(port)))
+	}
+
+	var constants []string
+	for _, net := range s.Nets {
+		if net.Source.Constant && !slices.Contains(constants, net.Source.Pin) {
+			constants = append(constants, net.Source.Pin)
+		}
+	}
+	slices.Sort(constants)
+	for _, constant := range constants {
+		sb.WriteString(fmt.Sprintf("%s%q [label=%q, shape=plaintext];\n", indent, prefix+"const_"+constant, constant))
+	}
+
+	for _, part := range s.Parts {
+		if part.Schematic != nil {
+			sb.WriteString(fmt.Sprintf("%ssubgraph %q {\n", indent, "cluster_"+prefix+part.ID))
+			sb.WriteString(fmt.Sprintf("%s\tlabel=%q;\n", indent, part.Chip))
+			part.Schematic.writeDOT(sb, prefix+part.ID+"_", indent+"\t")
+			sb.WriteString(indent + "}\n")
+			continue
+		}
+
+		var inputs, outputs []string
+		for _, port := range part.Inputs {
+			inputs = append(inputs, fmt.Sprintf("<in_%s> %s", port.Name, portLabel(port)))
+		}
+		for _, port := range part.Outputs {
+			outputs = append(outputs, fmt.Sprintf("<out_%s> %s", port.Name, portLabel(port)))
+		}
+		label := fmt.Sprintf("{{%s}|%s|{%s}}", strings.Join(inputs, "|"), part.Chip, strings.Join(outputs, "|"))
+		sb.WriteString(fmt.Sprintf("%s%q [label=%q];\n", indent, prefix+part.ID, label))
+	}
+
+	for _, net := range s.Nets {
+		var attributes []string
+		label := net.Name
+		if net.Width > 1 {
+			label = strings.TrimSpace(fmt.Sprintf("%s /%d", label, net.Width))
+			attributes = append(attributes, "style=bold")
+		}
+		if label != "" {
+			attributes = append(attributes, fmt.Sprintf("label=%q", label))
+		}
+		if r := rangeText(net.Source, s.width(net.Source, true)); r != "" {
+			attributes = append(attributes, fmt.Sprintf("taillabel=%q", r))
+		}
+		if r := rangeText(net.Target, s.width(net.Target, false)); r != "" {
+			attributes = append(attributes, fmt.Sprintf("headlabel=%q", r))
+		}
+
+		edge := fmt.Sprintf("%s%s -> %s", indent, s.dotID(prefix, net.Source, true), s.dotID(prefix, net.Target, false))
+		if len(attributes) > 0 {
+			edge += " [" + strings.Join(attributes, ", ") + "]"
+		}
+		sb.WriteString(edge + ";\n")
+	}
+}
+
+// dotID returns the node, with the port of the record if needed, that the end
+// of a net is drawn to.
+func (s *Schematic) dotID(prefix string, e Endpoint, isSource bool) string {
+	direction := "in_"
+	if isSource {
+		direction = "out_"
+	}
+
+	switch {
+	case e.Constant:
+		return fmt.Sprintf("%q", prefix+"const_"+e.Pin)
+	case e.Part == "" && isSource:
+		return fmt.Sprintf("%q", prefix+"in_"+e.Pin)
+	case e.Part == "":
+		return fmt.Sprintf("%q", prefix+"out_"+e.Pin)
+	}
+
+	part := s.part(e.Part)
+	if part.Schematic != nil {
+		// the pins of the chip inside the cluster, sources are its outputs
+		return fmt.Sprintf("%q", prefix+part.ID+"_"+direction+e.Pin)
+	}
+	return fmt.Sprintf("%q:%q", prefix+part.ID, direction+e.Pin)
+}
+
+func (s *Schematic) part(id string) *Part {
+	for i := range s.Parts {
+		if s.Parts[i].ID == id {
+			return &s.Parts[i]
+		}
+	}
+	return nil
+}
+
+// width returns the width of the pin of the endpoint, 0 for constants.
+func (s *Schematic) width(e Endpoint, isSource bool) int {
+	if e.Constant {
+		return 0
+	}
+
+	var ports []Port
+	switch {
+	case e.Part == "" && isSource:
+		ports = s.Inputs
+	case e.Part == "":
+		ports = s.Outputs
+	case isSource:
+		ports = s.part(e.Part).Outputs
+	default:
+		ports = s.part(e.Part).Inputs
+	}
+	for _, port := range ports {
+		if port.Name == e.Pin {
+			return port.Width
+		}
+	}
+	return 0
+}
+
+// rangeText returns the range of the endpoint if it is a part of a bus.
+func rangeText(e Endpoint, width int) string {
+	if width <= 1 || (e.From == 0 && e.To == width-1) {
+		return ""
+	}
+	if e.From == e.To {
+		return fmt.Sprintf("[%d]", e.From)
+	}
+	return fmt.Sprintf("[%d..%d]", e.From, e.To)
+}
+
+func portLabel(port Port) string {
+	if port.Width == 1 {
+		return port.Name
+	}
+	return fmt.Sprintf("%s[%d]", port.Name, port.Width)
+}
@@ -0,0 +1,179 @@
+package schematic
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+)
+
+// Schematic describes the parts of a chip and the wires between them, to draw
+// block diagrams. Pins are listed in alphabetical order and parts in the order
+// they appear in the HDL.
+type Schematic struct {
+	Chip    string `json:"chip"`
+	Inputs  []Port `json:"inputs"`
+	Outputs []Port `json:"outputs"`
+	Parts   []Part `json:"parts"`
+	Nets    []Net  `json:"nets"`
+}
+
+type Port struct {
+	Name  string `json:"name"`
+	Width int    `json:"width"`
+}
+
+type Part struct {
+	ID      string `json:"id"` // unique within the schematic
+	Chip    string `json:"chip"`
+	Builtin bool   `json:"builtin"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Inputs  []Port `json:"inputs"`
+	Outputs []Port `json:"outputs"`
+	// Schematic of the chip of the part, nil for built-in chips and for parts
+	// deeper than the requested depth.
+	Schematic *Schematic `json:"schematic,omitempty"`
+}
+
+// Endpoint is a range of bits of a pin. Part is empty for the pins of the chip
+// itself, and constants have Constant set with Pin being "true" or "false".
+type Endpoint struct {
+	Part     string `json:"part,omitempty"`
+	Pin      string `json:"pin"`
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	Constant bool   `json:"constant,omitempty"`
+}
+
+// Net is a wire carrying consecutive bits from a source to a target. Name is
+// the internal signal of the chip the bits belong to, if any.
+type Net struct {
+	Name   string   `json:"name,omitempty"`
+	Width  int      `json:"width"`
+	Source Endpoint `json:"source"`
+	Target Endpoint `json:"target"`
+}
+
+// source is where a bit comes from within a chip
+type source struct {
+	part  string
+	pin   string
+	index int
+}
+
+// Build describes the graph of the chip, expanding the parts that are custom
+// chips depth levels deep. A depth of 0 shows the parts of the chip only.
+func Build(chipName string, g *graphbuilder.Graph, depth int) *Schematic {
+	s := &Schematic{
+		Chip:    chipName,
+		Inputs:  ports(g.InputPins),
+		Outputs: ports(g.OutputPins),
+		Parts:   []Part{},
+		Nets:    []Net{},
+	}
+
+	sources := map[*graphbuilder.Bit]source{}
+	for name, pin := range g.InputPins {
+		for i, bitRef := range pin.Bits {
+			sources[bitRef.Bit] = source{pin: name, index: i}
+		}
+	}
+
+	nodes := slices.Clone(g.Nodes)
+	slices.SortStableFunc(nodes, func(a, b *graphbuilder.Node) int {
+		return cmp.Or(cmp.Compare(a.Loc.Line, b.Loc.Line), cmp.Compare(a.Loc.Column, b.Loc.Column))
+	})
+
+	for i, node := range nodes {
+		part := Part{
+			ID:      fmt.Sprintf("p%d", i),
+			Chip:    node.ChipName,
+			Builtin: node.SubGraph == nil,
+			Line:    node.Loc.Line,
+			Column:  node.Loc.Column,
+			Inputs:  ports(node.InputPins),
+			Outputs: ports(node.OutputPins),
+		}
+		if node.SubGraph != nil && depth > 0 {
+			part.Schematic = Build(node.ChipName, node.SubGraph, depth-1)
+		}
+		s.Parts = append(s.Parts, part)
+
+		for name, pin := range node.OutputPins {
+			for j, bitRef := range pin.Bits {
+				sources[bitRef.Bit] = source{part: part.ID, pin: name, index: j}
+			}
+		}
+	}
+
+	names := map[*graphbuilder.Bit]string{}
+	for name, pin := range g.InternalPins {
+		for _, bitRef := range pin.Bits {
+			names[bitRef.Bit] = name
+		}
+	}
+
+	for i, node := range nodes {
+		for _, name := range slices.Sorted(maps.Keys(node.InputPins)) {
+			s.Nets = append(s.Nets, nets(s.Parts[i].ID, name, node.InputPins[name].Bits, sources, names)...)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(g.OutputPins)) {
+		s.Nets = append(s.Nets, nets("", name, g.OutputPins[name].Bits, sources, names)...)
+	}
+
+	return s
+}
+
+// nets groups the bits of a target pin into nets of consecutive bits coming
+// from the same pin. Bits that are not driven by anything are constants.
+func nets(
+	part string,
+	pin string,
+	bits []*graphbuilder.BitRef,
+	sources map[*graphbuilder.Bit]source,
+	names map[*graphbuilder.Bit]string,
+) []Net {
+	var result []Net
+	for i, bitRef := range bits {
+		src, driven := sources[bitRef.Bit]
+		if !driven {
+			src = source{pin: fmt.Sprint(bitRef.Bit.Value)}
+		}
+		name := names[bitRef.Bit]
+
+		if len(result) > 0 {
+			last := &result[len(result)-1]
+			continues := last.Source.Part == src.part && last.Source.Pin == src.pin &&
+				last.Source.Constant == !driven && last.Name == name &&
+				(!driven || last.Source.To+1 == src.index)
+			if continues {
+				last.Width++
+				last.Target.To = i
+				if driven {
+					last.Source.To = src.index
+				}
+				continue
+			}
+		}
+
+		result = append(result, Net{
+			Name:   name,
+			Width:  1,
+			Source: Endpoint{Part: src.part, Pin: src.pin, From: src.index, To: src.index, Constant: !driven},
+			Target: Endpoint{Part: part, Pin: pin, From: i, To: i},
+		})
+	}
+	return result
+}
+
+func ports(pins map[string]*graphbuilder.Pin) []Port {
+	result := []Port{}
+	for _, name := range slices.Sorted(maps.Keys(pins)) {
+		result = append(result, Port{Name: name, Width: len(pins[name].Bits)})
+	}
+	return result
+}
@@ -0,0 +1,216 @@
+package schematic
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/resolver"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var splitChip = `CHIP Split {
+	IN a[16], b;
+	OUT out[8], c;
+
+	PARTS:
+	Or8Way(in = a[8..15], out = o);
+	And(a = o, b = true, out = c, out = x);
+	Mux16(a = a, b[0..3] = a[4..7], b[4] = x, sel = b, out[0..7] = out);
+}`
+
+func TestBuild(t *testing.T) {
+	hdls := map[string]string{"Split": splitChip}
+	s := Build("Split", mustBuildGraph(t, hdls, "Split"), 0)
+
+	assert.Equal(t, "Split", s.Chip)
+	assert.Equal(t, []Port{{Name: "a", Width: 16}, {Name: "b", Width: 1}}, s.Inputs)
+	assert.Equal(t, []Port{{Name: "c", Width: 1}, {Name: "out", Width: 8}}, s.Outputs)
+	assert.Equal(t, []Part{
+		{
+			ID: "p0", Chip: "Or8Way", Builtin: true, Line: 6, Column: 5,
+			Inputs:  []Port{{Name: "in", Width: 8}},
+			Outputs: []Port{{Name: "out", Width: 1}},
+		},
+		{
+			ID: "p1", Chip: "And", Builtin: true, Line: 7, Column: 5,
+			Inputs:  []Port{{Name: "a", Width: 1}, {Name: "b", Width: 1}},
+			Outputs: []Port{{Name: "out", Width: 1}},
+		},
+		{
+			ID: "p2", Chip: "Mux16", Builtin: true, Line: 8, Column: 5,
+			Inputs:  []Port{{Name: "a", Width: 16}, {Name: "b", Width: 16}, {Name: "sel", Width: 1}},
+			Outputs: []Port{{Name: "out", Width: 16}},
+		},
+	}, s.Parts)
+	assert.Equal(t, []Net{
+		{
+			Width:  8,
+			Source: Endpoint{Pin: "a", From: 8, To: 15},
+			Target: Endpoint{Part: "p0", Pin: "in", From: 0, To: 7},
+		},
+		{
+			Name:   "o",
+			Width:  1,
+			Source: Endpoint{Part: "p0", Pin: "out"},
+			Target: Endpoint{Part: "p1", Pin: "a"},
+		},
+		{
+			Width:  1,
+			Source: Endpoint{Pin: "true", Constant: true},
+			Target: Endpoint{Part: "p1", Pin: "b"},
+		},
+		{
+			Width:  16,
+			Source: Endpoint{Pin: "a", From: 0, To: 15},
+			Target: Endpoint{Part: "p2", Pin: "a", From: 0, To: 15},
+		},
+		{
+			Width:  4,
+			Source: Endpoint{Pin: "a", From: 4, To: 7},
+			Target: Endpoint{Part: "p2", Pin: "b", From: 0, To: 3},
+		},
+		{
+			Name:   "x",
+			Width:  1,
+			Source: Endpoint{Part: "p1", Pin: "out"},
+			Target: Endpoint{Part: "p2", Pin: "b", From: 4, To: 4},
+		},
+		{
+			Width:  11,
+			Source: Endpoint{Pin: "false", Constant: true},
+			Target: Endpoint{Part: "p2", Pin: "b", From: 5, To: 15},
+		},
+		{
+			Width:  1,
+			Source: Endpoint{Pin: "b"},
+			Target: Endpoint{Part: "p2", Pin: "sel"},
+		},
+		{
+			Name:   "x",
+			Width:  1,
+			Source: Endpoint{Part: "p1", Pin: "out"},
+			Target: Endpoint{Pin: "c"},
+		},
+		{
+			Width:  8,
+			Source: Endpoint{Part: "p2", Pin: "out", From: 0, To: 7},
+			Target: Endpoint{Pin: "out", From: 0, To: 7},
+		},
+	}, s.Nets)
+}
+
+func TestBuildDepth(t *testing.T) {
+	hdls := maps.Clone(testutils.ChipImplementations)
+	g := mustBuildGraph(t, hdls, "MuxChip")
+
+	s := Build("MuxChip", g, 0)
+	require.Len(t, s.Parts, 4)
+	for _, part := range s.Parts {
+		assert.False(t, part.Builtin)
+		assert.Nil(t, part.Schematic)
+	}
+
+	s = Build("MuxChip", g, 1)
+	and := s.Parts[1].Schematic
+	require.NotNil(t, and)
+	assert.Equal(t, "AndChip", and.Chip)
+	assert.Equal(t, []string{"Nand", "NotChip"}, []string{and.Parts[0].Chip, and.Parts[1].Chip})
+	assert.Nil(t, and.Parts[1].Schematic)
+
+	s = Build("MuxChip", g, 2)
+	assert.NotNil(t, s.Parts[1].Schematic.Parts[1].Schematic)
+}
+
+func TestDOT(t *testing.T) {
+	hdls := map[string]string{"Split": splitChip}
+	s := Build("Split", mustBuildGraph(t, hdls, "Split"), 0)
+
+	assert.Equal(t, `digraph "Split" {
+	rankdir=LR;
+	node [shape=record];
+	"in_a" [label="a[16]", shape=rarrow];
+	"in_b" [label="b", shape=rarrow];
+	"out_c" [label="c", shape=rarrow];
+	"out_out" [label="out[8]", shape=rarrow];
+	"const_false" [label="false", shape=plaintext];
+	"const_true" [label="true", shape=plaintext];
+	"p0" [label="{{<in_in> in[8]}|Or8Way|{<out_out> out}}"];
+	"p1" [label="{{<in_a> a|<in_b> b}|And|{<out_out> out}}"];
+	"p2" [label="{{<in_a> a[16]|<in_b> b[16]|<in_sel> sel}|Mux16|{<out_out> out[16]}}"];
+	"in_a" -> "p0":"in_in" [style=bold, label="/8", taillabel="[8..15]"];
+	"p0":"out_out" -> "p1":"in_a" [label="o"];
+	"const_true" -> "p1":"in_b";
+	"in_a" -> "p2":"in_a" [style=bold, label="/16"];
+	"in_a" -> "p2":"in_b" [style=bold, label="/4", taillabel="[4..7]", headlabel="[0..3]"];
+	"p1":"out_out" -> "p2":"in_b" [label="x", headlabel="[4]"];
+	"const_false" -> "p2":"in_b" [style=bold, label="/11", headlabel="[5..15]"];
+	"in_b" -> "p2":"in_sel";
+	"p1":"out_out" -> "out_c" [label="x"];
+	"p2":"out_out" -> "out_out" [style=bold, label="/8", taillabel="[0..7]"];
+}
+`, s.DOT())
+}
+
+func TestDOTExpanded(t *testing.T) {
+	hdls := maps.Clone(testutils.ChipImplementations)
+	s := Build("AndChip", mustBuildGraph(t, hdls, "AndChip"), 1)
+
+	assert.Equal(t, `digraph "AndChip" {
+	rankdir=LR;
+	node [shape=record];
+	"in_a" [label="a", shape=rarrow];
+	"in_b" [label="b", shape=rarrow];
+	"out_out" [label="out", shape=rarrow];
+	"p0" [label="{{<in_a> a|<in_b> b}|Nand|{<out_out> out}}"];
+	subgraph "cluster_p1" {
+		label="NotChip";
+		"p1_in_in" [label="in", shape=rarrow];
+		"p1_out_out" [label="out", shape=rarrow];
+		"p1_p0" [label="{{<in_a> a|<in_b> b}|Nand|{<out_out> out}}"];
+		"p1_in_in" -> "p1_p0":"in_a";
+		"p1_in_in" -> "p1_p0":"in_b";
+		"p1_p0":"out_out" -> "p1_out_out";
+	}
+	"in_a" -> "p0":"in_a";
+	"in_b" -> "p0":"in_b";
+	"p0":"out_out" -> "p1_in_in" [label="aNandB"];
+	"p1_out_out" -> "out_out";
+}
+`, s.DOT())
+}
+
+func mustBuildGraph(t *testing.T, hdls map[string]string, chipName string) *graphbuilder.Graph {
+	t.Helper()
+
+	l := lexer.New(hdls[chipName])
+	ts, err := l.Tokenize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := parser.New(ts)
+	chd, err := p.ParseChipDefinition()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := chips.NewDefaultRegistry()
+	r := resolver.New(chd, chipName, hdls, registry)
+	rchd, rchds, err := r.Resolve([]string{}, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rchds[rchd.Name] = rchd
+
+	g, err := graphbuilder.New(rchds, registry).BuildGraph(rchd.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
@@ -0,0 +1,110 @@
+package semantic
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/resolver"
+)
+
+type Outline struct {
+	ChipName        string
+	Inputs          []IO
+	Outputs         []IO
+	Parts           []Part
+	InternalSignals []InternalSignal // empty if the chip could not be resolved
+}
+
+type IO struct {
+	Name  string
+	Width int
+	Range Range
+}
+
+type Part struct {
+	Name      string
+	IsBuiltin bool
+	Range     Range
+}
+
+type InternalSignal struct {
+	Name  string
+	Width int
+}
+
+func buildOutline(hdl string, hdls map[string]string, registry *chips.Registry) *Outline {
+	l := lexer.New(hdl)
+	ts, err := l.Tokenize()
+	if err != nil {
+		return nil
+	}
+
+	p := parser.New(ts)
+	chd, err := p.ParseChipDefinition()
+	if err != nil {
+		return nil
+	}
+
+	outline := &Outline{
+		ChipName: chd.ChipName.Name,
+		Inputs:   []IO{},
+		Outputs:  []IO{},
+		Parts:    []Part{},
+	}
+
+	for _, input := range chd.Inputs {
+		outline.Inputs = append(outline.Inputs, IO{
+			Name:  input.Name,
+			Width: input.Width,
+			Range: nameRange(input.Name, input.Loc),
+		})
+	}
+
+	for _, output := range chd.Outputs {
+		outline.Outputs = append(outline.Outputs, IO{
+			Name:  output.Name,
+			Width: output.Width,
+			Range: nameRange(output.Name, output.Loc),
+		})
+	}
+
+	for _, part := range chd.Parts {
+		outline.Parts = append(outline.Parts, Part{
+			Name:      part.Name,
+			IsBuiltin: registry.Has(part.Name),
+			Range:     nameRange(part.Name, part.Loc),
+		})
+	}
+
+	outline.InternalSignals = resolveInternalSignals(chd, hdls, registry)
+	return outline
+}
+
+func resolveInternalSignals(chd *parser.ParsedChipDefinition, hdls map[string]string, registry *chips.Registry) []InternalSignal {
+	signals := []InternalSignal{}
+
+	r := resolver.New(chd, chd.ChipName.Name, hdls, registry)
+	rchd, _, err := r.Resolve([]string{}, []string{})
+	if err != nil {
+		return signals
+	}
+
+	for name, signal := range rchd.InternalSignals {
+		signals = append(signals, InternalSignal{Name: name, Width: signal.Width})
+	}
+	slices.SortFunc(signals, func(a, b InternalSignal) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return signals
+}
+
+func nameRange(name string, loc parser.Loc) Range {
+	return Range{
+		Start: Position{Line: loc.Line, Column: loc.Column},
+		End:   Position{Line: loc.Line, Column: loc.Column + len(name)},
+	}
+}
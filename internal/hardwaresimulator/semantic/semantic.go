@@ -0,0 +1,180 @@
+package semantic
+
+import (
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/token"
+)
+
+type TokenType string
+
+const (
+	KEYWORD         TokenType = "keyword"
+	CHIP_NAME       TokenType = "chipName"
+	BUILTIN_CHIP    TokenType = "builtinChip"
+	PIN             TokenType = "pin"
+	INTERNAL_SIGNAL TokenType = "internalSignal"
+	CONSTANT        TokenType = "constant"
+	COMMENT         TokenType = "comment"
+)
+
+type Token struct {
+	TokenType TokenType
+	Literal   string
+	Range     Range
+}
+
+type Range struct {
+	Start Position
+	End   Position // exclusive
+}
+
+type Position struct {
+	Line   int
+	Column int
+}
+
+type Document struct {
+	Tokens  []Token
+	Outline *Outline // nil if the HDL could not be parsed
+}
+
+// Analyze classifies the tokens of the given HDL the same way the simulator
+// sees them. It never fails: illegal tokens are skipped, so half-written code
+// can still be highlighted. The hdls of the project and the registry are used
+// to tell custom and built-in chips apart from unknown identifiers.
+func Analyze(hdl string, hdls map[string]string, registry *chips.Registry) *Document {
+	return &Document{
+		Tokens:  classifyTokens(hdl, hdls, registry),
+		Outline: buildOutline(hdl, hdls, registry),
+	}
+}
+
+type section int
+
+const (
+	sectionHeader section = iota
+	sectionChipName
+	sectionIO
+	sectionParts
+	sectionBuiltin
+	sectionClocked
+)
+
+func classifyTokens(hdl string, hdls map[string]string, registry *chips.Registry) []Token {
+	l := lexer.New(hdl)
+	var tokens []Token
+
+	currentSection := sectionHeader
+	ioNames := make(map[string]bool)
+	insidePart := false
+	expectingSignal := false
+
+	for {
+		tok := l.NextToken()
+		if tok.TokenType == token.EOF {
+			break
+		}
+		if tok.TokenType == token.ILLEGAL {
+			if tok.Literal == "EOF" {
+				// unterminated block comment, nothing follows
+				break
+			}
+			continue
+		}
+
+		var tokenType TokenType
+		switch tok.TokenType {
+		case token.LINE_COMMENT, token.BLOCK_COMMENT:
+			tokenType = COMMENT
+		case token.CHIP:
+			tokenType = KEYWORD
+			currentSection = sectionChipName
+		case token.IN, token.OUT:
+			tokenType = KEYWORD
+			if currentSection != sectionParts {
+				currentSection = sectionIO
+			}
+		case token.PARTS:
+			tokenType = KEYWORD
+			currentSection = sectionParts
+		case token.BUILTIN:
+			tokenType = KEYWORD
+			currentSection = sectionBuiltin
+		case token.CLOCKED:
+			tokenType = KEYWORD
+			currentSection = sectionClocked
+		case token.TRUE, token.FALSE, token.NUMBER:
+			tokenType = CONSTANT
+		case token.LPAREN:
+			insidePart = true
+			expectingSignal = false
+		case token.RPAREN:
+			insidePart = false
+		case token.COMMA:
+			expectingSignal = false
+		case token.ASSIGN:
+			expectingSignal = true
+		case token.IDENTIFIER:
+			tokenType = classifyIdentifier(tok.Literal, currentSection, insidePart, expectingSignal, ioNames, hdls, registry)
+			if currentSection == sectionIO {
+				ioNames[tok.Literal] = true
+			}
+			if currentSection == sectionChipName {
+				currentSection = sectionHeader
+			}
+		}
+
+		if tokenType == "" {
+			continue // punctuation is not highlighted semantically
+		}
+
+		tokens = append(tokens, Token{
+			TokenType: tokenType,
+			Literal:   tok.Literal,
+			Range: Range{
+				Start: Position{Line: tok.Line, Column: tok.Column},
+				End:   Position{Line: tok.EndLine, Column: tok.EndColumn},
+			},
+		})
+	}
+
+	return tokens
+}
+
+func classifyIdentifier(
+	name string,
+	currentSection section,
+	insidePart, expectingSignal bool,
+	ioNames map[string]bool,
+	hdls map[string]string,
+	registry *chips.Registry,
+) TokenType {
+	switch currentSection {
+	case sectionChipName:
+		return CHIP_NAME
+	case sectionIO, sectionClocked:
+		return PIN
+	case sectionBuiltin:
+		if registry.Has(name) {
+			return BUILTIN_CHIP
+		}
+		return ""
+	case sectionParts:
+		if !insidePart {
+			if registry.Has(name) {
+				return BUILTIN_CHIP
+			}
+			if _, ok := hdls[name]; ok {
+				return CHIP_NAME
+			}
+			return "" // unknown chip, the resolver would reject it
+		}
+		if !expectingSignal || ioNames[name] {
+			return PIN
+		}
+		return INTERNAL_SIGNAL
+	default:
+		return ""
+	}
+}
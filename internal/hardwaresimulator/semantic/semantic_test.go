@@ -0,0 +1,179 @@
+package semantic
+
+import (
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeTokens(t *testing.T) {
+	type expectedToken struct {
+		TokenType TokenType
+		Literal   string
+		Start     Position
+		End       Position
+	}
+
+	tests := []struct {
+		name           string
+		input          string
+		hdls           map[string]string
+		expectedTokens []expectedToken
+	}{
+		{
+			name: "Chip with builtin and custom parts",
+			input: `// comment
+CHIP AndChip {
+    IN a, b[2];
+    OUT out;
+
+    PARTS:
+    Nand(a = a, b = b[0], out = nandOut);
+    NotChip(in = nandOut, out = out);
+    Not(in = true, out[0..0] = x);
+}`,
+			hdls: map[string]string{"NotChip": ""},
+			expectedTokens: []expectedToken{
+				{COMMENT, "", Position{1, 1}, Position{1, 11}},
+				{KEYWORD, "CHIP", Position{2, 1}, Position{2, 5}},
+				{CHIP_NAME, "AndChip", Position{2, 6}, Position{2, 13}},
+				{KEYWORD, "IN", Position{3, 5}, Position{3, 7}},
+				{PIN, "a", Position{3, 8}, Position{3, 9}},
+				{PIN, "b", Position{3, 11}, Position{3, 12}},
+				{CONSTANT, "2", Position{3, 13}, Position{3, 14}},
+				{KEYWORD, "OUT", Position{4, 5}, Position{4, 8}},
+				{PIN, "out", Position{4, 9}, Position{4, 12}},
+				{KEYWORD, "PARTS", Position{6, 5}, Position{6, 10}},
+				{BUILTIN_CHIP, "Nand", Position{7, 5}, Position{7, 9}},
+				{PIN, "a", Position{7, 10}, Position{7, 11}},
+				{PIN, "a", Position{7, 14}, Position{7, 15}},
+				{PIN, "b", Position{7, 17}, Position{7, 18}},
+				{PIN, "b", Position{7, 21}, Position{7, 22}},
+				{CONSTANT, "0", Position{7, 23}, Position{7, 24}},
+				{PIN, "out", Position{7, 27}, Position{7, 30}},
+				{INTERNAL_SIGNAL, "nandOut", Position{7, 33}, Position{7, 40}},
+				{CHIP_NAME, "NotChip", Position{8, 5}, Position{8, 12}},
+				{PIN, "in", Position{8, 13}, Position{8, 15}},
+				{INTERNAL_SIGNAL, "nandOut", Position{8, 18}, Position{8, 25}},
+				{PIN, "out", Position{8, 27}, Position{8, 30}},
+				{PIN, "out", Position{8, 33}, Position{8, 36}},
+				{BUILTIN_CHIP, "Not", Position{9, 5}, Position{9, 8}},
+				{PIN, "in", Position{9, 9}, Position{9, 11}},
+				{CONSTANT, "true", Position{9, 14}, Position{9, 18}},
+				{PIN, "out", Position{9, 20}, Position{9, 23}},
+				{CONSTANT, "0", Position{9, 24}, Position{9, 25}},
+				{CONSTANT, "0", Position{9, 27}, Position{9, 28}},
+				{INTERNAL_SIGNAL, "x", Position{9, 32}, Position{9, 33}},
+			},
+		},
+		{
+			name: "Multiline block comment and unknown chip",
+			input: `/* first
+   second */ CHIP X { IN a; OUT b; PARTS: Foo(a = a, b = b); }`,
+			hdls: map[string]string{},
+			expectedTokens: []expectedToken{
+				{COMMENT, "", Position{1, 1}, Position{2, 13}},
+				{KEYWORD, "CHIP", Position{2, 14}, Position{2, 18}},
+				{CHIP_NAME, "X", Position{2, 19}, Position{2, 20}},
+				{KEYWORD, "IN", Position{2, 23}, Position{2, 25}},
+				{PIN, "a", Position{2, 26}, Position{2, 27}},
+				{KEYWORD, "OUT", Position{2, 29}, Position{2, 32}},
+				{PIN, "b", Position{2, 33}, Position{2, 34}},
+				{KEYWORD, "PARTS", Position{2, 36}, Position{2, 41}},
+				{PIN, "a", Position{2, 47}, Position{2, 48}},
+				{PIN, "a", Position{2, 51}, Position{2, 52}},
+				{PIN, "b", Position{2, 54}, Position{2, 55}},
+				{PIN, "b", Position{2, 58}, Position{2, 59}},
+			},
+		},
+		{
+			name: "Built-in chip declaration",
+			input: `CHIP Bit {
+    IN in, load;
+    OUT out;
+    BUILTIN Bit;
+    CLOCKED in, load;
+}`,
+			hdls: map[string]string{},
+			expectedTokens: []expectedToken{
+				{KEYWORD, "CHIP", Position{1, 1}, Position{1, 5}},
+				{CHIP_NAME, "Bit", Position{1, 6}, Position{1, 9}},
+				{KEYWORD, "IN", Position{2, 5}, Position{2, 7}},
+				{PIN, "in", Position{2, 8}, Position{2, 10}},
+				{PIN, "load", Position{2, 12}, Position{2, 16}},
+				{KEYWORD, "OUT", Position{3, 5}, Position{3, 8}},
+				{PIN, "out", Position{3, 9}, Position{3, 12}},
+				{KEYWORD, "BUILTIN", Position{4, 5}, Position{4, 12}},
+				{BUILTIN_CHIP, "Bit", Position{4, 13}, Position{4, 16}},
+				{KEYWORD, "CLOCKED", Position{5, 5}, Position{5, 12}},
+				{PIN, "in", Position{5, 13}, Position{5, 15}},
+				{PIN, "load", Position{5, 17}, Position{5, 21}},
+			},
+		},
+		{
+			name:  "Illegal characters are skipped",
+			input: `CHIP A { IN a$; }`,
+			hdls:  map[string]string{},
+			expectedTokens: []expectedToken{
+				{KEYWORD, "CHIP", Position{1, 1}, Position{1, 5}},
+				{CHIP_NAME, "A", Position{1, 6}, Position{1, 7}},
+				{KEYWORD, "IN", Position{1, 10}, Position{1, 12}},
+				{PIN, "a", Position{1, 13}, Position{1, 14}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Analyze(tt.input, tt.hdls, chips.NewDefaultRegistry())
+			var actual []expectedToken
+			for _, tok := range doc.Tokens {
+				actual = append(actual, expectedToken{tok.TokenType, tok.Literal, tok.Range.Start, tok.Range.End})
+			}
+			assert.Equal(t, tt.expectedTokens, actual)
+		})
+	}
+}
+
+func TestAnalyzeOutline(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip": `CHIP NotChip { IN in; OUT out; PARTS: Nand(a = in, b = in, out = out); }`,
+	}
+
+	input := `CHIP AndChip {
+    IN a, b;
+    OUT out[1];
+
+    PARTS:
+    Nand(a = a, b = b, out = nandOut);
+    NotChip(in = nandOut, out = out);
+}`
+
+	doc := Analyze(input, hdls, chips.NewDefaultRegistry())
+	expected := &Outline{
+		ChipName: "AndChip",
+		Inputs: []IO{
+			{Name: "a", Width: 1, Range: Range{Position{2, 8}, Position{2, 9}}},
+			{Name: "b", Width: 1, Range: Range{Position{2, 11}, Position{2, 12}}},
+		},
+		Outputs: []IO{
+			{Name: "out", Width: 1, Range: Range{Position{3, 9}, Position{3, 12}}},
+		},
+		Parts: []Part{
+			{Name: "Nand", IsBuiltin: true, Range: Range{Position{6, 5}, Position{6, 9}}},
+			{Name: "NotChip", IsBuiltin: false, Range: Range{Position{7, 5}, Position{7, 12}}},
+		},
+		InternalSignals: []InternalSignal{
+			{Name: "nandOut", Width: 1},
+		},
+	}
+	assert.Equal(t, expected, doc.Outline)
+
+	doc = Analyze(`CHIP AndChip { IN a`, hdls, chips.NewDefaultRegistry())
+	assert.Nil(t, doc.Outline)
+
+	doc = Analyze(`CHIP AndChip { IN a; OUT out; PARTS: Missing(a = a, out = out); }`, hdls, chips.NewDefaultRegistry())
+	assert.NotNil(t, doc.Outline)
+	assert.Equal(t, []InternalSignal{}, doc.Outline.InternalSignals)
+}
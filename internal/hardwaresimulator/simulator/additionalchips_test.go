@@ -1,8 +1,12 @@
 package simulator
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/analysis"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
 	"github.com/stretchr/testify/assert"
 )
@@ -99,12 +103,61 @@ func TestAdditionalChipsSimulation(t *testing.T) {
 				assert.Equal(t, expectedInternalPins, internalPins)
 			},
 		},
+		{
+			name:                        "Built-in Bit Chip",
+			chipFileName:                "BuiltinBitChip",
+			hdls:                        testutils.ChipImplementations,
+			expectedInputsAfterProcess:  map[string]int{"in": 1, "load": 1},
+			expectedOutputsAfterProcess: map[string]int{"out": 1},
+			afterProcess: func(t *testing.T, hs *HardwareSimulator) {
+				outputs, _ := hs.Tick(map[string][]bool{"in": {true}, "load": {true}})
+				assert.Equal(t, map[string][]bool{"out": {false}}, outputs)
+
+				outputs, _ = hs.Tock(map[string][]bool{"in": {true}, "load": {true}})
+				assert.Equal(t, map[string][]bool{"out": {true}}, outputs)
+
+				outputs, _ = hs.Tick(map[string][]bool{"in": {false}, "load": {false}})
+				assert.Equal(t, map[string][]bool{"out": {true}}, outputs)
+
+				outputs, _ = hs.Tock(map[string][]bool{"in": {false}, "load": {false}})
+				assert.Equal(t, map[string][]bool{"out": {true}}, outputs)
+			},
+		},
+		{
+			name:                        "Chip with parts declared as BUILTIN",
+			chipFileName:                "BuiltinXorChip",
+			hdls:                        testutils.ChipImplementations,
+			expectedInputsAfterProcess:  map[string]int{"a": 1, "b": 1},
+			expectedOutputsAfterProcess: map[string]int{"out": 1},
+			afterProcess: func(t *testing.T, hs *HardwareSimulator) {
+				for _, a := range []bool{false, true} {
+					for _, b := range []bool{false, true} {
+						outputs, _ := hs.Evaluate(map[string][]bool{"a": {a}, "b": {b}})
+						assert.Equal(t, map[string][]bool{"out": {a != b}}, outputs)
+					}
+				}
+			},
+		},
+		{
+			name:                        "Chip with numeric constants",
+			chipFileName:                "ConstantMuxChip",
+			hdls:                        testutils.ChipImplementations,
+			expectedInputsAfterProcess:  map[string]int{"sel": 1},
+			expectedOutputsAfterProcess: map[string]int{"out": 16},
+			afterProcess: func(t *testing.T, hs *HardwareSimulator) {
+				outputs, _ := hs.Evaluate(map[string][]bool{"sel": {false}})
+				assert.Equal(t, map[string][]bool{"out": testutils.StringToBoolArray("0111111111111111")}, outputs)
+
+				outputs, _ = hs.Evaluate(map[string][]bool{"sel": {true}})
+				assert.Equal(t, map[string][]bool{"out": testutils.StringToBoolArray("0000001100000101")}, outputs)
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			hs := New()
+			hs := New(chips.NewDefaultRegistry())
 			hs.SetChipHDLs(tt.hdls)
 			inputs, outputs, _, err := hs.Process(tt.chipFileName)
 			if err != nil {
@@ -129,3 +182,300 @@ func TestAdditionalChipsSimulation(t *testing.T) {
 		})
 	}
 }
+
+func TestCourseSpecificBuiltinChips(t *testing.T) {
+	registry := chips.NewDefaultRegistry()
+	assert.NoError(t, registry.Register(chips.NewRegister("ARegister", "Address register", 16)))
+	assert.NoError(t, registry.Register(chips.NewRegister("DRegister", "Data register", 16)))
+
+	hdls := map[string]string{
+		"Registers": `CHIP Registers {
+			IN in[16], loadA, loadD;
+			OUT a[16], d[16];
+
+			PARTS:
+			ARegister(in = in, load = loadA, out = a);
+			DRegister(in = in, load = loadD, out = d);
+		}`,
+	}
+
+	hs := New(registry)
+	hs.SetChipHDLs(hdls)
+	_, _, _, err := hs.Process("Registers")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	value := testutils.StringToBoolArray("0000000000101010")
+	zero := testutils.RepeatBool(false, 16)
+	inputs := map[string][]bool{"in": value, "loadA": {true}, "loadD": {false}}
+	hs.Tick(inputs)
+	outputs, _ := hs.Tock(inputs)
+	assert.Equal(t, map[string][]bool{"a": value, "d": zero}, outputs)
+
+	// the default registry does not know the course-specific chips
+	hs = New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+	_, _, _, err = hs.Process("Registers")
+	assert.EqualError(t, err, "Resolution error: Used chip 'ARegister' is neither a built-in chip nor a custom chip")
+}
+
+// TestBuiltinRegisterFeedback checks that the outputs of the registers, also
+// the course-specific ones, can be fed back to their inputs, as in a CPU.
+func TestBuiltinRegisterFeedback(t *testing.T) {
+	registry := chips.NewDefaultRegistry()
+	assert.NoError(t, registry.Register(chips.NewRegister("ARegister", "Address register", 16)))
+
+	hdls := map[string]string{
+		"Counter": `CHIP Counter {
+			IN reset;
+			OUT out[16], pc[16], a[16];
+
+			PARTS:
+			Inc16(in = reg, out = next);
+			Mux16(a = next, b = false, sel = reset, out = in);
+			Register(in = in, load = true, out = reg, out = out);
+			PC(in = pcOut, load = false, inc = true, reset = reset, out = pcOut, out = pc);
+			Inc16(in = aOut, out = aNext);
+			ARegister(in = aNext, load = true, out = aOut, out = a);
+		}`,
+	}
+
+	hs := New(registry)
+	hs.SetChipHDLs(hdls)
+	_, _, _, err := hs.Process("Counter")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	inputs := map[string][]bool{"reset": {false}}
+	for range 2 {
+		hs.Tick(inputs)
+		hs.Tock(inputs)
+	}
+	two := testutils.StringToBoolArray("0000000000000010")
+	outputs, _ := hs.Evaluate(inputs)
+	assert.Equal(t, map[string][]bool{"out": two, "pc": two, "a": two}, outputs)
+}
+
+func TestFourValuedSimulation(t *testing.T) {
+	hdls := map[string]string{
+		"Unknowns": `CHIP Unknowns {
+			IN a, in[16], load;
+			OUT andOut, xorOut, nandKnown, dffOut, regOut[16], muxOut[16];
+
+			PARTS:
+			And(a = a, out = andOut);
+			Xor(a = a, out = xorOut);
+			Nand(a = false, out = nandKnown);
+			DFF(in = a, out = dffOut);
+			Register(in = in, load = load, out = regOut);
+			Mux16(a = in, b = in, out = muxOut);
+		}`,
+	}
+	value := testutils.StringToBoolArray("0000000000101010")
+	allBits := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetFourValuedLogic(true)
+	hs.SetChipHDLs(hdls)
+	_, _, _, err := hs.Process("Unknowns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	inputs := map[string][]bool{"a": {true}, "in": value, "load": {false}}
+	outputs, _ := hs.Evaluate(inputs)
+	assert.Equal(t, []bool{true}, outputs["nandKnown"], "a known 0 decides Nand")
+	assert.Equal(t, value, outputs["muxOut"], "equal inputs decide Mux16 with unknown sel")
+	assert.Equal(t, map[string][]int{
+		"andOut": {0},
+		"xorOut": {0},
+		"dffOut": {0},
+		"regOut": allBits,
+	}, hs.UnknownOutputs())
+
+	// the DFF always stores, the register is not loaded yet
+	hs.Tick(inputs)
+	outputs, _ = hs.Tock(inputs)
+	assert.Equal(t, []bool{true}, outputs["dffOut"])
+	assert.Equal(t, map[string][]int{
+		"andOut": {0},
+		"xorOut": {0},
+		"regOut": allBits,
+	}, hs.UnknownOutputs())
+
+	inputs["load"] = []bool{true}
+	hs.Tick(inputs)
+	outputs, _ = hs.Tock(inputs)
+	assert.Equal(t, value, outputs["regOut"])
+	assert.Equal(t, map[string][]int{
+		"andOut": {0},
+		"xorOut": {0},
+	}, hs.UnknownOutputs())
+
+	// in two-valued mode the same chip reads unconnected pins as 0
+	hs = New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+	_, _, _, err = hs.Process("Unknowns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outputs, _ = hs.Evaluate(map[string][]bool{"a": {true}, "in": value, "load": {false}})
+	assert.Equal(t, []bool{false}, outputs["andOut"])
+	assert.Equal(t, []bool{true}, outputs["xorOut"])
+	assert.Equal(t, map[string][]int{}, hs.UnknownOutputs())
+}
+
+// latch is a sequential chip that does not implement chips.UnknownEvaluator.
+type latch struct{}
+
+func (latch) Name() string {
+	return "Latch"
+}
+
+func (latch) Signature() chips.Chip {
+	return chips.Chip{
+		Inputs:  map[string]chips.IO{"in": {Width: 1}},
+		Outputs: map[string]chips.IO{"out": {Width: 1}},
+	}
+}
+
+func (latch) IsSequentialBit(output string, bit int) bool {
+	return true
+}
+
+func (latch) Init() chips.State {
+	return chips.State{"out": {false}}
+}
+
+func (latch) Evaluate(pins chips.Pins, state chips.State) {}
+
+func (latch) Commit(pins chips.Pins, state chips.State) {
+	state["out"][0] = pins.Input("in").Get(0)
+}
+
+func (latch) Apply(pins chips.Pins, state chips.State) {
+	pins.Output("out").Set(0, state["out"][0])
+}
+
+func TestFourValuedSimulationOfOtherChips(t *testing.T) {
+	registry := chips.NewDefaultRegistry()
+	assert.NoError(t, registry.Register(latch{}))
+
+	hs := New(registry)
+	hs.SetFourValuedLogic(true)
+	hs.SetChipHDLs(map[string]string{
+		"Latches": `CHIP Latches {
+			IN in;
+			OUT latchOut, dffOut;
+
+			PARTS:
+			Latch(in = in, out = latchOut);
+			DFF(in = in, out = dffOut);
+		}`,
+	})
+	_, _, _, err := hs.Process("Latches")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	inputs := map[string][]bool{"in": {true}}
+	hs.Evaluate(inputs)
+	assert.Equal(t, map[string][]int{"latchOut": {0}, "dffOut": {0}}, hs.UnknownOutputs())
+
+	// the state of the latch is not known to be overwritten by the commit
+	hs.Tick(inputs)
+	outputs, _ := hs.Tock(inputs)
+	assert.Equal(t, []bool{true}, outputs["dffOut"])
+	assert.Equal(t, map[string][]int{"latchOut": {0}}, hs.UnknownOutputs())
+}
+
+func TestAnalyzeTiming(t *testing.T) {
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(testutils.ChipImplementations)
+
+	timing, err := hs.AnalyzeTiming("Add16Chip")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the carry ripples through every full adder, starting at the sum of the second bit
+	assert.Equal(t, 64, timing.Depth)
+	assert.Equal(t, map[string]int{"out": 64}, timing.Depths["a"])
+	assert.Equal(t, &analysis.PinBit{Name: "a", Bit: 1}, timing.From)
+	assert.Equal(t, &analysis.PinBit{Name: "out", Bit: 15}, timing.To)
+	assert.Len(t, timing.CriticalPath, 64)
+	assert.Equal(t, analysis.PartRef{Chip: "FullAdderChip", Line: 7, Column: 9}, timing.CriticalPath[0].Parts[0])
+	assert.Equal(t, analysis.PartRef{Chip: "FullAdderChip", Line: 21, Column: 9}, timing.CriticalPath[63].Parts[0])
+
+	_, err = hs.AnalyzeTiming("Missing")
+	assert.Error(t, err)
+}
+
+func TestSchematic(t *testing.T) {
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(testutils.ChipImplementations)
+
+	s, err := hs.Schematic("XorChip", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Equal(t, "XorChip", s.Chip)
+	assert.Equal(t, []string{"OrChip", "Nand", "AndChip"}, []string{s.Parts[0].Chip, s.Parts[1].Chip, s.Parts[2].Chip})
+	assert.NotNil(t, s.Parts[0].Schematic)
+	assert.Nil(t, s.Parts[1].Schematic)
+	assert.Contains(t, s.DOT(), `subgraph "cluster_p0"`)
+
+	_, err = hs.Schematic("Missing", 0)
+	assert.Error(t, err)
+}
+
+func TestVerilog(t *testing.T) {
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(testutils.ChipImplementations)
+
+	modules, err := hs.Verilog("MuxChip", "BitChip")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.ElementsMatch(t, []string{"MuxChip", "NotChip", "AndChip", "OrChip", "BitChip", "Nand", "DFF"}, slices.Collect(maps.Keys(modules)))
+	assert.Contains(t, modules["BitChip"], "    input clk,\n")
+	assert.NotContains(t, modules["MuxChip"], "clk")
+
+	_, err = hs.Verilog("MuxChip", "Missing")
+	assert.Error(t, err)
+}
+
+func TestCoverage(t *testing.T) {
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(testutils.ChipImplementations)
+	if _, _, _, err := hs.Process("BitChip"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cycle := func(in, load bool) {
+		hs.Tick(map[string][]bool{"in": {in}, "load": {load}})
+		hs.Tock(map[string][]bool{"in": {in}, "load": {load}})
+	}
+
+	cycle(false, false)
+	cycle(true, true)
+	report := hs.Coverage()
+	assert.Equal(t, 4, report.Samples)
+	assert.Equal(t, []int{0, 1}, report.Parts[0].Selected, "expected the MuxChip to have selected both inputs")
+	assert.False(t, report.Parts[0].Covered, "expected the MuxChip to be partially covered")
+	assert.Equal(t, analysis.LineCoverage{Line: 6, Status: analysis.LinePartial}, report.Lines[0])
+
+	cycle(false, true)
+	cycle(false, false)
+	report = hs.Coverage()
+	assert.True(t, report.Parts[0].Covered)
+	assert.True(t, report.Parts[1].Covered)
+	assert.Equal(t, report.Bits, report.ToggledBits)
+
+	hs.ResetCoverage()
+	assert.Equal(t, 0, hs.Coverage().Samples)
+}
@@ -0,0 +1,47 @@
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/evaluator"
+)
+
+// EvaluateBatch evaluates the chip for each input vector independently, in
+// two-valued logic from the initial state of its clocked parts, spreading the
+// vectors over all CPUs.
+// The outputs are returned in the order of the vectors. Inputs missing from a
+// vector are 0. It does not affect the chip loaded with Process.
+func (hs *HardwareSimulator) EvaluateBatch(chipName string, vectors []map[string][]bool) ([]map[string][]bool, error) {
+	_, rchd, g, err := hs.buildGraph(chipName)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors, err = completeInputs(rchd.Inputs, vectors, "vector")
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluator.EvaluateBatch(g, vectors, 0), nil
+}
+
+// completeInputs returns a copy of the vectors with the missing inputs set to
+// 0, checking the widths of the given ones. kind names a vector in errors.
+func completeInputs(inputs map[string]chips.IO, vectors []map[string][]bool, kind string) ([]map[string][]bool, error) {
+	completed := make([]map[string][]bool, len(vectors))
+	for i, vector := range vectors {
+		completed[i] = make(map[string][]bool, len(inputs))
+		for name, input := range inputs {
+			bits, ok := vector[name]
+			if !ok {
+				bits = make([]bool, input.Width)
+			}
+			if len(bits) != input.Width {
+				return nil, fmt.Errorf("Input '%s' of %s %d must have %d bits, got %d", name, kind, i+1, input.Width, len(bits))
+			}
+			completed[i][name] = bits
+		}
+	}
+	return completed, nil
+}
@@ -0,0 +1,56 @@
+package simulator
+
+import (
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateBatch(t *testing.T) {
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(testutils.ChipImplementations)
+
+	t.Run("Combinational chip", func(t *testing.T) {
+		var vectors []map[string][]bool
+		for i := range 1000 {
+			vectors = append(vectors, map[string][]bool{
+				"a":   {i%2 == 1},
+				"b":   {i%3 == 1},
+				"sel": {i%5 < 2},
+			})
+		}
+
+		results, err := hs.EvaluateBatch("MuxChip", vectors)
+		require.NoError(t, err)
+		require.Len(t, results, len(vectors))
+		for i, result := range results {
+			expected := vectors[i]["a"]
+			if vectors[i]["sel"][0] {
+				expected = vectors[i]["b"]
+			}
+			assert.Equal(t, map[string][]bool{"out": expected}, result, "vector %d", i+1)
+		}
+	})
+
+	t.Run("Sequential chip keeps its initial state", func(t *testing.T) {
+		results, err := hs.EvaluateBatch("BitChip", []map[string][]bool{
+			{"in": {true}, "load": {true}},
+			{"in": {true}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []map[string][]bool{{"out": {false}}, {"out": {false}}}, results)
+	})
+
+	t.Run("Wrong input width", func(t *testing.T) {
+		_, err := hs.EvaluateBatch("MuxChip", []map[string][]bool{{"a": {true}}, {"sel": {true, true}}})
+		assert.EqualError(t, err, "Input 'sel' of vector 2 must have 1 bits, got 2")
+	})
+
+	t.Run("Missing chip", func(t *testing.T) {
+		_, err := hs.EvaluateBatch("Missing", nil)
+		assert.Error(t, err)
+	})
+}
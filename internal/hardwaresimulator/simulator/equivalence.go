@@ -0,0 +1,288 @@
+package simulator
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/errors"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/evaluator"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/formal"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/resolver"
+)
+
+const defaultRandomVectors = 1000
+
+type EquivalenceOptions struct {
+	// Seed of the random input vectors, the same seed gives the same vectors.
+	Seed uint64
+	// RandomVectors is the number of random input vectors used when the inputs
+	// cannot be enumerated, or the number of random clock cycles for sequential
+	// chips. Defaults to 1000.
+	RandomVectors int
+}
+
+// Counterexample is an input sequence for which a chip and its built-in
+// reference produce different outputs.
+type Counterexample struct {
+	// Inputs holds the failing input of a combinational chip, or for sequential
+	// chips the inputs of every clock cycle from the start, as the difference
+	// may depend on the state built up by the earlier cycles.
+	Inputs []map[string][]bool
+	// Phase is "tick" or "tock" for sequential chips, telling where the outputs
+	// of the last cycle differ. It is empty for combinational chips.
+	Phase    string
+	Output   string
+	Expected []bool // output of the built-in chip
+	Actual   []bool // output of the chip
+}
+
+// CheckEquivalence compares the chip with the built-in chip of the same name.
+// Combinational chips with at most MaxTruthTableInputBits input bits are checked
+// for every input, other chips with corner cases (0, -1, 1, the sign bit and
+// alternating bits on each input) followed by random vectors. Sequential chips
+// are driven through a single sequence of clock cycles, so that their state
+// builds up. It returns nil if no difference was found.
+func (hs *HardwareSimulator) CheckEquivalence(chipName string, options EquivalenceOptions) (*Counterexample, error) {
+	reference, ok := hs.registry.Lookup(chipName)
+	if !ok {
+		return nil, fmt.Errorf("There is no built-in chip named '%s' to compare with", chipName)
+	}
+	signature := reference.Signature()
+
+	_, rchd, g, err := hs.buildGraph(chipName)
+	if err != nil {
+		return nil, err
+	}
+	if !maps.Equal(rchd.Inputs, signature.Inputs) || !maps.Equal(rchd.Outputs, signature.Outputs) {
+		return nil, fmt.Errorf("Chip '%s' does not have the same inputs and outputs as the built-in chip", chipName)
+	}
+
+	referenceGraph, err := hs.builtinGraph(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.RandomVectors == 0 {
+		options.RandomVectors = defaultRandomVectors
+	}
+
+	actual := evaluator.New(g)
+	actual.InitializeNodeStates()
+	expected := evaluator.New(referenceGraph)
+	expected.InitializeNodeStates()
+
+	inputs := slices.Sorted(maps.Keys(signature.Inputs))
+	outputs := slices.Sorted(maps.Keys(signature.Outputs))
+	sequential := reference.Init() != nil
+	vectors := equivalenceVectors(signature, inputs, sequential, options)
+
+	var applied []map[string][]bool
+	for vector := range vectors {
+		if sequential {
+			applied = append(applied, vector)
+		} else {
+			applied = []map[string][]bool{vector}
+		}
+
+		phases := []string{""}
+		if sequential {
+			phases = []string{"tick", "tock"}
+		}
+		for _, phase := range phases {
+			for _, e := range []*evaluator.Evaluator{actual, expected} {
+				e.SetInputs(vector)
+				switch phase {
+				case "tick":
+					e.Apply()
+					e.EvaluateAndCommit()
+				case "tock":
+					e.Apply()
+					e.Evaluate()
+				default:
+					e.Evaluate()
+				}
+			}
+
+			actualOutputs, _ := actual.GetOutputsAndInternalPins()
+			expectedOutputs, _ := expected.GetOutputsAndInternalPins()
+			for _, output := range outputs {
+				if !slices.Equal(actualOutputs[output], expectedOutputs[output]) {
+					return &Counterexample{
+						Inputs:   applied,
+						Phase:    phase,
+						Output:   output,
+						Expected: expectedOutputs[output],
+						Actual:   actualOutputs[output],
+					}, nil
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// ProveEquivalence proves with a SAT solver that the chip computes the same
+// outputs as the reference for every input, or returns inputs for which they
+// differ. The reference is a chip of the project if there is one with that
+// name, otherwise a built-in chip. Both chips have to be combinational.
+func (hs *HardwareSimulator) ProveEquivalence(chipName string, referenceName string) (*formal.Result, error) {
+	m, err := hs.equivalenceMiter(chipName, referenceName)
+	if err != nil {
+		return nil, err
+	}
+	return m.Prove(), nil
+}
+
+// EquivalenceCNF returns the formula ProveEquivalence solves, to hand it to an
+// external solver: it is unsatisfiable exactly when the chips are equivalent.
+func (hs *HardwareSimulator) EquivalenceCNF(chipName string, referenceName string) (*formal.CNF, error) {
+	m, err := hs.equivalenceMiter(chipName, referenceName)
+	if err != nil {
+		return nil, err
+	}
+	return m.CNF, nil
+}
+
+func (hs *HardwareSimulator) equivalenceMiter(chipName string, referenceName string) (*formal.Miter, error) {
+	_, _, g, err := hs.buildGraph(chipName)
+	if err != nil {
+		return nil, err
+	}
+
+	var referenceGraph *graphbuilder.Graph
+	if _, ok := hs.hdls[referenceName]; ok {
+		_, _, referenceGraph, err = hs.buildGraph(referenceName)
+	} else if reference, ok := hs.registry.Lookup(referenceName); ok {
+		referenceGraph, err = hs.builtinGraph(reference)
+	} else {
+		err = errors.NewChipNotFoundError(referenceName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return formal.NewMiter(g, referenceGraph)
+}
+
+// builtinGraph returns the graph of a chip made of the built-in chip alone.
+func (hs *HardwareSimulator) builtinGraph(builtin chips.BuiltinChip) (*graphbuilder.Graph, error) {
+	signature := builtin.Signature()
+	rchd := &resolver.ResolvedChipDefinition{
+		Name:        builtin.Name(),
+		Inputs:      signature.Inputs,
+		Outputs:     signature.Outputs,
+		BuiltinName: builtin.Name(),
+	}
+	return graphbuilder.New(
+		map[string]*resolver.ResolvedChipDefinition{rchd.Name: rchd},
+		hs.registry,
+	).BuildGraph(rchd.Name)
+}
+
+// equivalenceVectors yields every input vector if there are few enough input
+// bits and the chip is combinational, otherwise corner cases and random vectors.
+func equivalenceVectors(
+	signature chips.Chip,
+	inputs []string,
+	sequential bool,
+	options EquivalenceOptions,
+) func(yield func(map[string][]bool) bool) {
+	inputBits := 0
+	for _, input := range inputs {
+		inputBits += signature.Inputs[input].Width
+	}
+
+	return func(yield func(map[string][]bool) bool) {
+		if !sequential && inputBits <= MaxTruthTableInputBits {
+			for value := range 1 << inputBits {
+				vector := make(map[string][]bool, len(inputs))
+				shift := 0
+				for _, input := range inputs {
+					width := signature.Inputs[input].Width
+					vector[input] = valueToBits(uint64(value>>shift), width)
+					shift += width
+				}
+				if !yield(vector) {
+					return
+				}
+			}
+			return
+		}
+
+		zero := func() map[string][]bool {
+			vector := make(map[string][]bool, len(inputs))
+			for _, input := range inputs {
+				vector[input] = make([]bool, signature.Inputs[input].Width)
+			}
+			return vector
+		}
+
+		allOnes := zero()
+		for _, input := range inputs {
+			for i := range allOnes[input] {
+				allOnes[input][i] = true
+			}
+		}
+		if !yield(zero()) || !yield(allOnes) {
+			return
+		}
+
+		for _, input := range inputs {
+			width := signature.Inputs[input].Width
+			for _, corner := range cornerValues(width) {
+				vector := zero()
+				vector[input] = corner
+				if !yield(vector) {
+					return
+				}
+			}
+		}
+
+		random := rand.New(rand.NewPCG(options.Seed, options.Seed))
+		for range options.RandomVectors {
+			vector := make(map[string][]bool, len(inputs))
+			for _, input := range inputs {
+				bits := make([]bool, signature.Inputs[input].Width)
+				for i := range bits {
+					bits[i] = random.IntN(2) == 1
+				}
+				vector[input] = bits
+			}
+			if !yield(vector) {
+				return
+			}
+		}
+	}
+}
+
+// cornerValues returns the values of a pin that most often reveal mistakes:
+// all ones, 1, only the most significant bit and alternating bits.
+func cornerValues(width int) [][]bool {
+	allOnes := make([]bool, width)
+	one := make([]bool, width)
+	signBit := make([]bool, width)
+	evenBits := make([]bool, width)
+	oddBits := make([]bool, width)
+	for i := range width {
+		allOnes[i] = true
+		evenBits[i] = i%2 == 0
+		oddBits[i] = i%2 == 1
+	}
+	one[0] = true
+	signBit[width-1] = true
+	return [][]bool{allOnes, one, signBit, evenBits, oddBits}
+}
+
+// valueToBits returns the lowest width bits of the value, bit 0 being the least significant.
+func valueToBits(value uint64, width int) []bool {
+	bits := make([]bool, width)
+	for i := range bits {
+		bits[i] = value>>i&1 == 1
+	}
+	return bits
+}
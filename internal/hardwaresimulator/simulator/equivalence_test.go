@@ -0,0 +1,176 @@
+package simulator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEquivalence(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip":  testutils.ChipImplementations["NotChip"],
+		"AndChip":  testutils.ChipImplementations["AndChip"],
+		"OrChip":   testutils.ChipImplementations["OrChip"],
+		"MuxChip":  testutils.ChipImplementations["MuxChip"],
+		"BitChip":  testutils.ChipImplementations["BitChip"],
+		"Xor":      strings.Replace(testutils.ChipImplementations["XorChip"], "CHIP XorChip", "CHIP Xor", 1),
+		"Register": strings.Replace(testutils.ChipImplementations["RegisterChip"], "CHIP RegisterChip", "CHIP Register", 1),
+		"Mux8Way16": `CHIP Mux8Way16 {
+			IN a[16], b[16], c[16], d[16], e[16], f[16], g[16], h[16], sel[3];
+			OUT out[16];
+
+			PARTS:
+			Mux4Way16(a = a, b = b, c = c, d = d, sel = sel[0..1], out = ad);
+			Mux4Way16(a = e, b = f, c = g, d[0..4] = h[0..4], d[5] = g[5], d[6..15] = h[6..15], sel = sel[0..1], out = eh);
+			Mux16(a = ad, b = eh, sel = sel[2], out = out);
+		}`,
+		"Bit": `CHIP Bit {
+			IN in, load;
+			OUT out;
+
+			PARTS:
+			DFF(in = in, out = out);
+		}`,
+		"And": `CHIP And {
+			IN a, b, c;
+			OUT out;
+
+			PARTS:
+			Nand(a = a, b = b, out = out);
+		}`,
+	}
+
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+
+	t.Run("Equivalent combinational chip", func(t *testing.T) {
+		counterexample, err := hs.CheckEquivalence("Xor", EquivalenceOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, counterexample)
+	})
+
+	t.Run("Equivalent sequential chip", func(t *testing.T) {
+		counterexample, err := hs.CheckEquivalence("Register", EquivalenceOptions{RandomVectors: 100})
+		require.NoError(t, err)
+		assert.Nil(t, counterexample)
+	})
+
+	t.Run("Wrong bit of a wide chip", func(t *testing.T) {
+		counterexample, err := hs.CheckEquivalence("Mux8Way16", EquivalenceOptions{Seed: 42})
+		require.NoError(t, err)
+		require.NotNil(t, counterexample)
+
+		assert.Len(t, counterexample.Inputs, 1)
+		inputs := counterexample.Inputs[0]
+		assert.Equal(t, []bool{true, true, true}, inputs["sel"])
+		assert.NotEqual(t, inputs["g"][5], inputs["h"][5])
+		assert.Equal(t, "", counterexample.Phase)
+		assert.Equal(t, "out", counterexample.Output)
+		assert.Equal(t, inputs["h"], counterexample.Expected)
+		assert.NotEqual(t, counterexample.Expected[5], counterexample.Actual[5])
+	})
+
+	t.Run("Wrong sequential chip", func(t *testing.T) {
+		counterexample, err := hs.CheckEquivalence("Bit", EquivalenceOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, counterexample)
+
+		// 0, 1, then the corner cases of in while load is 0
+		assert.Equal(t, []map[string][]bool{
+			{"in": {false}, "load": {false}},
+			{"in": {true}, "load": {true}},
+			{"in": {true}, "load": {false}},
+			{"in": {true}, "load": {false}},
+			{"in": {true}, "load": {false}},
+			{"in": {true}, "load": {false}},
+			{"in": {false}, "load": {false}},
+		}, counterexample.Inputs)
+		assert.Equal(t, "tock", counterexample.Phase)
+		assert.Equal(t, "out", counterexample.Output)
+		assert.Equal(t, []bool{true}, counterexample.Expected)
+		assert.Equal(t, []bool{false}, counterexample.Actual)
+	})
+
+	t.Run("Different IO", func(t *testing.T) {
+		_, err := hs.CheckEquivalence("And", EquivalenceOptions{})
+		assert.EqualError(t, err, "Chip 'And' does not have the same inputs and outputs as the built-in chip")
+	})
+
+	t.Run("No built-in chip", func(t *testing.T) {
+		_, err := hs.CheckEquivalence("BitChip", EquivalenceOptions{})
+		assert.EqualError(t, err, "There is no built-in chip named 'BitChip' to compare with")
+	})
+}
+
+func TestProveEquivalence(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip": testutils.ChipImplementations["NotChip"],
+		"AndChip": testutils.ChipImplementations["AndChip"],
+		"OrChip":  testutils.ChipImplementations["OrChip"],
+		"XorChip": testutils.ChipImplementations["XorChip"],
+		"MuxChip": testutils.ChipImplementations["MuxChip"],
+		"BitChip": testutils.ChipImplementations["BitChip"],
+		"NandXor": `CHIP NandXor {
+			IN a, b;
+			OUT out;
+
+			PARTS:
+			Nand(a = a, b = b, out = ab);
+			Nand(a = a, b = ab, out = x);
+			Nand(a = ab, b = b, out = y);
+			Nand(a = x, b = y, out = out);
+		}`,
+		"WrongXor": `CHIP WrongXor {
+			IN a, b;
+			OUT out;
+
+			PARTS:
+			Or(a = a, b = b, out = out);
+		}`,
+	}
+
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+
+	t.Run("Built-in reference", func(t *testing.T) {
+		result, err := hs.ProveEquivalence("XorChip", "Xor")
+		require.NoError(t, err)
+		assert.True(t, result.Equivalent)
+	})
+
+	t.Run("Chip of the project as reference", func(t *testing.T) {
+		result, err := hs.ProveEquivalence("NandXor", "XorChip")
+		require.NoError(t, err)
+		assert.True(t, result.Equivalent)
+	})
+
+	t.Run("Counterexample", func(t *testing.T) {
+		result, err := hs.ProveEquivalence("WrongXor", "Xor")
+		require.NoError(t, err)
+		assert.False(t, result.Equivalent)
+		assert.Equal(t, map[string][]bool{"a": {true}, "b": {true}}, result.Counterexample)
+		assert.Equal(t, "out", result.Output)
+		assert.Equal(t, []bool{false}, result.Expected)
+		assert.Equal(t, []bool{true}, result.Actual)
+	})
+
+	t.Run("DIMACS export", func(t *testing.T) {
+		cnf, err := hs.EquivalenceCNF("XorChip", "Xor")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(cnf.DIMACS(), "p cnf "))
+	})
+
+	t.Run("Sequential chip", func(t *testing.T) {
+		_, err := hs.ProveEquivalence("BitChip", "Bit")
+		assert.EqualError(t, err, "Chip 'DFF' is sequential, only combinational chips can be proven equivalent")
+	})
+
+	t.Run("Unknown reference", func(t *testing.T) {
+		_, err := hs.ProveEquivalence("XorChip", "Foo")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,194 @@
+package simulator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/analysis"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/evaluator"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
+)
+
+// FaultSite is a bit of a signal that a fault can be injected into.
+type FaultSite struct {
+	// Parts leads from the chip to the part the signal belongs to, it is empty
+	// for the signals of the chip itself.
+	Parts  []analysis.PartRef `json:"parts"`
+	Signal string             `json:"signal"`
+	Bit    int                `json:"bit"`
+}
+
+func (s FaultSite) String() string {
+	var sb strings.Builder
+	for _, part := range s.Parts {
+		sb.WriteString(fmt.Sprintf("%s@%d:%d/", part.Chip, part.Line, part.Column))
+	}
+	sb.WriteString(fmt.Sprintf("%s[%d]", s.Signal, s.Bit))
+	return sb.String()
+}
+
+type StuckAtFault struct {
+	FaultSite
+	StuckAt bool `json:"stuckAt"`
+}
+
+type FaultCoverage struct {
+	Faults     int            `json:"faults"`
+	Detected   int            `json:"detected"`
+	Undetected []StuckAtFault `json:"undetected"`
+}
+
+// Ratio returns the share of the faults that were detected, 1 if there are none.
+func (c *FaultCoverage) Ratio() float64 {
+	if c.Faults == 0 {
+		return 1
+	}
+	return float64(c.Detected) / float64(c.Faults)
+}
+
+// faultSite is a site with the bit it stands for.
+type faultSite struct {
+	FaultSite
+	bit *graphbuilder.Bit
+}
+
+// faultSites lists every signal bit of the chip and of its parts once. Bits
+// shared by several signals, like the input of a part and the signal connected
+// to it, are listed under the name closest to the chip. Constants and
+// unconnected outputs of parts are left out.
+func faultSites(g *graphbuilder.Graph) []faultSite {
+	seen := make(map[*graphbuilder.Bit]bool)
+	var sites []faultSite
+
+	var visit func(g *graphbuilder.Graph, parts []analysis.PartRef)
+	visit = func(g *graphbuilder.Graph, parts []analysis.PartRef) {
+		add := func(signal string, bits []*graphbuilder.BitRef) {
+			for i, bitRef := range bits {
+				if seen[bitRef.Bit] {
+					continue
+				}
+				seen[bitRef.Bit] = true
+				sites = append(sites, faultSite{
+					FaultSite: FaultSite{Parts: parts, Signal: signal, Bit: i},
+					bit:       bitRef.Bit,
+				})
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(g.InputPins)) {
+			add(name, g.InputPins[name].Bits)
+		}
+		for _, name := range slices.Sorted(maps.Keys(g.InternalPins)) {
+			add(name, g.InternalPins[name].Bits)
+		}
+		for _, name := range slices.Sorted(maps.Keys(g.OutputPins)) {
+			add(name, g.OutputPins[name].Bits)
+		}
+
+		nodes := slices.Clone(g.Nodes)
+		slices.SortStableFunc(nodes, func(a, b *graphbuilder.Node) int {
+			return cmp.Or(cmp.Compare(a.Loc.Line, b.Loc.Line), cmp.Compare(a.Loc.Column, b.Loc.Column))
+		})
+		for _, node := range nodes {
+			if node.SubGraph != nil {
+				part := analysis.PartRef{Chip: node.ChipName, Line: node.Loc.Line, Column: node.Loc.Column}
+				visit(node.SubGraph, append(slices.Clip(parts), part))
+			}
+		}
+	}
+
+	visit(g, []analysis.PartRef{})
+	return sites
+}
+
+// FaultSites lists the bits of the chip loaded with Process that faults can be injected into.
+func (hs *HardwareSimulator) FaultSites() []FaultSite {
+	var sites []FaultSite
+	for _, site := range faultSites(hs.Evaluator.Graph) {
+		sites = append(sites, site.FaultSite)
+	}
+	return sites
+}
+
+// InjectFault forces a bit of the chip loaded with Process to a value, until
+// the faults are cleared.
+func (hs *HardwareSimulator) InjectFault(fault StuckAtFault) error {
+	for _, site := range faultSites(hs.Evaluator.Graph) {
+		if slices.Equal(site.Parts, fault.Parts) && site.Signal == fault.Signal && site.Bit == fault.Bit {
+			hs.Evaluator.InjectFault(site.bit, fault.StuckAt)
+			return nil
+		}
+	}
+	return fmt.Errorf("There is no signal bit %s to inject a fault into", fault.FaultSite)
+}
+
+func (hs *HardwareSimulator) ClearFaults() {
+	hs.Evaluator.ClearFaults()
+}
+
+// FaultCoverage injects every stuck-at-0 and stuck-at-1 fault of the chip one
+// at a time, and replays the input sequence from the initial state. Every step
+// is a clock cycle, the outputs being compared with the ones of the fault-free
+// chip after the tick and after the tock. A fault is detected if an output
+// differs at any point. Inputs missing from a step are 0.
+func (hs *HardwareSimulator) FaultCoverage(chipName string, sequence []map[string][]bool) (*FaultCoverage, error) {
+	_, rchd, g, err := hs.buildGraph(chipName)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := completeInputs(rchd.Inputs, sequence, "step")
+	if err != nil {
+		return nil, err
+	}
+
+	e := evaluator.New(g)
+	e.InitializeNodeStates()
+	expected := runSequence(e, steps, nil)
+
+	coverage := &FaultCoverage{Undetected: []StuckAtFault{}}
+	for _, site := range faultSites(g) {
+		for _, value := range []bool{false, true} {
+			fault := StuckAtFault{FaultSite: site.FaultSite, StuckAt: value}
+			coverage.Faults++
+
+			e.ResetNodeStates()
+			e.InjectFault(site.bit, value)
+			if runSequence(e, steps, expected) == nil {
+				coverage.Detected++
+			} else {
+				coverage.Undetected = append(coverage.Undetected, fault)
+			}
+			e.ClearFaults()
+		}
+	}
+
+	return coverage, nil
+}
+
+// runSequence returns the outputs after the tick and the tock of every step.
+// If expected outputs are given, it stops and returns nil at the first difference.
+func runSequence(e *evaluator.Evaluator, steps []map[string][]bool, expected []map[string][]bool) []map[string][]bool {
+	var results []map[string][]bool
+	for _, inputs := range steps {
+		for _, tick := range []bool{true, false} {
+			e.SetInputs(inputs)
+			e.Apply()
+			if tick {
+				e.EvaluateAndCommit()
+			} else {
+				e.Evaluate()
+			}
+
+			outputs, _ := e.GetOutputsAndInternalPins()
+			if expected != nil && !maps.EqualFunc(outputs, expected[len(results)], slices.Equal) {
+				return nil
+			}
+			results = append(results, outputs)
+		}
+	}
+	return results
+}
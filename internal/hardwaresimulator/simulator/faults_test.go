@@ -0,0 +1,156 @@
+package simulator
+
+import (
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/analysis"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultCoverage(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip": testutils.ChipImplementations["NotChip"],
+		"AndChip": testutils.ChipImplementations["AndChip"],
+		"OrChip":  testutils.ChipImplementations["OrChip"],
+		"XorChip": testutils.ChipImplementations["XorChip"],
+		"MuxChip": testutils.ChipImplementations["MuxChip"],
+		"BitChip": testutils.ChipImplementations["BitChip"],
+		"Redundant": `CHIP Redundant {
+			IN a, b;
+			OUT out;
+
+			PARTS:
+			And(a = a, b = b, out = aAndB);
+			Or(a = a, b = aAndB, out = out);
+		}`,
+	}
+
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+
+	t.Run("Exhaustive vectors detect every fault", func(t *testing.T) {
+		coverage, err := hs.FaultCoverage("XorChip", []map[string][]bool{
+			{"a": {false}, "b": {false}},
+			{"a": {false}, "b": {true}},
+			{"a": {true}, "b": {false}},
+			{"a": {true}, "b": {true}},
+		})
+		require.NoError(t, err)
+
+		// a, b, out, AOrB and ANandB, notA and notB in OrChip, aNandB in AndChip
+		assert.Equal(t, 16, coverage.Faults)
+		assert.Equal(t, 16, coverage.Detected)
+		assert.Empty(t, coverage.Undetected)
+		assert.Equal(t, 1.0, coverage.Ratio())
+	})
+
+	t.Run("A single vector misses faults", func(t *testing.T) {
+		coverage, err := hs.FaultCoverage("XorChip", []map[string][]bool{
+			{"a": {false}, "b": {false}},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 16, coverage.Faults)
+		assert.Less(t, coverage.Detected, coverage.Faults)
+		assert.Equal(t, coverage.Faults-coverage.Detected, len(coverage.Undetected))
+		assert.Contains(t, coverage.Undetected, StuckAtFault{
+			FaultSite: FaultSite{Parts: []analysis.PartRef{}, Signal: "out", Bit: 0},
+			StuckAt:   false,
+		})
+		assert.NotContains(t, coverage.Undetected, StuckAtFault{
+			FaultSite: FaultSite{Parts: []analysis.PartRef{}, Signal: "out", Bit: 0},
+			StuckAt:   true,
+		})
+	})
+
+	t.Run("Redundant logic", func(t *testing.T) {
+		coverage, err := hs.FaultCoverage("Redundant", []map[string][]bool{
+			{"a": {false}, "b": {false}},
+			{"a": {false}, "b": {true}},
+			{"a": {true}, "b": {false}},
+			{"a": {true}, "b": {true}},
+		})
+		require.NoError(t, err)
+
+		// out = a, so b has no effect and aAndB can't be seen stuck at 0
+		assert.Equal(t, 8, coverage.Faults)
+		assert.Equal(t, []StuckAtFault{
+			{FaultSite: FaultSite{Parts: []analysis.PartRef{}, Signal: "b", Bit: 0}, StuckAt: false},
+			{FaultSite: FaultSite{Parts: []analysis.PartRef{}, Signal: "b", Bit: 0}, StuckAt: true},
+			{FaultSite: FaultSite{Parts: []analysis.PartRef{}, Signal: "aAndB", Bit: 0}, StuckAt: false},
+		}, coverage.Undetected)
+	})
+
+	t.Run("Sequential chip", func(t *testing.T) {
+		coverage, err := hs.FaultCoverage("BitChip", []map[string][]bool{
+			{"in": {true}, "load": {true}},
+			{"in": {false}, "load": {false}},
+		})
+		require.NoError(t, err)
+		// the loaded 1 is only seen after the clock edge
+		assert.NotContains(t, coverage.Undetected, StuckAtFault{
+			FaultSite: FaultSite{Parts: []analysis.PartRef{}, Signal: "in", Bit: 0},
+			StuckAt:   false,
+		})
+		// a 0 is never loaded
+		assert.Contains(t, coverage.Undetected, StuckAtFault{
+			FaultSite: FaultSite{Parts: []analysis.PartRef{}, Signal: "in", Bit: 0},
+			StuckAt:   true,
+		})
+	})
+
+	t.Run("Missing inputs are 0", func(t *testing.T) {
+		coverage, err := hs.FaultCoverage("XorChip", []map[string][]bool{{"a": {true}}})
+		require.NoError(t, err)
+		assert.NotContains(t, coverage.Undetected, StuckAtFault{
+			FaultSite: FaultSite{Parts: []analysis.PartRef{}, Signal: "out", Bit: 0},
+			StuckAt:   false,
+		})
+	})
+
+	t.Run("Wrong input width", func(t *testing.T) {
+		_, err := hs.FaultCoverage("XorChip", []map[string][]bool{{"a": {true, false}}})
+		assert.EqualError(t, err, "Input 'a' of step 1 must have 1 bits, got 2")
+	})
+}
+
+func TestInjectFault(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip": testutils.ChipImplementations["NotChip"],
+		"AndChip": testutils.ChipImplementations["AndChip"],
+	}
+
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+	_, _, _, err := hs.Process("AndChip")
+	require.NoError(t, err)
+
+	sites := hs.FaultSites()
+	notChip := analysis.PartRef{Chip: "NotChip", Line: 6, Column: 9}
+	assert.Equal(t, []FaultSite{
+		{Parts: []analysis.PartRef{}, Signal: "a", Bit: 0},
+		{Parts: []analysis.PartRef{}, Signal: "b", Bit: 0},
+		{Parts: []analysis.PartRef{}, Signal: "aNandB", Bit: 0},
+		{Parts: []analysis.PartRef{}, Signal: "out", Bit: 0},
+	}, sites)
+	assert.Equal(t, "NotChip@6:9/in[0]", FaultSite{Parts: []analysis.PartRef{notChip}, Signal: "in", Bit: 0}.String())
+
+	inputs := map[string][]bool{"a": {true}, "b": {true}}
+	outputs, _ := hs.Evaluate(inputs)
+	assert.Equal(t, map[string][]bool{"out": {true}}, outputs)
+
+	err = hs.InjectFault(StuckAtFault{FaultSite: sites[1], StuckAt: false})
+	require.NoError(t, err)
+	outputs, _ = hs.Evaluate(inputs)
+	assert.Equal(t, map[string][]bool{"out": {false}}, outputs)
+
+	hs.ClearFaults()
+	outputs, _ = hs.Evaluate(inputs)
+	assert.Equal(t, map[string][]bool{"out": {true}}, outputs)
+
+	err = hs.InjectFault(StuckAtFault{FaultSite: FaultSite{Parts: []analysis.PartRef{notChip}, Signal: "x", Bit: 0}})
+	assert.EqualError(t, err, "There is no signal bit NotChip@6:9/x[0] to inject a fault into")
+}
@@ -3,6 +3,7 @@ package simulator
 import (
 	"testing"
 
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
 	"github.com/stretchr/testify/assert"
 )
@@ -686,7 +687,7 @@ func TestProject1ChipsSimulation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			hs := New()
+			hs := New(chips.NewDefaultRegistry())
 			hs.SetChipHDLs(tt.hdls)
 			inputs, outputs, _, err := hs.Process(tt.chipFileName)
 			if err != nil {
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
 	"github.com/stretchr/testify/assert"
 )
@@ -562,7 +563,7 @@ func TestProject3ChipsSimulation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			hs := New()
+			hs := New(chips.NewDefaultRegistry())
 			hs.SetChipHDLs(tt.hdls)
 			inputs, outputs, _, err := hs.Process(tt.chipFileName)
 			if err != nil {
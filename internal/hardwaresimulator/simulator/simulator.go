@@ -1,28 +1,130 @@
 package simulator
 
 import (
+	"maps"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/analysis"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/errors"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/evaluator"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/graphbuilder"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/resolver"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/schematic"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/verilog"
 )
 
 type HardwareSimulator struct {
-	hdls      map[string]string
-	Evaluator *evaluator.Evaluator
+	hdls       map[string]string
+	registry   *chips.Registry
+	fourValued bool
+	Evaluator  *evaluator.Evaluator
+	coverage   *analysis.Coverage
 }
 
-func New() *HardwareSimulator {
-	return &HardwareSimulator{}
+// New creates a simulator that can use the built-in chips of the registry,
+// usually chips.NewDefaultRegistry() extended with course-specific chips.
+func New(registry *chips.Registry) *HardwareSimulator {
+	return &HardwareSimulator{registry: registry}
 }
 
 func (hs *HardwareSimulator) SetChipHDLs(hdls map[string]string) {
 	hs.hdls = hdls
 }
 
+// SetFourValuedLogic switches between two-valued (0/1) and four-valued (0/1/X)
+// simulation. In four-valued mode unconnected pins and registers that were never
+// loaded read as X instead of 0. It takes effect on the next Process call.
+func (hs *HardwareSimulator) SetFourValuedLogic(enabled bool) {
+	hs.fourValued = enabled
+}
+
 func (hs *HardwareSimulator) Process(chipName string) (outputs map[string]int, inputs map[string]int, internals map[string]int, err error) {
+	_, rchd, g, err := hs.buildGraph(chipName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	inputs = make(map[string]int)
+	for inputName, input := range rchd.Inputs {
+		inputs[inputName] = input.Width
+	}
+	outputs = make(map[string]int)
+	for outputName, output := range rchd.Outputs {
+		outputs[outputName] = output.Width
+	}
+	internals = make(map[string]int)
+	for internalPinName, internalPin := range rchd.InternalSignals {
+		internals[internalPinName] = internalPin.Width
+	}
+
+	e := evaluator.New(g)
+	e.FourValued = hs.fourValued
+	e.InitializeNodeStates()
+	hs.Evaluator = e
+	hs.coverage = analysis.NewCoverage(g)
+
+	return inputs, outputs, internals, nil
+}
+
+// BuildGraph builds the graph of the chip without preparing it for evaluation,
+// e.g. to analyze its structure.
+func (hs *HardwareSimulator) BuildGraph(chipName string) (*graphbuilder.Graph, error) {
+	_, _, g, err := hs.buildGraph(chipName)
+	return g, err
+}
+
+// AnalyzeTiming returns the gate depths and the critical path of the chip,
+// counting the built-in chips with the default delays.
+func (hs *HardwareSimulator) AnalyzeTiming(chipName string) (*analysis.Timing, error) {
+	g, err := hs.BuildGraph(chipName)
+	if err != nil {
+		return nil, err
+	}
+	timing := analysis.AnalyzeTiming(g, analysis.DefaultDelays())
+	return &timing, nil
+}
+
+// Schematic describes the parts and wires of the chip, with the parts that are
+// custom chips expanded depth levels deep.
+func (hs *HardwareSimulator) Schematic(chipName string, depth int) (*schematic.Schematic, error) {
+	g, err := hs.BuildGraph(chipName)
+	if err != nil {
+		return nil, err
+	}
+	return schematic.Build(chipName, g, depth), nil
+}
+
+// buildGraph returns the parsed definition of the chip too, as it keeps the order of the IO pins.
+func (hs *HardwareSimulator) buildGraph(chipName string) (
+	*parser.ParsedChipDefinition,
+	*resolver.ResolvedChipDefinition,
+	*graphbuilder.Graph,
+	error,
+) {
+	chd, rchd, rchds, err := hs.resolve(chipName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	gb := graphbuilder.New(rchds, hs.registry)
+	g, err := gb.BuildGraph(rchd.Name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return chd, rchd, g, nil
+}
+
+// resolve returns the definitions of the chip and of every chip it uses, the
+// chip's own included.
+func (hs *HardwareSimulator) resolve(chipName string) (
+	*parser.ParsedChipDefinition,
+	*resolver.ResolvedChipDefinition,
+	map[string]*resolver.ResolvedChipDefinition,
+	error,
+) {
 	hdl, ok := hs.hdls[chipName]
 	if !ok {
 		return nil, nil, nil, errors.NewChipNotFoundError(chipName)
@@ -40,42 +142,33 @@ func (hs *HardwareSimulator) Process(chipName string) (outputs map[string]int, i
 		return nil, nil, nil, err
 	}
 
-	r := resolver.New(chd, chipName, hs.hdls)
+	r := resolver.New(chd, chipName, hs.hdls, hs.registry)
 	rchd, rchds, err := r.Resolve([]string{}, []string{})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	rchds[rchd.Name] = rchd
 
-	inputs = make(map[string]int)
-	for inputName, input := range rchd.Inputs {
-		inputs[inputName] = input.Width
-	}
-	outputs = make(map[string]int)
-	for outputName, output := range rchd.Outputs {
-		outputs[outputName] = output.Width
-	}
-	internals = make(map[string]int)
-	for internalPinName, internalPin := range rchd.InternalSignals {
-		internals[internalPinName] = internalPin.Width
-	}
+	return chd, rchd, rchds, nil
+}
 
-	gb := graphbuilder.New(rchds)
-	g, err := gb.BuildGraph(rchd.Name)
-	if err != nil {
-		return nil, nil, nil, err
+// Verilog translates the chips and every chip they use to Verilog modules, by module name.
+func (hs *HardwareSimulator) Verilog(chipNames ...string) (map[string]string, error) {
+	rchds := map[string]*resolver.ResolvedChipDefinition{}
+	for _, chipName := range chipNames {
+		_, _, chipRchds, err := hs.resolve(chipName)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(rchds, chipRchds)
 	}
-
-	e := evaluator.New(g)
-	e.InitializeNodeStates()
-	hs.Evaluator = e
-
-	return inputs, outputs, internals, nil
+	return verilog.Translate(rchds, hs.registry)
 }
 
 func (hs *HardwareSimulator) Evaluate(inputs map[string][]bool) (map[string][]bool, map[string][]bool) {
 	hs.Evaluator.SetInputs(inputs)
 	hs.Evaluator.Evaluate()
+	hs.coverage.Sample()
 	outputs, internalPins := hs.Evaluator.GetOutputsAndInternalPins()
 	return outputs, internalPins
 }
@@ -84,6 +177,7 @@ func (hs *HardwareSimulator) Tick(inputs map[string][]bool) (map[string][]bool,
 	hs.Evaluator.SetInputs(inputs)
 	hs.Evaluator.Apply()
 	hs.Evaluator.EvaluateAndCommit()
+	hs.coverage.Sample()
 	outputs, internalPins := hs.Evaluator.GetOutputsAndInternalPins()
 	return outputs, internalPins
 }
@@ -92,6 +186,27 @@ func (hs *HardwareSimulator) Tock(inputs map[string][]bool) (map[string][]bool,
 	hs.Evaluator.SetInputs(inputs)
 	hs.Evaluator.Apply()
 	hs.Evaluator.Evaluate()
+	hs.coverage.Sample()
 	outputs, internalPins := hs.Evaluator.GetOutputsAndInternalPins()
 	return outputs, internalPins
 }
+
+// Coverage returns the toggle coverage of the signals and the coverage of the
+// parts of the chip loaded with Process, over the evaluations since it was
+// loaded or since the coverage was reset.
+func (hs *HardwareSimulator) Coverage() *analysis.CoverageReport {
+	return hs.coverage.Report()
+}
+
+func (hs *HardwareSimulator) ResetCoverage() {
+	hs.coverage.Reset()
+}
+
+// UnknownOutputs returns the indexes of the output bits that depend on X after
+// the last evaluation, by output name. It is always empty in two-valued mode.
+func (hs *HardwareSimulator) UnknownOutputs() map[string][]int {
+	if !hs.Evaluator.FourValued {
+		return map[string][]int{}
+	}
+	return hs.Evaluator.UnknownOutputs()
+}
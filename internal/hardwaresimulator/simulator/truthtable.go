@@ -0,0 +1,198 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/evaluator"
+)
+
+// MaxTruthTableInputBits is the largest number of input bits a truth table is generated for.
+const MaxTruthTableInputBits = 16
+
+// TruthTableTooBigError is returned when the chip has too many input bits to enumerate.
+type TruthTableTooBigError struct {
+	ChipName  string
+	InputBits int
+}
+
+func (e *TruthTableTooBigError) Error() string {
+	return fmt.Sprintf(
+		"Truth table of chip '%s' is too big: it has %d input bits, at most %d are supported",
+		e.ChipName, e.InputBits, MaxTruthTableInputBits,
+	)
+}
+
+type TruthTableColumn struct {
+	Name  string
+	Width int
+}
+
+// TruthTableRow holds the value of each input and output, in the order of the columns.
+// Bit 0 of a value is the least significant bit.
+type TruthTableRow struct {
+	Inputs  [][]bool
+	Outputs [][]bool
+}
+
+type TruthTable struct {
+	ChipName string
+	Inputs   []TruthTableColumn // in the order of the IN declaration
+	Outputs  []TruthTableColumn // in the order of the OUT declaration
+	Rows     []TruthTableRow
+}
+
+// TruthTable evaluates the chip for every combination of its inputs, counting
+// up with the first input as the most significant bits. Clocked parts keep their
+// initial state, so the table describes the combinational behavior only.
+// It does not affect the chip loaded with Process.
+func (hs *HardwareSimulator) TruthTable(chipName string) (*TruthTable, error) {
+	chd, _, g, err := hs.buildGraph(chipName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &TruthTable{ChipName: chipName}
+	inputBits := 0
+	for _, input := range chd.Inputs {
+		table.Inputs = append(table.Inputs, TruthTableColumn{Name: input.Name, Width: input.Width})
+		inputBits += input.Width
+	}
+	for _, output := range chd.Outputs {
+		table.Outputs = append(table.Outputs, TruthTableColumn{Name: output.Name, Width: output.Width})
+	}
+
+	if inputBits > MaxTruthTableInputBits {
+		return nil, &TruthTableTooBigError{ChipName: chipName, InputBits: inputBits}
+	}
+
+	vectors := make([]map[string][]bool, 1<<inputBits)
+	for value := range vectors {
+		vectors[value] = make(map[string][]bool, len(table.Inputs))
+		row := TruthTableRow{}
+		shift := inputBits
+		for _, input := range table.Inputs {
+			shift -= input.Width
+			bits := make([]bool, input.Width)
+			for i := range bits {
+				bits[i] = value>>(shift+i)&1 == 1
+			}
+			vectors[value][input.Name] = bits
+			row.Inputs = append(row.Inputs, bits)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	for value, outputs := range evaluator.EvaluateBatch(g, vectors, 0) {
+		for _, output := range table.Outputs {
+			table.Rows[value].Outputs = append(table.Rows[value].Outputs, outputs[output.Name])
+		}
+	}
+
+	return table, nil
+}
+
+func (t *TruthTable) columns() []TruthTableColumn {
+	return append(append([]TruthTableColumn{}, t.Inputs...), t.Outputs...)
+}
+
+func (r TruthTableRow) values() [][]bool {
+	return append(append([][]bool{}, r.Inputs...), r.Outputs...)
+}
+
+// CSV returns the table with a header row, values are binary numbers.
+func (t *TruthTable) CSV() string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{}
+	for _, column := range t.columns() {
+		header = append(header, column.Name)
+	}
+	w.Write(header)
+
+	for _, row := range t.Rows {
+		record := []string{}
+		for _, value := range row.values() {
+			record = append(record, bitsToBinary(value))
+		}
+		w.Write(record)
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+// Markdown returns the table as a GitHub flavored Markdown table.
+func (t *TruthTable) Markdown() string {
+	var sb strings.Builder
+
+	columns := t.columns()
+	for _, column := range columns {
+		sb.WriteString("| " + column.Name + " ")
+	}
+	sb.WriteString("|\n")
+	for range columns {
+		sb.WriteString("| --- ")
+	}
+	sb.WriteString("|\n")
+
+	for _, row := range t.Rows {
+		for _, value := range row.values() {
+			sb.WriteString("| " + bitsToBinary(value) + " ")
+		}
+		sb.WriteString("|\n")
+	}
+
+	return sb.String()
+}
+
+// Cmp returns the table in the format of the .cmp files of the course, as the
+// test scripts print it with %B3.1.3 for single bits and %B1.w.1 for buses.
+func (t *TruthTable) Cmp() string {
+	var sb strings.Builder
+
+	columns := t.columns()
+	for _, column := range columns {
+		sb.WriteString("|" + centerCmpCell(column.Name, cmpCellWidth(column.Width)))
+	}
+	sb.WriteString("|\n")
+
+	for _, row := range t.Rows {
+		for i, value := range row.values() {
+			sb.WriteString("|" + centerCmpCell(bitsToBinary(value), cmpCellWidth(columns[i].Width)))
+		}
+		sb.WriteString("|\n")
+	}
+
+	return sb.String()
+}
+
+func cmpCellWidth(width int) int {
+	if width == 1 {
+		return 7
+	}
+	return width + 2
+}
+
+// centerCmpCell centers the text in the cell, the extra space going to the right.
+func centerCmpCell(text string, width int) string {
+	padding := max(width-len(text), 0)
+	left := padding / 2
+	return strings.Repeat(" ", left) + text + strings.Repeat(" ", padding-left)
+}
+
+// bitsToBinary formats the bits as a binary number, most significant bit first.
+func bitsToBinary(bits []bool) string {
+	var sb strings.Builder
+	for i := len(bits) - 1; i >= 0; i-- {
+		if bits[i] {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,120 @@
+package simulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruthTable(t *testing.T) {
+	hdls := map[string]string{
+		"NotChip": testutils.ChipImplementations["NotChip"],
+		"AndChip": testutils.ChipImplementations["AndChip"],
+		"Swap": `CHIP Swap {
+			IN in[2], enable;
+			OUT out[2];
+
+			PARTS:
+			And(a = in[1], b = enable, out = out[0]);
+			And(a = in[0], b = enable, out = out[1]);
+		}`,
+		"Wide": `CHIP Wide {
+			IN a[16], b;
+			OUT out[16], notB;
+
+			PARTS:
+			Not16(in = a, out = out);
+			Not(in = b, out = notB);
+		}`,
+	}
+
+	hs := New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+
+	t.Run("Single bits", func(t *testing.T) {
+		table, err := hs.TruthTable("AndChip")
+		require.NoError(t, err)
+
+		assert.Equal(t, []TruthTableColumn{{Name: "a", Width: 1}, {Name: "b", Width: 1}}, table.Inputs)
+		assert.Equal(t, []TruthTableColumn{{Name: "out", Width: 1}}, table.Outputs)
+		assert.Equal(t, []TruthTableRow{
+			{Inputs: [][]bool{{false}, {false}}, Outputs: [][]bool{{false}}},
+			{Inputs: [][]bool{{false}, {true}}, Outputs: [][]bool{{false}}},
+			{Inputs: [][]bool{{true}, {false}}, Outputs: [][]bool{{false}}},
+			{Inputs: [][]bool{{true}, {true}}, Outputs: [][]bool{{true}}},
+		}, table.Rows)
+
+		assert.Equal(t, ""+
+			"|   a   |   b   |  out  |\n"+
+			"|   0   |   0   |   0   |\n"+
+			"|   0   |   1   |   0   |\n"+
+			"|   1   |   0   |   0   |\n"+
+			"|   1   |   1   |   1   |\n",
+			table.Cmp(),
+		)
+	})
+
+	t.Run("Buses", func(t *testing.T) {
+		table, err := hs.TruthTable("Swap")
+		require.NoError(t, err)
+		require.Len(t, table.Rows, 8)
+
+		assert.Equal(t, ""+
+			"in,enable,out\n"+
+			"00,0,00\n"+
+			"00,1,00\n"+
+			"01,0,00\n"+
+			"01,1,10\n"+
+			"10,0,00\n"+
+			"10,1,01\n"+
+			"11,0,00\n"+
+			"11,1,11\n",
+			table.CSV(),
+		)
+
+		assert.Equal(t, ""+
+			"| in | enable | out |\n"+
+			"| --- | --- | --- |\n"+
+			"| 00 | 0 | 00 |\n"+
+			"| 00 | 1 | 00 |\n"+
+			"| 01 | 0 | 00 |\n"+
+			"| 01 | 1 | 10 |\n"+
+			"| 10 | 0 | 00 |\n"+
+			"| 10 | 1 | 01 |\n"+
+			"| 11 | 0 | 00 |\n"+
+			"| 11 | 1 | 11 |\n",
+			table.Markdown(),
+		)
+
+		assert.Equal(t, ""+
+			"| in |enable |out |\n"+
+			"| 00 |   0   | 00 |\n"+
+			"| 00 |   1   | 00 |\n"+
+			"| 01 |   0   | 00 |\n"+
+			"| 01 |   1   | 10 |\n"+
+			"| 10 |   0   | 00 |\n"+
+			"| 10 |   1   | 01 |\n"+
+			"| 11 |   0   | 00 |\n"+
+			"| 11 |   1   | 11 |\n",
+			table.Cmp(),
+		)
+	})
+
+	t.Run("Too many input bits", func(t *testing.T) {
+		_, err := hs.TruthTable("Wide")
+
+		var tooBigErr *TruthTableTooBigError
+		require.True(t, errors.As(err, &tooBigErr))
+		assert.Equal(t, 17, tooBigErr.InputBits)
+		assert.Equal(t, "Truth table of chip 'Wide' is too big: it has 17 input bits, at most 16 are supported", err.Error())
+	})
+
+	t.Run("Missing chip", func(t *testing.T) {
+		_, err := hs.TruthTable("Missing")
+		assert.Error(t, err)
+	})
+}
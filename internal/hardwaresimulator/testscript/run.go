@@ -0,0 +1,420 @@
+package testscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/simulator"
+)
+
+// timeColumn is the name of the output column that shows the clock, like 3+
+// after the tick of the fourth cycle.
+const timeColumn = "time"
+
+// contextCheckInterval is how many commands run between checks of the context.
+const contextCheckInterval = 256
+
+// ErrStepLimitExceeded is returned when a script runs more commands than allowed.
+var ErrStepLimitExceeded = errors.New("testscript: step limit exceeded")
+
+type Options struct {
+	// Chip is loaded before running the script, load commands can replace it.
+	Chip string
+	// Compare is the content of the compare file, the output is not compared if empty.
+	Compare string
+	// MaxSteps limits the number of commands that run, counting every
+	// iteration of loops, 0 meaning no limit.
+	MaxSteps int
+}
+
+// Mismatch is the first line of the output that differs from the compare file.
+type Mismatch struct {
+	Line     int    `json:"line"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+type Result struct {
+	Output string   `json:"output"`
+	Echo   []string `json:"echo"`
+	// Compared tells if a compare file was given, and Passed if every line of
+	// the output matched it.
+	Compared bool      `json:"compared"`
+	Passed   bool      `json:"passed"`
+	Mismatch *Mismatch `json:"mismatch,omitempty"`
+	Steps    int       `json:"steps"`
+}
+
+type runner struct {
+	ctx      context.Context
+	hs       *simulator.HardwareSimulator
+	options  Options
+	compare  []string
+	loaded   bool
+	widths   map[string]int
+	inputs   map[string][]bool
+	values   map[string][]bool
+	columns  []Column
+	cycle    int
+	ticked   bool
+	output   []string
+	echo     []string
+	mismatch *Mismatch
+	steps    int
+}
+
+// Run runs the script on the simulator, which has to have the HDLs of the chips
+// set. It stops at the first line of the output that differs from the compare
+// file, when the step limit is reached or when the context is done.
+func Run(ctx context.Context, hs *simulator.HardwareSimulator, script *Script, options Options) (*Result, error) {
+	r := &runner{
+		ctx:     ctx,
+		hs:      hs,
+		options: options,
+		echo:    []string{},
+	}
+	if options.Compare != "" {
+		r.compare = strings.Split(strings.ReplaceAll(options.Compare, "\r\n", "\n"), "\n")
+	}
+
+	if options.Chip != "" {
+		if err := r.load(options.Chip); err != nil {
+			return nil, err
+		}
+	}
+
+	err := r.run(script.Commands)
+	if err != nil && !errors.Is(err, errMismatch) {
+		return nil, err
+	}
+
+	result := &Result{
+		Output:   strings.Join(r.output, "\n"),
+		Echo:     r.echo,
+		Compared: r.compare != nil,
+		Mismatch: r.mismatch,
+		Steps:    r.steps,
+	}
+	if len(r.output) > 0 {
+		result.Output += "\n"
+	}
+	result.Passed = result.Compared && r.mismatch == nil
+	return result, nil
+}
+
+// errMismatch stops the script at the first difference from the compare file.
+var errMismatch = errors.New("testscript: output differs from the compare file")
+
+func (r *runner) run(commands []Command) error {
+	for _, command := range commands {
+		if err := r.step(); err != nil {
+			return err
+		}
+		if err := r.runCommand(command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// step counts a step against the step limit and checks the context now and
+// then. Every command and every loop iteration is a step, so that loops with
+// an empty body end too.
+func (r *runner) step() error {
+	r.steps++
+	if r.options.MaxSteps > 0 && r.steps > r.options.MaxSteps {
+		return ErrStepLimitExceeded
+	}
+	if r.steps%contextCheckInterval == 0 {
+		if err := r.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *runner) runCommand(command Command) error {
+	switch command.Kind {
+	case CommandLoad:
+		if command.Name == "" {
+			return newError(command.Line, "load needs a chip name here")
+		}
+		return r.load(strings.TrimSuffix(command.Name, ".hdl"))
+	case CommandOutputFile, CommandCompareTo:
+		// the output and the compare file are passed around by the caller
+		return nil
+	case CommandOutputList:
+		if err := r.requireChip(command.Line); err != nil {
+			return err
+		}
+		columns := make([]Column, len(command.Columns))
+		for i, column := range command.Columns {
+			width, ok := r.widths[column.Name]
+			if !ok && column.Name != timeColumn {
+				return newError(command.Line, "Unknown pin '%s'", column.Name)
+			}
+			if column.defaultWidth {
+				column.Width = max(width, 1)
+				if column.Name == timeColumn {
+					column.Format, column.Width = 'S', 4
+				}
+			}
+			columns[i] = column
+		}
+		r.columns = columns
+		return r.writeLine(r.header(), command.Line)
+	case CommandSet:
+		if err := r.requireChip(command.Line); err != nil {
+			return err
+		}
+		if _, ok := r.inputs[command.Name]; !ok {
+			return newError(command.Line, "'%s' is not an input pin of the chip", command.Name)
+		}
+		r.inputs[command.Name] = toBits(command.Value, r.widths[command.Name])
+		r.values[command.Name] = r.inputs[command.Name]
+		return nil
+	case CommandEval, CommandTick, CommandTock:
+		if err := r.requireChip(command.Line); err != nil {
+			return err
+		}
+		var outputs, internals map[string][]bool
+		switch command.Kind {
+		case CommandEval:
+			outputs, internals = r.hs.Evaluate(r.inputs)
+		case CommandTick:
+			outputs, internals = r.hs.Tick(r.inputs)
+			r.ticked = true
+		case CommandTock:
+			outputs, internals = r.hs.Tock(r.inputs)
+			r.ticked = false
+			r.cycle++
+		}
+		for name, bits := range outputs {
+			r.values[name] = bits
+		}
+		for name, bits := range internals {
+			r.values[name] = bits
+		}
+		return nil
+	case CommandOutput:
+		if r.columns == nil {
+			return newError(command.Line, "output needs an output-list first")
+		}
+		return r.writeLine(r.row(), command.Line)
+	case CommandEcho:
+		r.echo = append(r.echo, command.Name)
+		return nil
+	case CommandClearEcho:
+		r.echo = []string{}
+		return nil
+	case CommandRepeat:
+		for i := 0; command.Count == 0 || i < command.Count; i++ {
+			if err := r.run(command.Body); err != nil {
+				return err
+			}
+			if err := r.step(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CommandWhile:
+		for {
+			holds, err := r.holds(command.Condition, command.Line)
+			if err != nil {
+				return err
+			}
+			if !holds {
+				return nil
+			}
+			if err := r.run(command.Body); err != nil {
+				return err
+			}
+			if err := r.step(); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("testscript: unknown command kind %d", command.Kind)
+}
+
+// load processes the chip, errors in its HDL are returned as they are.
+func (r *runner) load(chipName string) error {
+	inputs, outputs, internals, err := r.hs.Process(chipName)
+	if err != nil {
+		return err
+	}
+
+	r.loaded = true
+	r.widths = make(map[string]int)
+	r.inputs = make(map[string][]bool)
+	r.values = make(map[string][]bool)
+	for name, width := range inputs {
+		r.widths[name] = width
+		r.inputs[name] = make([]bool, width)
+		r.values[name] = r.inputs[name]
+	}
+	for _, pins := range []map[string]int{outputs, internals} {
+		for name, width := range pins {
+			r.widths[name] = width
+			r.values[name] = make([]bool, width)
+		}
+	}
+	r.columns = nil
+	r.cycle = 0
+	r.ticked = false
+	return nil
+}
+
+func (r *runner) requireChip(line int) error {
+	if !r.loaded {
+		return newError(line, "No chip is loaded")
+	}
+	return nil
+}
+
+func (r *runner) holds(condition Condition, line int) (bool, error) {
+	bits, ok := r.values[condition.Name]
+	if !ok {
+		return false, newError(line, "Unknown pin '%s'", condition.Name)
+	}
+	value := toValue(bits)
+	switch condition.Operator {
+	case "=":
+		return value == condition.Value, nil
+	case "<>":
+		return value != condition.Value, nil
+	case "<":
+		return value < condition.Value, nil
+	case ">":
+		return value > condition.Value, nil
+	case "<=":
+		return value <= condition.Value, nil
+	default:
+		return value >= condition.Value, nil
+	}
+}
+
+// writeLine adds the line to the output, comparing it with the same line of
+// the compare file.
+func (r *runner) writeLine(text string, line int) error {
+	r.output = append(r.output, text)
+	if r.compare == nil {
+		return nil
+	}
+
+	n := len(r.output)
+	expected := ""
+	if n <= len(r.compare) {
+		expected = strings.TrimRight(r.compare[n-1], " \t")
+	}
+	if !matches(expected, text) {
+		r.mismatch = &Mismatch{Line: n, Expected: expected, Actual: text}
+		return errMismatch
+	}
+	return nil
+}
+
+// matches compares a line of the output with a line of the compare file, in
+// which '*' matches any character.
+func matches(expected string, actual string) bool {
+	actual = strings.TrimRight(actual, " \t")
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range len(expected) {
+		if expected[i] != '*' && expected[i] != actual[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *runner) header() string {
+	var sb strings.Builder
+	for _, column := range r.columns {
+		width := column.PaddingLeft + column.Width + column.PaddingRight
+		name := column.Name
+		if len(name) > width {
+			name = name[:width]
+		}
+		padding := width - len(name)
+		sb.WriteString("|" + strings.Repeat(" ", padding/2) + name + strings.Repeat(" ", padding-padding/2))
+	}
+	sb.WriteString("|")
+	return sb.String()
+}
+
+func (r *runner) row() string {
+	var sb strings.Builder
+	for _, column := range r.columns {
+		sb.WriteString("|" + strings.Repeat(" ", column.PaddingLeft))
+		sb.WriteString(r.format(column))
+		sb.WriteString(strings.Repeat(" ", column.PaddingRight))
+	}
+	sb.WriteString("|")
+	return sb.String()
+}
+
+// format formats the value of the column in its width, binary and hexadecimal
+// values padded with zeros, decimal values aligned to the right and strings to
+// the left.
+func (r *runner) format(column Column) string {
+	var text string
+	if column.Name == timeColumn {
+		text = strconv.Itoa(r.cycle)
+		if r.ticked {
+			text += "+"
+		}
+	} else {
+		bits := r.values[column.Name]
+		switch column.Format {
+		case 'B':
+			text = fmt.Sprintf("%0*b", column.Width, toUnsigned(bits))
+		case 'X':
+			text = fmt.Sprintf("%0*X", column.Width, toUnsigned(bits))
+		default:
+			text = strconv.FormatInt(toValue(bits), 10)
+		}
+	}
+
+	if len(text) > column.Width {
+		return text[len(text)-column.Width:]
+	}
+	padding := strings.Repeat(" ", column.Width-len(text))
+	if column.Format == 'S' {
+		return text + padding
+	}
+	return padding + text
+}
+
+// toBits returns the lowest bits of the value, in two's complement for negative values.
+func toBits(value int64, width int) []bool {
+	bits := make([]bool, width)
+	for i := range bits {
+		bits[i] = value>>i&1 == 1
+	}
+	return bits
+}
+
+func toUnsigned(bits []bool) uint64 {
+	var value uint64
+	for i, bit := range bits {
+		if bit {
+			value |= 1 << i
+		}
+	}
+	return value
+}
+
+// toValue returns the value of the bits the way the course's tools show it:
+// 16-bit values are signed, narrower ones unsigned.
+func toValue(bits []bool) int64 {
+	value := int64(toUnsigned(bits))
+	if len(bits) == 16 && bits[15] {
+		value -= 1 << 16
+	}
+	return value
+}
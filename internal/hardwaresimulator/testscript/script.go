@@ -0,0 +1,454 @@
+// Package testscript runs the test scripts of the course (.tst files) against
+// the hardware simulator, producing the output file and comparing it with a
+// compare file.
+package testscript
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Error is a syntax or runtime error of a test script.
+type Error struct {
+	Message string
+	Line    int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Test script error at line %d: %s", e.Line, e.Message)
+}
+
+func newError(line int, format string, args ...any) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...), Line: line}
+}
+
+type CommandKind int
+
+const (
+	CommandLoad CommandKind = iota
+	CommandOutputFile
+	CommandCompareTo
+	CommandOutputList
+	CommandSet
+	CommandEval
+	CommandTick
+	CommandTock
+	CommandOutput
+	CommandEcho
+	CommandClearEcho
+	CommandRepeat
+	CommandWhile
+)
+
+// Column is a column of the output list, printed with a format like %B1.16.1:
+// the padding on the left, the width and the padding on the right.
+type Column struct {
+	Name         string
+	Format       byte // B, D, X or S
+	PaddingLeft  int
+	Width        int
+	PaddingRight int
+	// defaultWidth is set when the width was not given, it is the width of the pin then.
+	defaultWidth bool
+}
+
+// Condition compares a pin with a value, like out <> 0.
+type Condition struct {
+	Name     string
+	Operator string
+	Value    int64
+}
+
+type Command struct {
+	Kind CommandKind
+	Line int
+
+	// Name is the chip or file of load, output-file and compare-to, the pin of
+	// set, and the text of echo.
+	Name    string
+	Value   int64
+	Columns []Column
+
+	// Count is the number of iterations of repeat, 0 repeating forever.
+	Count     int
+	Condition Condition
+	Body      []Command
+}
+
+type Script struct {
+	Commands []Command
+}
+
+type token struct {
+	text   string
+	line   int
+	quoted bool
+}
+
+// Parse parses a test script. Commands are separated by ',' inside a
+// simulation step, ';' ends a step and '!' ends a step with a pause, all of
+// them are treated the same.
+func Parse(src string) (*Script, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	commands, err := p.parseCommands(false)
+	if err != nil {
+		return nil, err
+	}
+	return &Script{Commands: commands}, nil
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			startLine := line
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, newError(startLine, "Unterminated comment")
+			}
+			i += 2
+		case r == '"':
+			start := i + 1
+			i++
+			for i < len(runes) && runes[i] != '"' && runes[i] != '\n' {
+				i++
+			}
+			if i >= len(runes) || runes[i] != '"' {
+				return nil, newError(line, "Unterminated string")
+			}
+			tokens = append(tokens, token{text: string(runes[start:i]), line: line, quoted: true})
+			i++
+		case strings.ContainsRune(",;!{}", r):
+			tokens = append(tokens, token{text: string(r), line: line})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(",;!{}\"", runes[i]) &&
+				!(runes[i] == '/' && i+1 < len(runes) && (runes[i+1] == '/' || runes[i+1] == '*')) {
+				i++
+			}
+			tokens = append(tokens, token{text: string(runes[start:i]), line: line})
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (token, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return t, ok
+}
+
+func (p *parser) lastLine() int {
+	if len(p.tokens) == 0 {
+		return 1
+	}
+	return p.tokens[len(p.tokens)-1].line
+}
+
+// expectWord returns the next token, which has to be a word.
+func (p *parser) expectWord(what string) (token, error) {
+	t, ok := p.next()
+	if !ok {
+		return token{}, newError(p.lastLine(), "Expected %s, got end of script", what)
+	}
+	if !t.quoted && isSeparator(t.text) {
+		return token{}, newError(t.line, "Expected %s, got '%s'", what, t.text)
+	}
+	return t, nil
+}
+
+func isSeparator(text string) bool {
+	return text == "," || text == ";" || text == "!" || text == "{" || text == "}"
+}
+
+// parseCommands parses commands until the end of the script, or until the
+// closing brace of a block.
+func (p *parser) parseCommands(block bool) ([]Command, error) {
+	commands := []Command{}
+	for {
+		t, ok := p.peek()
+		if !ok {
+			if block {
+				return nil, newError(p.lastLine(), "Expected '}', got end of script")
+			}
+			return commands, nil
+		}
+		if t.text == "}" && !t.quoted {
+			if !block {
+				return nil, newError(t.line, "Unexpected '}'")
+			}
+			p.pos++
+			return commands, nil
+		}
+		if !t.quoted && (t.text == "," || t.text == ";" || t.text == "!") {
+			p.pos++
+			continue
+		}
+
+		command, err := p.parseCommand()
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+
+		// simple commands have to be followed by a separator
+		if command.Kind != CommandRepeat && command.Kind != CommandWhile {
+			t, ok := p.peek()
+			if ok && !(!t.quoted && (t.text == "," || t.text == ";" || t.text == "!" || t.text == "}")) {
+				return nil, newError(t.line, "Expected ',', ';' or '!' after the command, got '%s'", t.text)
+			}
+		}
+	}
+}
+
+func (p *parser) parseCommand() (Command, error) {
+	t, _ := p.next()
+	command := Command{Line: t.line}
+
+	switch t.text {
+	case "load", "output-file", "compare-to":
+		command.Kind = map[string]CommandKind{
+			"load":        CommandLoad,
+			"output-file": CommandOutputFile,
+			"compare-to":  CommandCompareTo,
+		}[t.text]
+		if next, ok := p.peek(); ok && !next.quoted && !isSeparator(next.text) {
+			p.pos++
+			command.Name = next.text
+		} else if command.Kind != CommandLoad {
+			return command, newError(t.line, "Expected a file name after %s", t.text)
+		}
+	case "output-list":
+		command.Kind = CommandOutputList
+		for {
+			next, ok := p.peek()
+			if !ok || next.quoted || isSeparator(next.text) {
+				break
+			}
+			p.pos++
+			column, err := parseColumn(next)
+			if err != nil {
+				return command, err
+			}
+			command.Columns = append(command.Columns, column)
+		}
+		if len(command.Columns) == 0 {
+			return command, newError(t.line, "Expected at least one column after output-list")
+		}
+	case "set":
+		command.Kind = CommandSet
+		name, err := p.expectWord("a pin name")
+		if err != nil {
+			return command, err
+		}
+		value, err := p.expectWord("a value")
+		if err != nil {
+			return command, err
+		}
+		command.Name = name.text
+		command.Value, err = parseValue(value)
+		if err != nil {
+			return command, err
+		}
+	case "eval":
+		command.Kind = CommandEval
+	case "tick":
+		command.Kind = CommandTick
+	case "tock":
+		command.Kind = CommandTock
+	case "output":
+		command.Kind = CommandOutput
+	case "echo":
+		command.Kind = CommandEcho
+		text, err := p.expectWord("a text")
+		if err != nil {
+			return command, err
+		}
+		command.Name = text.text
+	case "clear-echo":
+		command.Kind = CommandClearEcho
+	case "repeat":
+		command.Kind = CommandRepeat
+		next, ok := p.peek()
+		if ok && !next.quoted && next.text != "{" {
+			p.pos++
+			count, err := strconv.Atoi(next.text)
+			if err != nil || count <= 0 {
+				return command, newError(next.line, "Expected a positive number of iterations, got '%s'", next.text)
+			}
+			command.Count = count
+		}
+		body, err := p.parseBlock()
+		if err != nil {
+			return command, err
+		}
+		command.Body = body
+	case "while":
+		command.Kind = CommandWhile
+		condition, err := p.parseCondition()
+		if err != nil {
+			return command, err
+		}
+		command.Condition = condition
+		body, err := p.parseBlock()
+		if err != nil {
+			return command, err
+		}
+		command.Body = body
+	default:
+		return command, newError(t.line, "Unknown command '%s'", t.text)
+	}
+
+	return command, nil
+}
+
+func (p *parser) parseBlock() ([]Command, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, newError(p.lastLine(), "Expected '{', got end of script")
+	}
+	if t.quoted || t.text != "{" {
+		return nil, newError(t.line, "Expected '{', got '%s'", t.text)
+	}
+	return p.parseCommands(true)
+}
+
+var operators = []string{"<>", "<=", ">=", "=", "<", ">"}
+
+// parseCondition parses a condition with or without spaces around the
+// operator, like out<>0 or out <> 0.
+func (p *parser) parseCondition() (Condition, error) {
+	var text strings.Builder
+	line := 0
+	for {
+		t, ok := p.peek()
+		if !ok || t.quoted || isSeparator(t.text) {
+			break
+		}
+		if line == 0 {
+			line = t.line
+		}
+		p.pos++
+		text.WriteString(t.text)
+	}
+
+	for _, operator := range operators {
+		name, value, found := strings.Cut(text.String(), operator)
+		if !found {
+			continue
+		}
+		if name == "" {
+			return Condition{}, newError(line, "Expected a pin name before '%s'", operator)
+		}
+		parsed, err := parseValue(token{text: value, line: line})
+		if err != nil {
+			return Condition{}, err
+		}
+		return Condition{Name: name, Operator: operator, Value: parsed}, nil
+	}
+	return Condition{}, newError(line, "Expected a condition like out <> 0, got '%s'", text.String())
+}
+
+// parseColumn parses an output list column like a%B3.1.3, or a name alone.
+func parseColumn(t token) (Column, error) {
+	name, format, found := strings.Cut(t.text, "%")
+	if !found {
+		return Column{Name: name, Format: 'B', PaddingLeft: 1, PaddingRight: 1, defaultWidth: true}, nil
+	}
+	if name == "" || len(format) < 1 || !strings.ContainsRune("BDXS", rune(format[0])) {
+		return Column{}, newError(t.line, "Invalid output column '%s'", t.text)
+	}
+
+	parts := strings.Split(format[1:], ".")
+	if len(parts) != 3 {
+		return Column{}, newError(t.line, "Invalid output column '%s', expected a format like %%B1.16.1", t.text)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Column{}, newError(t.line, "Invalid output column '%s', expected a format like %%B1.16.1", t.text)
+		}
+		numbers[i] = n
+	}
+	return Column{
+		Name:         name,
+		Format:       format[0],
+		PaddingLeft:  numbers[0],
+		Width:        numbers[1],
+		PaddingRight: numbers[2],
+	}, nil
+}
+
+// parseValue parses a decimal value, or one with a %B, %X or %D prefix.
+func parseValue(t token) (int64, error) {
+	text := t.text
+	base := 10
+	if len(text) >= 2 && text[0] == '%' {
+		switch text[1] {
+		case 'B':
+			base = 2
+		case 'X':
+			base = 16
+		case 'D':
+			base = 10
+		default:
+			return 0, newError(t.line, "Invalid value '%s'", t.text)
+		}
+		text = text[2:]
+	}
+
+	if base != 10 {
+		value, err := strconv.ParseUint(text, base, 64)
+		if err != nil {
+			return 0, newError(t.line, "Invalid value '%s'", t.text)
+		}
+		return int64(value), nil
+	}
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, newError(t.line, "Invalid value '%s'", t.text)
+	}
+	return value, nil
+}
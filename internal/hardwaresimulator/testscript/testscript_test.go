@@ -0,0 +1,259 @@
+package testscript
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/simulator"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const xorTest = `// Xor.tst
+load XorChip.hdl,
+output-file Xor.out,
+compare-to Xor.cmp,
+output-list a%B3.1.3 b%B3.1.3 out%B3.1.3;
+
+set a 0,
+set b 0,
+eval,
+output;
+
+set a 0,
+set b 1,
+eval,
+output;
+
+set a 1,
+set b 0,
+eval,
+output;
+
+set a 1,
+set b 1,
+eval,
+output;
+`
+
+const xorCmp = `|   a   |   b   |  out  |
+|   0   |   0   |   0   |
+|   0   |   1   |   1   |
+|   1   |   0   |   1   |
+|   1   |   1   |   0   |
+`
+
+func newSimulator() *simulator.HardwareSimulator {
+	hs := simulator.New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(testutils.ChipImplementations)
+	return hs
+}
+
+func mustRun(t *testing.T, src string, options Options) *Result {
+	t.Helper()
+
+	script, err := Parse(src)
+	require.NoError(t, err)
+	result, err := Run(context.Background(), newSimulator(), script, options)
+	require.NoError(t, err)
+	return result
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Passing comparison", func(t *testing.T) {
+		result := mustRun(t, xorTest, Options{Compare: xorCmp})
+		assert.Equal(t, xorCmp, result.Output)
+		assert.True(t, result.Compared)
+		assert.True(t, result.Passed)
+		assert.Nil(t, result.Mismatch)
+	})
+
+	t.Run("Failing comparison", func(t *testing.T) {
+		cmp := "|   a   |   b   |  out  |\n|   0   |   0   |   0   |\n|   0   |   1   |   0   |\n"
+		result := mustRun(t, xorTest, Options{Compare: cmp})
+		assert.False(t, result.Passed)
+		assert.Equal(t, &Mismatch{Line: 3, Expected: "|   0   |   1   |   0   |", Actual: "|   0   |   1   |   1   |"}, result.Mismatch)
+		assert.Equal(t, "|   a   |   b   |  out  |\n|   0   |   0   |   0   |\n|   0   |   1   |   1   |\n", result.Output, "expected the script to stop at the mismatch")
+	})
+
+	t.Run("Wildcards in the compare file", func(t *testing.T) {
+		cmp := "|   a   |   b   |  out  |\n|   0   |   0   |*******|\n|   0   |   1   |   1   |\n|   1   |   0   |   1   |\n|   1   |   1   |   0   |\n"
+		result := mustRun(t, xorTest, Options{Compare: cmp})
+		assert.True(t, result.Passed)
+	})
+
+	t.Run("Without a compare file", func(t *testing.T) {
+		result := mustRun(t, xorTest, Options{})
+		assert.False(t, result.Compared)
+		assert.False(t, result.Passed)
+		assert.Equal(t, xorCmp, result.Output)
+	})
+
+	t.Run("Clocked chip with time, repeat and while", func(t *testing.T) {
+		result := mustRun(t, `output-list time%S1.4.1 in%D2.1.2 load out%B1.1.1;
+			set in 1, set load 1,
+			while out = 0 {
+				tick, output;
+				tock, output;
+			}
+			set load 0, set in 0,
+			repeat 2 {
+				tick, tock, output;
+			}
+			echo "done";`, Options{Chip: "BitChip"})
+
+		assert.Equal(t, "| time | in  |loa|out|\n"+
+			"| 0+   |  1  | 1 | 0 |\n"+
+			"| 1    |  1  | 1 | 1 |\n"+
+			"| 2    |  0  | 0 | 1 |\n"+
+			"| 3    |  0  | 0 | 1 |\n", result.Output)
+		assert.Equal(t, []string{"done"}, result.Echo)
+	})
+
+	t.Run("Number formats", func(t *testing.T) {
+		hs := newSimulator()
+		hs.SetChipHDLs(map[string]string{"Wide": `CHIP Wide {
+			IN a[16];
+			OUT out[16];
+
+			PARTS:
+			Not16(in = a, out = out);
+		}`})
+		script, err := Parse(`load Wide,
+			output-list a%D1.6.1 a%X1.4.1 out%D1.6.1 out%B1.16.1;
+			set a %B101, eval, output;
+			set a -1, eval, output;
+			set a %XFFFE, eval, output;`)
+		require.NoError(t, err)
+		result, err := Run(context.Background(), hs, script, Options{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "|   a    |  a   |  out   |       out        |\n"+
+			"|      5 | 0005 |     -6 | 1111111111111010 |\n"+
+			"|     -1 | FFFF |      0 | 0000000000000000 |\n"+
+			"|     -2 | FFFE |      1 | 0000000000000001 |\n", result.Output)
+	})
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		script        string
+		options       Options
+		expectedError string
+	}{
+		{
+			name:          "Unknown pin in the output list",
+			script:        "load XorChip, output-list c;",
+			expectedError: "Test script error at line 1: Unknown pin 'c'",
+		},
+		{
+			name:          "Setting an output",
+			script:        "load XorChip,\nset out 1;",
+			expectedError: "Test script error at line 2: 'out' is not an input pin of the chip",
+		},
+		{
+			name:          "No chip",
+			script:        "set a 1;",
+			expectedError: "Test script error at line 1: No chip is loaded",
+		},
+		{
+			name:          "Missing chip",
+			script:        "load Missing.hdl;",
+			expectedError: "Chip not found: Missing",
+		},
+		{
+			name:          "Runaway repeat",
+			script:        "load XorChip, repeat { eval; }",
+			options:       Options{MaxSteps: 1000},
+			expectedError: ErrStepLimitExceeded.Error(),
+		},
+		{
+			name:          "Runaway repeat with an empty body",
+			script:        "load XorChip, repeat { }",
+			options:       Options{MaxSteps: 1000},
+			expectedError: ErrStepLimitExceeded.Error(),
+		},
+		{
+			name:          "Runaway while with an empty body",
+			script:        "load XorChip, while out = 0 { }",
+			options:       Options{MaxSteps: 1000},
+			expectedError: ErrStepLimitExceeded.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := Parse(tt.script)
+			require.NoError(t, err)
+			_, err = Run(context.Background(), newSimulator(), script, tt.options)
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+
+	for _, source := range []string{"load XorChip, repeat { }", "load XorChip, while out = 0 { }"} {
+		t.Run("Deadline of "+source, func(t *testing.T) {
+			script, err := Parse(source)
+			require.NoError(t, err)
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			_, err = Run(ctx, newSimulator(), script, Options{})
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	}
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		script, err := Parse("load XorChip, repeat { eval; }")
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = Run(ctx, newSimulator(), script, Options{})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestParse(t *testing.T) {
+	script, err := Parse(`/* multi
+		line */ load Foo.hdl, // the chip
+		output-list a%B3.1.3 b;
+		repeat 3 { tick, tock; }
+		while a<>%B11 { eval; }`)
+	require.NoError(t, err)
+	assert.Equal(t, []Command{
+		{Kind: CommandLoad, Line: 2, Name: "Foo.hdl"},
+		{Kind: CommandOutputList, Line: 3, Columns: []Column{
+			{Name: "a", Format: 'B', PaddingLeft: 3, Width: 1, PaddingRight: 3},
+			{Name: "b", Format: 'B', PaddingLeft: 1, PaddingRight: 1, defaultWidth: true},
+		}},
+		{Kind: CommandRepeat, Line: 4, Count: 3, Body: []Command{
+			{Kind: CommandTick, Line: 4},
+			{Kind: CommandTock, Line: 4},
+		}},
+		{Kind: CommandWhile, Line: 5, Condition: Condition{Name: "a", Operator: "<>", Value: 3}, Body: []Command{
+			{Kind: CommandEval, Line: 5},
+		}},
+	}, script.Commands)
+
+	tests := []struct {
+		script        string
+		expectedError string
+	}{
+		{"set a", "Test script error at line 1: Expected a value, got end of script"},
+		{"set a x;", "Test script error at line 1: Invalid value 'x'"},
+		{"foo;", "Test script error at line 1: Unknown command 'foo'"},
+		{"eval tick;", "Test script error at line 1: Expected ',', ';' or '!' after the command, got 'tick'"},
+		{"repeat 2 {\neval;", "Test script error at line 2: Expected '}', got end of script"},
+		{"repeat 0 { eval; }", "Test script error at line 1: Expected a positive number of iterations, got '0'"},
+		{"output-list a%Q1.1.1;", "Test script error at line 1: Invalid output column 'a%Q1.1.1'"},
+		{"while out { eval; }", "Test script error at line 1: Expected a condition like out <> 0, got 'out'"},
+		{"echo \"unterminated;", "Test script error at line 1: Unterminated string"},
+		{"}", "Test script error at line 1: Unexpected '}'"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.script)
+		assert.EqualError(t, err, tt.expectedError, tt.script)
+	}
+}
@@ -299,6 +299,42 @@ var ChipImplementations = map[string]string{
 		DFF(in = in, out = dff1, out = dff1internal);
 		DFF(in = dff1internal, out = dff2);
 	}`,
+	"BuiltinBitChip": `// This file is part of www.nand2tetris.org
+	/**
+	 * 1-bit register:
+	 * If load is asserted, the register's value is set to in;
+	 * Otherwise, the register maintains its current value.
+	 */
+	CHIP BuiltinBitChip {
+		IN in, load;
+		OUT out;
+
+		BUILTIN Bit;
+		CLOCKED in, load;
+	}`,
+	"BuiltinNandChip": `CHIP BuiltinNandChip {
+		IN a, b;
+		OUT out;
+
+		BUILTIN Nand;
+	}`,
+	"BuiltinXorChip": `CHIP BuiltinXorChip {
+		IN a, b;
+		OUT out;
+
+		PARTS:
+		BuiltinNandChip(a = a, b = b, out = nandAB);
+		BuiltinNandChip(a = a, b = nandAB, out = x);
+		BuiltinNandChip(a = nandAB, b = b, out = y);
+		BuiltinNandChip(a = x, b = y, out = out);
+	}`,
+	"ConstantMuxChip": `CHIP ConstantMuxChip {
+		IN sel;
+		OUT out[16];
+
+		PARTS:
+		Mux16(a = 0x7FFF, b[0..7] = 0b00000101, b[8..15] = 3, sel = sel, out = out);
+	}`,
 	"TestChip": `CHIP TestChip {
 		IN in;
 		OUT out, outnot;
@@ -7,6 +7,8 @@ type Token struct {
 	Literal   string
 	Line      int
 	Column    int
+	EndLine   int // line of the last character of the token
+	EndColumn int // column right after the last character of the token
 }
 
 const (
@@ -31,24 +33,28 @@ const (
 
 	RANGE TokenType = ".."
 
-	CHIP  TokenType = "CHIP"
-	IN    TokenType = "IN"
-	OUT   TokenType = "OUT"
-	PARTS TokenType = "PARTS"
-	TRUE  TokenType = "TRUE"
-	FALSE TokenType = "FALSE"
+	CHIP    TokenType = "CHIP"
+	IN      TokenType = "IN"
+	OUT     TokenType = "OUT"
+	PARTS   TokenType = "PARTS"
+	BUILTIN TokenType = "BUILTIN"
+	CLOCKED TokenType = "CLOCKED"
+	TRUE    TokenType = "TRUE"
+	FALSE   TokenType = "FALSE"
 
 	LINE_COMMENT  TokenType = "LINE_COMMENT"
 	BLOCK_COMMENT TokenType = "BLOCK_COMMENT"
 )
 
 var keywords = map[string]TokenType{
-	"CHIP":  CHIP,
-	"IN":    IN,
-	"OUT":   OUT,
-	"PARTS": PARTS,
-	"true":  TRUE,
-	"false": FALSE,
+	"CHIP":    CHIP,
+	"IN":      IN,
+	"OUT":     OUT,
+	"PARTS":   PARTS,
+	"BUILTIN": BUILTIN,
+	"CLOCKED": CLOCKED,
+	"true":    TRUE,
+	"false":   FALSE,
 }
 
 func LookupTokenType(ident string) TokenType {
@@ -0,0 +1,184 @@
+package verilog
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+)
+
+// behaviour returns the body of the module of a built-in chip.
+type behaviour func(signature chips.Chip) string
+
+// behaviours of the default built-in chips, by chip name
+var behaviours = map[string]behaviour{
+	"Nand":      assign("out", "~(a & b)"),
+	"And":       assign("out", "a & b"),
+	"And16":     assign("out", "a & b"),
+	"Or":        assign("out", "a | b"),
+	"Or16":      assign("out", "a | b"),
+	"Xor":       assign("out", "a ^ b"),
+	"Not":       assign("out", "~in"),
+	"Not16":     assign("out", "~in"),
+	"Or8Way":    assign("out", "|in"),
+	"Mux":       mux,
+	"Mux16":     mux,
+	"Mux4Way16": mux,
+	"Mux8Way16": mux,
+	"DMux":      dmux,
+	"DMux4Way":  dmux,
+	"DMux8Way":  dmux,
+	"HalfAdder": assign("{carry, sum}", "a + b"),
+	"FullAdder": assign("{carry, sum}", "a + b + c"),
+	"Add16":     assign("out", "a + b"),
+	"Inc16":     assign("out", "in + 16'd1"),
+	"DFF":       dff,
+	"Bit":       register,
+	"Register":  register,
+	"PC":        pc,
+	"RAM8":      ram,
+	"RAM64":     ram,
+	"RAM512":    ram,
+	"RAM4K":     ram,
+	"RAM16K":    ram,
+}
+
+// registerOutputs are the built-in chips whose output is assigned in an always block.
+var registerOutputs = map[string]bool{"DFF": true, "Bit": true, "Register": true, "PC": true}
+
+func (t *translator) translateBuiltin(name string) error {
+	behaviour, ok := behaviours[name]
+	if !ok {
+		return fmt.Errorf("Built-in chip '%s' cannot be translated to Verilog", name)
+	}
+	builtin, _ := t.registry.Lookup(name)
+	signature := builtin.Signature()
+
+	var sb strings.Builder
+	moduleName := t.builtinModuleName(name)
+	writeHeader(&sb, moduleName, signature, builtin.Init() != nil, registerOutputs[name])
+	sb.WriteString(behaviour(signature))
+	sb.WriteString("endmodule\n")
+	t.modules[moduleName] = sb.String()
+	return nil
+}
+
+func assign(target, expression string) behaviour {
+	return func(signature chips.Chip) string {
+		return fmt.Sprintf("    assign %s = %s;\n", target, expression)
+	}
+}
+
+// mux selects between the inputs other than sel, in alphabetical order.
+func mux(signature chips.Chip) string {
+	var inputs []string
+	for _, name := range slices.Sorted(maps.Keys(signature.Inputs)) {
+		if name != "sel" {
+			inputs = append(inputs, name)
+		}
+	}
+	selWidth := signature.Inputs["sel"].Width
+
+	var selectInput func(inputs []string, bit int) string
+	selectInput = func(inputs []string, bit int) string {
+		if len(inputs) == 1 {
+			return inputs[0]
+		}
+		half := len(inputs) / 2
+		return fmt.Sprintf("%s ? %s : %s",
+			selBit(bit, selWidth),
+			parenthesize(selectInput(inputs[half:], bit-1)),
+			parenthesize(selectInput(inputs[:half], bit-1)),
+		)
+	}
+	return fmt.Sprintf("    assign out = %s;\n", selectInput(inputs, selWidth-1))
+}
+
+// dmux routes in to the output selected by sel, the outputs in alphabetical order.
+func dmux(signature chips.Chip) string {
+	var sb strings.Builder
+	selWidth := signature.Inputs["sel"].Width
+	for i, name := range slices.Sorted(maps.Keys(signature.Outputs)) {
+		if selWidth == 1 && i == 0 {
+			sb.WriteString(fmt.Sprintf("    assign %s = in & ~sel;\n", name))
+		} else if selWidth == 1 {
+			sb.WriteString(fmt.Sprintf("    assign %s = in & sel;\n", name))
+		} else {
+			sb.WriteString(fmt.Sprintf("    assign %s = in & (sel == %d'd%d);\n", name, selWidth, i))
+		}
+	}
+	return sb.String()
+}
+
+func dff(signature chips.Chip) string {
+	return `    initial out = 1'b0;
+
+    always @(posedge clk) begin
+        out <= in;
+    end
+`
+}
+
+func register(signature chips.Chip) string {
+	return `    initial out = 0;
+
+    always @(posedge clk) begin
+        if (load) begin
+            out <= in;
+        end
+    end
+`
+}
+
+func pc(signature chips.Chip) string {
+	return `    initial out = 16'd0;
+
+    always @(posedge clk) begin
+        if (reset) begin
+            out <= 16'd0;
+        end else if (load) begin
+            out <= in;
+        end else if (inc) begin
+            out <= out + 16'd1;
+        end
+    end
+`
+}
+
+// ram reads asynchronously, like the built-in chip, and writes on the clock edge.
+func ram(signature chips.Chip) string {
+	size := 1 << signature.Inputs["address"].Width
+	return fmt.Sprintf(`    reg [15:0] memory [0:%d];
+    integer i;
+
+    initial begin
+        for (i = 0; i < %d; i = i + 1) begin
+            memory[i] = 16'd0;
+        end
+    end
+
+    assign out = memory[address];
+
+    always @(posedge clk) begin
+        if (load) begin
+            memory[address] <= in;
+        end
+    end
+`, size-1, size)
+}
+
+func selBit(bit int, width int) string {
+	if width == 1 {
+		return "sel"
+	}
+	return fmt.Sprintf("sel[%d]", bit)
+}
+
+func parenthesize(expression string) string {
+	if strings.Contains(expression, " ") {
+		return "(" + expression + ")"
+	}
+	return expression
+}
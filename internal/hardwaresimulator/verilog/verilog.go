@@ -0,0 +1,412 @@
+package verilog
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/resolver"
+)
+
+// clock is the port added to the modules of chips with clocked parts
+const (
+	clock           = "clk"
+	clockConnection = "." + clock + "(" + clock + ")"
+)
+
+// Translate returns synthesizable Verilog modules of the chips and of every
+// built-in chip they use, by module name. Chips become structural modules with
+// a part instance per part, built-in chips behavioural ones. Chips containing
+// clocked parts get a clk input, their state changes on its rising edge.
+func Translate(rchds map[string]*resolver.ResolvedChipDefinition, registry *chips.Registry) (map[string]string, error) {
+	t := &translator{
+		rchds:      rchds,
+		registry:   registry,
+		modules:    map[string]string{},
+		builtins:   map[string]bool{},
+		sequential: map[string]bool{},
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(rchds)) {
+		if err := t.translateChip(rchds[name]); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(t.builtins)) {
+		if err := t.translateBuiltin(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.modules, nil
+}
+
+type translator struct {
+	rchds      map[string]*resolver.ResolvedChipDefinition
+	registry   *chips.Registry
+	modules    map[string]string
+	builtins   map[string]bool // built-in chips used by the chips
+	sequential map[string]bool // chips known to have clocked parts, by chip name
+}
+
+// part returns the IO, the module name and whether the chip used as a part is
+// clocked. Like in the simulator, built-in chips take precedence over the chips
+// of the project, and chips declared with BUILTIN are their implementation.
+func (t *translator) part(name string) (chips.Chip, string, bool, error) {
+	if rchd, ok := t.rchds[name]; ok && rchd.BuiltinName != "" {
+		if _, isBuiltin := t.registry.Lookup(name); !isBuiltin {
+			name = rchd.BuiltinName
+		}
+	}
+
+	if builtin, ok := t.registry.Lookup(name); ok {
+		t.builtins[name] = true
+		return builtin.Signature(), t.builtinModuleName(name), builtin.Init() != nil, nil
+	}
+
+	rchd, ok := t.rchds[name]
+	if !ok {
+		return chips.Chip{}, "", false, fmt.Errorf("Chip '%s' is neither a built-in chip nor a custom chip", name)
+	}
+	sequential, err := t.isSequential(rchd)
+	if err != nil {
+		return chips.Chip{}, "", false, err
+	}
+	return chips.Chip{Inputs: rchd.Inputs, Outputs: rchd.Outputs}, identifier(rchd.Name), sequential, nil
+}
+
+func (t *translator) isSequential(rchd *resolver.ResolvedChipDefinition) (bool, error) {
+	if sequential, ok := t.sequential[rchd.Name]; ok {
+		return sequential, nil
+	}
+
+	sequential := false
+	if rchd.BuiltinName != "" {
+		builtin, ok := t.registry.Lookup(rchd.BuiltinName)
+		sequential = ok && builtin.Init() != nil
+	}
+	for _, part := range rchd.Parts {
+		_, _, partSequential, err := t.part(part.Name)
+		if err != nil {
+			return false, err
+		}
+		sequential = sequential || partSequential
+	}
+
+	t.sequential[rchd.Name] = sequential
+	return sequential, nil
+}
+
+// builtinModuleName is the name of the built-in chip, unless a chip of the
+// project has the same name, as the student implemented it.
+func (t *translator) builtinModuleName(name string) string {
+	if _, ok := t.rchds[name]; ok {
+		return name + "_builtin"
+	}
+	return identifier(name)
+}
+
+func (t *translator) translateChip(rchd *resolver.ResolvedChipDefinition) error {
+	sequential, err := t.isSequential(rchd)
+	if err != nil {
+		return err
+	}
+
+	if rchd.BuiltinName != "" {
+		// a chip declared with BUILTIN wraps the module of its implementation
+		_, moduleName, _, err := t.part(rchd.BuiltinName)
+		if err != nil {
+			return err
+		}
+		var connections []string
+		if sequential {
+			connections = append(connections, clockConnection)
+		}
+		for _, name := range sortedPins(rchd.Inputs, rchd.Outputs) {
+			connections = append(connections, connection(name, identifier(name)))
+		}
+
+		var sb strings.Builder
+		writeHeader(&sb, identifier(rchd.Name), chips.Chip{Inputs: rchd.Inputs, Outputs: rchd.Outputs}, sequential, false)
+		writeInstance(&sb, moduleName, "implementation", connections)
+		sb.WriteString("endmodule\n")
+		t.modules[identifier(rchd.Name)] = sb.String()
+		return nil
+	}
+
+	m := &module{rchd: rchd, driven: map[string][]bool{}}
+	for name, output := range rchd.Outputs {
+		m.driven[name] = make([]bool, output.Width)
+	}
+	for _, name := range slices.Sorted(maps.Keys(rchd.InternalSignals)) {
+		m.wires = append(m.wires, wire(identifier(name), rchd.InternalSignals[name].Width))
+	}
+
+	for i, part := range rchd.Parts {
+		if err := t.translatePart(m, i, part); err != nil {
+			return err
+		}
+	}
+
+	// bits of the outputs that no part drives read as 0 in the simulator
+	for _, name := range slices.Sorted(maps.Keys(m.driven)) {
+		for _, r := range undrivenRanges(m.driven[name]) {
+			target := signalRef(identifier(name), r, len(m.driven[name]))
+			m.assigns = append(m.assigns, fmt.Sprintf("assign %s = %s;", target, zero(r.End-r.Start+1)))
+		}
+	}
+
+	var sb strings.Builder
+	writeHeader(&sb, identifier(rchd.Name), chips.Chip{Inputs: rchd.Inputs, Outputs: rchd.Outputs}, sequential, false)
+	for _, w := range m.wires {
+		sb.WriteString("    " + w + "\n")
+	}
+	for _, instance := range m.instances {
+		sb.WriteString("\n" + instance)
+	}
+	if len(m.assigns) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, assign := range m.assigns {
+		sb.WriteString("    " + assign + "\n")
+	}
+	sb.WriteString("endmodule\n")
+	t.modules[identifier(rchd.Name)] = sb.String()
+	return nil
+}
+
+// module collects the declarations and statements of a chip's module.
+type module struct {
+	rchd      *resolver.ResolvedChipDefinition
+	wires     []string
+	instances []string
+	assigns   []string
+	driven    map[string][]bool // bits of the outputs driven by parts
+}
+
+func (t *translator) translatePart(m *module, index int, part resolver.Part) error {
+	signature, moduleName, sequential, err := t.part(part.Name)
+	if err != nil {
+		return err
+	}
+	instanceName := fmt.Sprintf("part%d", index)
+
+	var connections []string
+	if sequential {
+		connections = append(connections, clockConnection)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(signature.Inputs)) {
+		connections = append(connections, connection(name, m.inputExpression(part, name, signature.Inputs[name].Width)))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(signature.Outputs)) {
+		width := signature.Outputs[name].Width
+		var outputConnections []resolver.Connection
+		for _, c := range part.OutputConnections {
+			if c.Pin.Name == name {
+				if _, isInternal := m.rchd.InternalSignals[c.Signal.Name]; isInternal {
+					// internal signals always take the whole connected range of the pin
+					c.Signal.Range = resolver.Range{Start: 0, End: c.Pin.Range.End - c.Pin.Range.Start}
+				}
+				outputConnections = append(outputConnections, c)
+				m.markDriven(c.Signal)
+			}
+		}
+
+		switch {
+		case len(outputConnections) == 0:
+			connections = append(connections, connection(name, ""))
+		case len(outputConnections) == 1 && outputConnections[0].Pin.Range == resolver.Range{Start: 0, End: width - 1}:
+			c := outputConnections[0]
+			connections = append(connections, connection(name, signalRef(identifier(c.Signal.Name), c.Signal.Range, m.width(c.Signal.Name))))
+		default:
+			// the pin drives several signals or parts of signals through a wire of its own
+			wireName := instanceName + "_" + name
+			m.wires = append(m.wires, wire(wireName, width))
+			connections = append(connections, connection(name, wireName))
+			for _, c := range outputConnections {
+				target := signalRef(identifier(c.Signal.Name), c.Signal.Range, m.width(c.Signal.Name))
+				m.assigns = append(m.assigns, fmt.Sprintf("assign %s = %s;", target, signalRef(wireName, c.Pin.Range, width)))
+			}
+		}
+	}
+
+	var sb strings.Builder
+	writeInstance(&sb, moduleName, instanceName, connections)
+	m.instances = append(m.instances, sb.String())
+	return nil
+}
+
+// inputExpression concatenates the signals connected to the bits of the input
+// pin of a part, the bits left unconnected are 0.
+func (m *module) inputExpression(part resolver.Part, pin string, width int) string {
+	var connections []resolver.Connection
+	for _, c := range part.InputConnections {
+		if c.Pin.Name == pin {
+			connections = append(connections, c)
+		}
+	}
+	slices.SortFunc(connections, func(a, b resolver.Connection) int {
+		return a.Pin.Range.Start - b.Pin.Range.Start
+	})
+
+	var pieces []string // least significant first
+	next := 0
+	for _, c := range connections {
+		if c.Pin.Range.Start > next {
+			pieces = append(pieces, zero(c.Pin.Range.Start-next))
+		}
+		pieces = append(pieces, m.signalExpression(c.Signal))
+		next = c.Pin.Range.End + 1
+	}
+	if next < width {
+		pieces = append(pieces, zero(width-next))
+	}
+
+	if len(pieces) == 1 {
+		return pieces[0]
+	}
+	slices.Reverse(pieces)
+	return "{" + strings.Join(pieces, ", ") + "}"
+}
+
+func (m *module) signalExpression(signal resolver.Signal) string {
+	width := signal.Range.End - signal.Range.Start + 1
+	switch {
+	case signal.IsNumericConstant:
+		var bits strings.Builder
+		for i := width - 1; i >= 0; i-- {
+			if i < 64 && signal.Value>>i&1 == 1 {
+				bits.WriteByte('1')
+			} else {
+				bits.WriteByte('0')
+			}
+		}
+		return fmt.Sprintf("%d'b%s", width, bits.String())
+	case signal.Name == "true" && width == 1:
+		return "1'b1"
+	case signal.Name == "true":
+		return fmt.Sprintf("{%d{1'b1}}", width)
+	case signal.Name == "false":
+		return zero(width)
+	}
+	return signalRef(identifier(signal.Name), signal.Range, m.width(signal.Name))
+}
+
+func (m *module) markDriven(signal resolver.Signal) {
+	if bits, ok := m.driven[signal.Name]; ok {
+		for i := signal.Range.Start; i <= signal.Range.End; i++ {
+			bits[i] = true
+		}
+	}
+}
+
+func (m *module) width(signal string) int {
+	if input, ok := m.rchd.Inputs[signal]; ok {
+		return input.Width
+	}
+	if output, ok := m.rchd.Outputs[signal]; ok {
+		return output.Width
+	}
+	return m.rchd.InternalSignals[signal].Width
+}
+
+func undrivenRanges(driven []bool) []resolver.Range {
+	var ranges []resolver.Range
+	for i, isDriven := range driven {
+		if isDriven {
+			continue
+		}
+		if len(ranges) > 0 && ranges[len(ranges)-1].End == i-1 {
+			ranges[len(ranges)-1].End = i
+		} else {
+			ranges = append(ranges, resolver.Range{Start: i, End: i})
+		}
+	}
+	return ranges
+}
+
+// writeHeader writes the module declaration with the clock first, then the
+// inputs and outputs in alphabetical order. regOutputs declares the outputs as
+// registers, for behavioural modules assigning them in always blocks.
+func writeHeader(sb *strings.Builder, name string, signature chips.Chip, sequential bool, regOutputs bool) {
+	var ports []string
+	if sequential {
+		ports = append(ports, "input "+clock)
+	}
+	for _, pin := range slices.Sorted(maps.Keys(signature.Inputs)) {
+		ports = append(ports, "input "+vector(signature.Inputs[pin].Width)+identifier(pin))
+	}
+	output := "output "
+	if regOutputs {
+		output = "output reg "
+	}
+	for _, pin := range slices.Sorted(maps.Keys(signature.Outputs)) {
+		ports = append(ports, output+vector(signature.Outputs[pin].Width)+identifier(pin))
+	}
+
+	sb.WriteString(fmt.Sprintf("module %s (\n", name))
+	sb.WriteString("    " + strings.Join(ports, ",\n    ") + "\n")
+	sb.WriteString(");\n")
+}
+
+func writeInstance(sb *strings.Builder, moduleName string, instanceName string, connections []string) {
+	sb.WriteString(fmt.Sprintf("    %s %s (\n", moduleName, instanceName))
+	sb.WriteString("        " + strings.Join(connections, ",\n        ") + "\n")
+	sb.WriteString("    );\n")
+}
+
+func connection(pin string, expression string) string {
+	return fmt.Sprintf(".%s(%s)", identifier(pin), expression)
+}
+
+func wire(name string, width int) string {
+	return fmt.Sprintf("wire %s%s;", vector(width), name)
+}
+
+func vector(width int) string {
+	if width == 1 {
+		return ""
+	}
+	return fmt.Sprintf("[%d:0] ", width-1)
+}
+
+func signalRef(name string, r resolver.Range, width int) string {
+	switch {
+	case r.Start == 0 && r.End == width-1:
+		return name
+	case r.Start == r.End:
+		return fmt.Sprintf("%s[%d]", name, r.Start)
+	default:
+		return fmt.Sprintf("%s[%d:%d]", name, r.End, r.Start)
+	}
+}
+
+func zero(width int) string {
+	return fmt.Sprintf("%d'b0", width)
+}
+
+func sortedPins(inputs, outputs map[string]chips.IO) []string {
+	return append(slices.Sorted(maps.Keys(inputs)), slices.Sorted(maps.Keys(outputs))...)
+}
+
+// keywords of Verilog that are valid HDL names
+var keywords = map[string]bool{
+	"always": true, "assign": true, "begin": true, "case": true, "default": true,
+	"else": true, "end": true, "endcase": true, "endmodule": true, "for": true,
+	"if": true, "initial": true, "inout": true, "input": true, "integer": true,
+	"module": true, "negedge": true, "output": true, "parameter": true,
+	"posedge": true, "reg": true, "wire": true, "clk": true,
+}
+
+// identifier renames the names that are Verilog keywords, and clk, which is
+// taken by the clock.
+func identifier(name string) string {
+	if keywords[name] {
+		return name + "_"
+	}
+	return name
+}
@@ -0,0 +1,260 @@
+package verilog
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslate(t *testing.T) {
+	hdls := map[string]string{
+		"Split": `CHIP Split {
+	IN a[16], b, wire;
+	OUT out[8], c, d[4];
+
+	PARTS:
+	Or8Way(in = a[8..15], out = o);
+	And(a = o, b = true, out = c, out = x);
+	Mux16(a = a, b[0..3] = a[4..7], b[4] = x, b[5..7] = 5, sel = wire, out[0..7] = out, out[8..9] = d[1..2]);
+	Bit(in = b, load = true);
+}`,
+	}
+
+	modules, err := Translate(mustResolve(t, hdls, "Split"), chips.NewDefaultRegistry())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"Split", "Or8Way", "And", "Mux16", "Bit"}, slices.Collect(maps.Keys(modules)))
+	assert.Equal(t, `module Split (
+    input clk,
+    input [15:0] a,
+    input b,
+    input wire_,
+    output c,
+    output [3:0] d,
+    output [7:0] out
+);
+    wire o;
+    wire x;
+    wire part1_out;
+    wire [15:0] part2_out;
+
+    Or8Way part0 (
+        .in(a[15:8]),
+        .out(o)
+    );
+
+    And part1 (
+        .a(o),
+        .b(1'b1),
+        .out(part1_out)
+    );
+
+    Mux16 part2 (
+        .a(a),
+        .b({8'b0, 3'b101, x, a[7:4]}),
+        .sel(wire_),
+        .out(part2_out)
+    );
+
+    Bit part3 (
+        .clk(clk),
+        .in(b),
+        .load(1'b1),
+        .out()
+    );
+
+    assign c = part1_out;
+    assign x = part1_out;
+    assign out = part2_out[7:0];
+    assign d[2:1] = part2_out[9:8];
+    assign d[0] = 1'b0;
+    assign d[3] = 1'b0;
+endmodule
+`, modules["Split"])
+	assert.Equal(t, `module Bit (
+    input clk,
+    input in,
+    input load,
+    output reg out
+);
+    initial out = 0;
+
+    always @(posedge clk) begin
+        if (load) begin
+            out <= in;
+        end
+    end
+endmodule
+`, modules["Bit"])
+}
+
+func TestTranslateBuiltins(t *testing.T) {
+	hdls := map[string]string{
+		"All": `CHIP All {
+	IN a[16], s[3], x;
+	OUT o[16];
+
+	PARTS:
+	Mux4Way16(a = a, b = a, c = a, d = a, sel = s[0..1]);
+	Mux8Way16(a = a, b = a, c = a, d = a, e = a, f = a, g = a, h = a, sel = s, out = o);
+	DMux4Way(in = x, sel = s[0..1]);
+	DFF(in = x, out = q);
+	RAM8(in = a, load = x, address = s);
+}`,
+	}
+
+	modules, err := Translate(mustResolve(t, hdls, "All"), chips.NewDefaultRegistry())
+	require.NoError(t, err)
+
+	assert.Contains(t, modules["Mux4Way16"], "    assign out = sel[1] ? (sel[0] ? d : c) : (sel[0] ? b : a);\n")
+	assert.Contains(t, modules["Mux8Way16"], "    assign out = sel[2] ? (sel[1] ? (sel[0] ? h : g) : (sel[0] ? f : e)) : (sel[1] ? (sel[0] ? d : c) : (sel[0] ? b : a));\n")
+	assert.Contains(t, modules["DMux4Way"], `    assign a = in & (sel == 2'd0);
+    assign b = in & (sel == 2'd1);
+    assign c = in & (sel == 2'd2);
+    assign d = in & (sel == 2'd3);
+`)
+	assert.Equal(t, `module DFF (
+    input clk,
+    input in,
+    output reg out
+);
+    initial out = 1'b0;
+
+    always @(posedge clk) begin
+        out <= in;
+    end
+endmodule
+`, modules["DFF"])
+	assert.Contains(t, modules["RAM8"], "    reg [15:0] memory [0:7];\n")
+	assert.Contains(t, modules["RAM8"], "    input [2:0] address,\n")
+}
+
+func TestTranslateClock(t *testing.T) {
+	hdls := map[string]string{
+		"Outer": `CHIP Outer {
+	IN in;
+	OUT out;
+
+	PARTS:
+	Inner(in = in, out = stored);
+	Not(in = stored, out = out);
+}`,
+		"Inner": `CHIP Inner {
+	IN in;
+	OUT out;
+
+	PARTS:
+	DFF(in = in, out = out);
+}`,
+		"Comb": `CHIP Comb {
+	IN in;
+	OUT out;
+
+	PARTS:
+	Not(in = in, out = out);
+}`,
+	}
+
+	modules, err := Translate(mustResolve(t, hdls, "Outer", "Comb"), chips.NewDefaultRegistry())
+	require.NoError(t, err)
+
+	assert.Contains(t, modules["Outer"], "    input clk,\n")
+	assert.Contains(t, modules["Outer"], "    Inner part0 (\n        .clk(clk),\n")
+	assert.Contains(t, modules["Inner"], "    input clk,\n")
+	assert.NotContains(t, modules["Comb"], "clk")
+	assert.NotContains(t, modules["Not"], "clk")
+}
+
+func TestTranslateChipsNamedLikeBuiltins(t *testing.T) {
+	hdls := map[string]string{
+		"Xor": `CHIP Xor {
+	IN a, b;
+	OUT out;
+
+	PARTS:
+	Or(a = a, b = b, out = or);
+	Nand(a = a, b = b, out = nand);
+	And(a = or, b = nand, out = out);
+}`,
+		"Parity": `CHIP Parity {
+	IN a, b, c;
+	OUT out;
+
+	PARTS:
+	Xor(a = a, b = b, out = ab);
+	Xor(a = ab, b = c, out = out);
+}`,
+	}
+
+	modules, err := Translate(mustResolve(t, hdls, "Xor", "Parity"), chips.NewDefaultRegistry())
+	require.NoError(t, err)
+
+	// parts use the built-in chip, like in the simulator
+	assert.Contains(t, modules["Parity"], "    Xor_builtin part0 (\n")
+	assert.Contains(t, modules["Xor_builtin"], "module Xor_builtin (\n")
+	assert.Contains(t, modules["Xor"], "module Xor (\n")
+	assert.Contains(t, modules["Xor"], "    Or part0 (\n")
+}
+
+func TestTranslateErrors(t *testing.T) {
+	registry := chips.NewDefaultRegistry()
+	require.NoError(t, registry.Register(chips.NewRegister("ARegister", "A register", 16)))
+	hdls := map[string]string{
+		"UsesA": `CHIP UsesA {
+	IN in[16];
+	OUT out[16];
+
+	PARTS:
+	ARegister(in = in, load = true, out = out);
+}`,
+	}
+
+	l := lexer.New(hdls["UsesA"])
+	ts, err := l.Tokenize()
+	require.NoError(t, err)
+	chd, err := parser.New(ts).ParseChipDefinition()
+	require.NoError(t, err)
+	rchd, rchds, err := resolver.New(chd, "UsesA", hdls, registry).Resolve([]string{}, []string{})
+	require.NoError(t, err)
+	rchds[rchd.Name] = rchd
+
+	_, err = Translate(rchds, registry)
+	assert.EqualError(t, err, "Built-in chip 'ARegister' cannot be translated to Verilog")
+}
+
+func mustResolve(t *testing.T, hdls map[string]string, chipNames ...string) map[string]*resolver.ResolvedChipDefinition {
+	t.Helper()
+
+	rchds := map[string]*resolver.ResolvedChipDefinition{}
+	for _, chipName := range chipNames {
+		l := lexer.New(hdls[chipName])
+		ts, err := l.Tokenize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p := parser.New(ts)
+		chd, err := p.ParseChipDefinition()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := resolver.New(chd, chipName, hdls, chips.NewDefaultRegistry())
+		rchd, used, err := r.Resolve([]string{}, []string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, usedRchd := range used {
+			rchds[name] = usedRchd
+		}
+		rchds[rchd.Name] = rchd
+	}
+	return rchds
+}
@@ -17,4 +17,6 @@ const (
 const (
 	ConstraintNameUsersUniqueEmail    = "users_unique_constraint_email"
 	ConstraintNameUsersUniqueUsername = "users_unique_constraint_username"
+
+	ConstraintNameClassroomsUniqueInviteCode = "classrooms_unique_constraint_invite_code"
 )
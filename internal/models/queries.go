@@ -32,6 +32,9 @@ type DBQueries interface {
 	InvalidateEmailVerificationRequestsOfUser(ctx context.Context, arg InvalidateEmailVerificationRequestsOfUserParams) error
 
 	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	CreateProjectWithUniqueTitle(ctx context.Context, arg CreateProjectWithUniqueTitleParams) (Project, error)
+	DuplicateProject(ctx context.Context, arg DuplicateProjectParams) (Project, error)
+	SkipDefaultChips(ctx context.Context) error
 	DeleteProject(ctx context.Context, arg DeleteProjectParams) (Project, error)
 	GetPaginatedProjects(ctx context.Context, arg GetPaginatedProjectsParams) ([]Project, error)
 	GetProject(ctx context.Context, arg GetProjectParams) (Project, error)
@@ -41,8 +44,55 @@ type DBQueries interface {
 	IsProjectOwnedByUser(ctx context.Context, arg IsProjectOwnedByUserParams) (bool, error)
 
 	CreateChip(ctx context.Context, arg CreateChipParams) (Chip, error)
+	CreateChipWithHdl(ctx context.Context, arg CreateChipWithHdlParams) (Chip, error)
 	DeleteChip(ctx context.Context, id int32) (Chip, error)
 	GetChipsByProject(ctx context.Context, projectID int32) ([]Chip, error)
 	UpdateChip(ctx context.Context, arg UpdateChipParams) (Chip, error)
 	GetChip(ctx context.Context, arg GetChipParams) (Chip, error)
+	CopyChips(ctx context.Context, arg CopyChipsParams) error
+
+	CreateChipRevision(ctx context.Context, arg CreateChipRevisionParams) (ChipRevision, error)
+	GetChipRevision(ctx context.Context, arg GetChipRevisionParams) (ChipRevision, error)
+	GetLatestChipRevision(ctx context.Context, chipID int32) (ChipRevision, error)
+	GetChipRevisions(ctx context.Context, chipID int32) ([]ChipRevision, error)
+	DeleteOldChipRevisions(ctx context.Context, arg DeleteOldChipRevisionsParams) error
+
+	CreateProjectShare(ctx context.Context, arg CreateProjectShareParams) (ProjectShare, error)
+	GetProjectShares(ctx context.Context, projectID int32) ([]ProjectShare, error)
+	DeleteProjectShare(ctx context.Context, arg DeleteProjectShareParams) (ProjectShare, error)
+	GetSharedProject(ctx context.Context, token string) (GetSharedProjectRow, error)
+
+	CreateClassroom(ctx context.Context, arg CreateClassroomParams) (Classroom, error)
+	GetClassroomByInviteCode(ctx context.Context, inviteCode string) (Classroom, error)
+	GetClassroomOfMember(ctx context.Context, arg GetClassroomOfMemberParams) (GetClassroomOfMemberRow, error)
+	GetClassroomsOfMember(ctx context.Context, userID int32) ([]GetClassroomsOfMemberRow, error)
+	AddClassroomMember(ctx context.Context, arg AddClassroomMemberParams) (ClassroomMember, error)
+	GetClassroomMembers(ctx context.Context, classroomID int32) ([]GetClassroomMembersRow, error)
+	UpdateClassroomMemberRole(ctx context.Context, arg UpdateClassroomMemberRoleParams) (ClassroomMember, error)
+
+	CreateAssignment(ctx context.Context, arg CreateAssignmentParams) (Assignment, error)
+	GetAssignment(ctx context.Context, arg GetAssignmentParams) (Assignment, error)
+	GetAssignmentsByClassroom(ctx context.Context, classroomID int32) ([]Assignment, error)
+	GetAssignmentsOfStudent(ctx context.Context, arg GetAssignmentsOfStudentParams) ([]GetAssignmentsOfStudentRow, error)
+	CreateAssignmentSubmission(ctx context.Context, arg CreateAssignmentSubmissionParams) (AssignmentSubmission, error)
+	GetAssignmentSubmission(ctx context.Context, arg GetAssignmentSubmissionParams) (AssignmentSubmission, error)
+	GetAssignmentSubmissions(ctx context.Context, assignmentID int32) ([]GetAssignmentSubmissionsRow, error)
+
+	CreateAssignmentTest(ctx context.Context, arg CreateAssignmentTestParams) (AssignmentTest, error)
+	GetAssignmentTests(ctx context.Context, assignmentID int32) ([]AssignmentTest, error)
+	DeleteAssignmentTest(ctx context.Context, arg DeleteAssignmentTestParams) (AssignmentTest, error)
+	QueueGradingRuns(ctx context.Context, arg QueueGradingRunsParams) ([]GradingRun, error)
+	ClaimGradingRun(ctx context.Context) (GradingRun, error)
+	RequeueRunningGradingRuns(ctx context.Context) error
+	FinishGradingRun(ctx context.Context, arg FinishGradingRunParams) (GradingRun, error)
+	CreateGradingResult(ctx context.Context, arg CreateGradingResultParams) error
+	GetLatestGradingRun(ctx context.Context, arg GetLatestGradingRunParams) (GradingRun, error)
+	GetGradingResults(ctx context.Context, runID int32) ([]GradingResult, error)
+	GetLatestGradingResultsOfAssignment(ctx context.Context, assignmentID int32) ([]GetLatestGradingResultsOfAssignmentRow, error)
+
+	CreateApiToken(ctx context.Context, arg CreateApiTokenParams) (ApiToken, error)
+	GetApiTokensOfUser(ctx context.Context, userID int32) ([]ApiToken, error)
+	GetApiTokenByLookupId(ctx context.Context, lookupID string) (ApiToken, error)
+	UpdateApiTokenLastUsed(ctx context.Context, id int32) error
+	DeleteApiToken(ctx context.Context, arg DeleteApiTokenParams) (ApiToken, error)
 }
@@ -0,0 +1,220 @@
+// Package projectarchive reads and writes projects as zip archives of HDL
+// files, the way the nand2tetris software suite keeps them on disk.
+package projectarchive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/lexer"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/parser"
+)
+
+// MetadataFileName is the name of the file holding the metadata of the project.
+const MetadataFileName = "project.json"
+
+// An archive is read only up to these limits, so that a small upload cannot
+// unpack to an arbitrary amount of data.
+const (
+	MaxChips    = 500
+	MaxHdlSize  = 1 << 20
+	maxMetaSize = 64 << 10
+)
+
+var chipNameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]{1,99}$`)
+
+// Error is returned when an archive is not a valid project.
+type Error struct {
+	File    string // the file in the archive the error is about, empty if it is about the archive
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return "invalid project archive: " + e.Message
+	}
+	return fmt.Sprintf("invalid project archive: %s: %s", e.File, e.Message)
+}
+
+type Metadata struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type Chip struct {
+	Name string
+	Hdl  string
+}
+
+type Archive struct {
+	Metadata *Metadata // nil if the archive has no metadata file
+	Chips    []Chip    // sorted by name
+}
+
+// Write writes the project as a zip archive to w, the metadata in
+// MetadataFileName and every chip in <ChipName>.hdl at the top level.
+func Write(w io.Writer, metadata Metadata, chips []Chip) error {
+	zw := zip.NewWriter(w)
+
+	f, err := zw.Create(MetadataFileName)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(metadata); err != nil {
+		return err
+	}
+
+	for _, chip := range chips {
+		f, err := zw.Create(chip.Name + ".hdl")
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, chip.Hdl); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// Read reads a project from a zip archive of size bytes. The .hdl files are
+// collected from every folder of the archive, so both the archives written by
+// Write and the projects/01…05 folders of the nand2tetris software suite can
+// be read; other files, such as test scripts, are skipped. Every chip has to
+// be named after its file.
+func Read(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, &Error{Message: err.Error()}
+	}
+
+	archive := &Archive{}
+	files := map[string]string{}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || skipped(f.Name) {
+			continue
+		}
+
+		base := path.Base(f.Name)
+		ext := path.Ext(base)
+
+		if base == MetadataFileName {
+			if archive.Metadata != nil {
+				return nil, &Error{File: f.Name, Message: "more than one metadata file"}
+			}
+			content, err := readFile(f, maxMetaSize)
+			if err != nil {
+				return nil, err
+			}
+			var metadata Metadata
+			if err := json.Unmarshal(content, &metadata); err != nil {
+				return nil, &Error{File: f.Name, Message: err.Error()}
+			}
+			archive.Metadata = &metadata
+			continue
+		}
+
+		if !strings.EqualFold(ext, ".hdl") {
+			continue
+		}
+
+		name := strings.TrimSuffix(base, ext)
+		if other, ok := files[name]; ok {
+			return nil, &Error{File: f.Name, Message: fmt.Sprintf("chip %s is also defined in %s", name, other)}
+		}
+		if len(archive.Chips) == MaxChips {
+			return nil, &Error{Message: fmt.Sprintf("more than %d chips", MaxChips)}
+		}
+
+		chip, err := readChip(f, name)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = f.Name
+		archive.Chips = append(archive.Chips, chip)
+	}
+
+	slices.SortFunc(archive.Chips, func(a, b Chip) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return archive, nil
+}
+
+// readChip reads the chip from the file, checking that the chip is named after the file.
+func readChip(f *zip.File, name string) (Chip, error) {
+	if !chipNameRegexp.MatchString(name) {
+		return Chip{}, &Error{
+			File:    f.Name,
+			Message: "chip name must be 2 to 100 letters and digits, starting with a letter",
+		}
+	}
+
+	content, err := readFile(f, MaxHdlSize)
+	if err != nil {
+		return Chip{}, err
+	}
+	hdl := string(content)
+
+	ts, err := lexer.New(hdl).Tokenize()
+	if err != nil {
+		return Chip{}, &Error{File: f.Name, Message: err.Error()}
+	}
+	// only the header is parsed, as the chips of the nand2tetris projects
+	// start out without parts
+	chipName, err := parser.New(ts).ParseChipHeader()
+	if err != nil {
+		return Chip{}, &Error{File: f.Name, Message: err.Error()}
+	}
+	if chipName.Name != name {
+		return Chip{}, &Error{
+			File:    f.Name,
+			Message: fmt.Sprintf("chip %s must be in %s.hdl", chipName.Name, chipName.Name),
+		}
+	}
+
+	return Chip{Name: name, Hdl: hdl}, nil
+}
+
+func readFile(f *zip.File, limit int64) ([]byte, error) {
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, &Error{File: f.Name, Message: fmt.Sprintf("file is larger than %d bytes", limit)}
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, &Error{File: f.Name, Message: err.Error()}
+	}
+	defer rc.Close()
+
+	// the size in the header is not trusted, the file is read one byte past
+	// the limit to tell whether it is larger
+	content, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, &Error{File: f.Name, Message: err.Error()}
+	}
+	if int64(len(content)) > limit {
+		return nil, &Error{File: f.Name, Message: fmt.Sprintf("file is larger than %d bytes", limit)}
+	}
+	return content, nil
+}
+
+// skipped tells whether the file is metadata of an operating system, such as
+// the __MACOSX folder or .DS_Store files, rather than part of the project.
+func skipped(name string) bool {
+	for part := range strings.SplitSeq(name, "/") {
+		if part == "__MACOSX" || (strings.HasPrefix(part, ".") && part != "." && part != "..") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package projectarchive
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const notHdl = `// Not gate
+CHIP Not {
+    IN in;
+    OUT out;
+
+    PARTS:
+    Nand(a = in, b = in, out = out);
+}`
+
+const add16Hdl = "CHIP Add16 {\r\n    IN a[16], b[16];\r\n    OUT out[16];\r\n\r\n    PARTS:\r\n    //// Replace this comment with your code.\r\n}\r\n"
+
+func zipFiles(t *testing.T, files [][2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		f, err := zw.Create(file[0])
+		require.NoError(t, err)
+		_, err = f.Write([]byte(file[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestWriteRead(t *testing.T) {
+	metadata := Metadata{Title: "Project 1", Description: "Boolean logic"}
+	chips := []Chip{{Name: "Add16", Hdl: add16Hdl}, {Name: "Not", Hdl: notHdl}}
+
+	var buf bytes.Buffer
+	err := Write(&buf, metadata, chips)
+	require.NoError(t, err)
+
+	archive, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, &metadata, archive.Metadata)
+	assert.Equal(t, chips, archive.Chips)
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    [][2]string
+		expected *Archive
+	}{
+		{
+			name: "nand2tetris project folders",
+			files: [][2]string{
+				{"projects/02/Add16.hdl", add16Hdl},
+				{"projects/02/Add16.tst", "load Add16.hdl;"},
+				{"projects/02/Add16.cmp", "|        a         |"},
+				{"projects/01/Not.hdl", notHdl},
+				{"__MACOSX/projects/01/._Not.hdl", "\x00\x05\x16\x07"},
+				{"projects/.DS_Store", "\x00"},
+			},
+			expected: &Archive{
+				Chips: []Chip{{Name: "Add16", Hdl: add16Hdl}, {Name: "Not", Hdl: notHdl}},
+			},
+		},
+		{
+			name:     "No chips",
+			files:    [][2]string{{"README.md", "# Project"}},
+			expected: &Archive{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := zipFiles(t, tt.files)
+			archive, err := Read(bytes.NewReader(content), int64(len(content)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, archive)
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    [][2]string
+		expected string
+	}{
+		{
+			name:     "Chip not named after its file",
+			files:    [][2]string{{"projects/01/Inverter.hdl", notHdl}},
+			expected: "invalid project archive: projects/01/Inverter.hdl: chip Not must be in Not.hdl",
+		},
+		{
+			name: "Chip in two folders",
+			files: [][2]string{
+				{"projects/01/Not.hdl", notHdl},
+				{"projects/02/Not.hdl", notHdl},
+			},
+			expected: "invalid project archive: projects/02/Not.hdl: chip Not is also defined in projects/01/Not.hdl",
+		},
+		{
+			name:     "Invalid chip name",
+			files:    [][2]string{{"1Not.hdl", notHdl}},
+			expected: "invalid project archive: 1Not.hdl: chip name must be 2 to 100 letters and digits, starting with a letter",
+		},
+		{
+			name:     "Invalid metadata",
+			files:    [][2]string{{"project.json", "{"}},
+			expected: "invalid project archive: project.json: unexpected end of JSON input",
+		},
+		{
+			name:     "Metadata twice",
+			files:    [][2]string{{"project.json", "{}"}, {"copy/project.json", "{}"}},
+			expected: "invalid project archive: copy/project.json: more than one metadata file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := zipFiles(t, tt.files)
+			_, err := Read(bytes.NewReader(content), int64(len(content)))
+			assert.EqualError(t, err, tt.expected)
+			assert.IsType(t, &Error{}, err)
+		})
+	}
+
+	t.Run("Chip without a header", func(t *testing.T) {
+		content := zipFiles(t, [][2]string{{"Not.hdl", "Not {\n    IN in;\n}"}})
+		_, err := Read(bytes.NewReader(content), int64(len(content)))
+		var archiveErr *Error
+		require.ErrorAs(t, err, &archiveErr)
+		assert.Equal(t, "Not.hdl", archiveErr.File)
+	})
+
+	t.Run("Not a zip", func(t *testing.T) {
+		content := []byte("CHIP Not {}")
+		_, err := Read(bytes.NewReader(content), int64(len(content)))
+		assert.IsType(t, &Error{}, err)
+	})
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/crypto"
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/bauerbrun0/nand2tetris-web/ui/pages"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrInvalidApiToken  = errors.New("apitokenservice: invalid or expired api token")
+	ErrApiTokenNotFound = errors.New("apitokenservice: api token not found")
+)
+
+// apiTokenLastUsedInterval is how often the time a token was last used is
+// updated, so that not every request with a token writes to the database.
+const apiTokenLastUsedInterval = 5 * time.Minute
+
+type ApiTokenService interface {
+	// CreateApiToken creates a token which expires after the duration, or
+	// never if it is 0. The token is returned only here, as just its hash is
+	// stored.
+	CreateApiToken(userId int32, name string, scope pages.ApiTokenScope, expiresIn time.Duration) (string, *pages.ApiToken, error)
+	GetApiTokens(userId int32) ([]pages.ApiToken, error)
+	RevokeApiToken(userId int32, tokenId int32) error
+	// AuthenticateApiToken returns the id of the user the token belongs to and
+	// its scope, or ErrInvalidApiToken if the token is unknown or expired. The
+	// time it was last used is updated at most every apiTokenLastUsedInterval.
+	AuthenticateApiToken(token string) (int32, pages.ApiTokenScope, error)
+}
+
+type apiTokenService struct {
+	logger    *slog.Logger
+	ctx       context.Context
+	queries   models.DBQueries
+	txStarter models.TxStarter
+}
+
+func NewApiTokenService(
+	logger *slog.Logger,
+	ctx context.Context,
+	queries models.DBQueries,
+	txStarter models.TxStarter,
+) ApiTokenService {
+	return &apiTokenService{
+		logger:    logger,
+		ctx:       ctx,
+		queries:   queries,
+		txStarter: txStarter,
+	}
+}
+
+func (s *apiTokenService) CreateApiToken(
+	userId int32,
+	name string,
+	scope pages.ApiTokenScope,
+	expiresIn time.Duration,
+) (string, *pages.ApiToken, error) {
+	token, lookupId, secret := crypto.GenerateApiToken()
+
+	var hasher crypto.PasswordHasher
+	secretHash, err := hasher.GenerateFromPassword(secret, crypto.DefaultApiTokenHashParams)
+	if err != nil {
+		return "", nil, err
+	}
+
+	params := models.CreateApiTokenParams{
+		UserID:     userId,
+		Name:       name,
+		LookupID:   lookupId,
+		SecretHash: secretHash,
+		Scope:      string(scope),
+	}
+	if expiresIn > 0 {
+		params.Expires = pgtype.Timestamptz{Time: time.Now().Add(expiresIn), Valid: true}
+	}
+
+	apiToken, err := s.queries.CreateApiToken(s.ctx, params)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, toApiToken(apiToken), nil
+}
+
+func (s *apiTokenService) GetApiTokens(userId int32) ([]pages.ApiToken, error) {
+	apiTokens, err := s.queries.GetApiTokensOfUser(s.ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]pages.ApiToken, 0, len(apiTokens))
+	for _, apiToken := range apiTokens {
+		result = append(result, *toApiToken(apiToken))
+	}
+	return result, nil
+}
+
+func (s *apiTokenService) RevokeApiToken(userId int32, tokenId int32) error {
+	_, err := s.queries.DeleteApiToken(s.ctx, models.DeleteApiTokenParams{
+		ID:     tokenId,
+		UserID: userId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrApiTokenNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *apiTokenService) AuthenticateApiToken(token string) (int32, pages.ApiTokenScope, error) {
+	lookupId, secret, ok := crypto.ParseApiToken(token)
+	if !ok {
+		return 0, "", ErrInvalidApiToken
+	}
+
+	apiToken, err := s.queries.GetApiTokenByLookupId(s.ctx, lookupId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, "", ErrInvalidApiToken
+		}
+		return 0, "", err
+	}
+
+	var hasher crypto.PasswordHasher
+	ok, err = hasher.ComparePasswordAndHash(secret, apiToken.SecretHash)
+	if err != nil {
+		return 0, "", err
+	}
+	if !ok {
+		return 0, "", ErrInvalidApiToken
+	}
+
+	if !apiToken.LastUsed.Valid || time.Since(apiToken.LastUsed.Time) >= apiTokenLastUsedInterval {
+		err = s.queries.UpdateApiTokenLastUsed(s.ctx, apiToken.ID)
+		if err != nil {
+			return 0, "", err
+		}
+	}
+
+	return apiToken.UserID, pages.ApiTokenScope(apiToken.Scope), nil
+}
+
+func toApiToken(apiToken models.ApiToken) *pages.ApiToken {
+	result := &pages.ApiToken{
+		ID:      apiToken.ID,
+		Name:    apiToken.Name,
+		Scope:   pages.ApiTokenScope(apiToken.Scope),
+		Created: apiToken.Created.Time,
+	}
+	if apiToken.Expires.Valid {
+		expires := apiToken.Expires.Time
+		result.Expires = &expires
+	}
+	if apiToken.LastUsed.Valid {
+		lastUsed := apiToken.LastUsed.Time
+		result.LastUsed = &lastUsed
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/crypto"
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/bauerbrun0/nand2tetris-web/internal/models/mocks"
+	"github.com/bauerbrun0/nand2tetris-web/ui/pages"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticateApiToken(t *testing.T) {
+	token, lookupId, secret := crypto.GenerateApiToken()
+	var hasher crypto.PasswordHasher
+	secretHash, err := hasher.GenerateFromPassword(secret, crypto.DefaultApiTokenHashParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiTokenUsedAt := func(lastUsed pgtype.Timestamptz) models.ApiToken {
+		return models.ApiToken{
+			ID:         1,
+			UserID:     2,
+			LookupID:   lookupId,
+			SecretHash: secretHash,
+			Scope:      string(pages.ApiTokenScopeRead),
+			LastUsed:   lastUsed,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		apiToken       models.ApiToken
+		lookupErr      error
+		wantLastUsed   bool
+		wantErr        error
+		wantUserId     int32
+		skipLookupCall bool
+	}{
+		{
+			name:         "Never used",
+			token:        token,
+			apiToken:     apiTokenUsedAt(pgtype.Timestamptz{}),
+			wantLastUsed: true,
+			wantUserId:   2,
+		},
+		{
+			name:         "Used a while ago",
+			token:        token,
+			apiToken:     apiTokenUsedAt(pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}),
+			wantLastUsed: true,
+			wantUserId:   2,
+		},
+		{
+			name:       "Used recently",
+			token:      token,
+			apiToken:   apiTokenUsedAt(pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}),
+			wantUserId: 2,
+		},
+		{
+			name:      "Revoked or expired",
+			token:     token,
+			lookupErr: pgx.ErrNoRows,
+			wantErr:   ErrInvalidApiToken,
+		},
+		{
+			name:           "Malformed",
+			token:          "not-a-token",
+			wantErr:        ErrInvalidApiToken,
+			skipLookupCall: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries := mocks.NewMockDBQueries(t)
+			ctx := context.Background()
+			s := NewApiTokenService(slog.New(slog.DiscardHandler), ctx, queries, mocks.NewMockTxStarter(queries))
+
+			if !tt.skipLookupCall {
+				queries.EXPECT().GetApiTokenByLookupId(ctx, lookupId).Return(tt.apiToken, tt.lookupErr).Once()
+			}
+			if tt.wantLastUsed {
+				queries.EXPECT().UpdateApiTokenLastUsed(ctx, int32(1)).Return(nil).Once()
+			}
+
+			userId, scope, err := s.AuthenticateApiToken(tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUserId, userId)
+			assert.Equal(t, pages.ApiTokenScopeRead, scope)
+		})
+	}
+}
@@ -3,24 +3,100 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"runtime"
+	"time"
 
 	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/analysis"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/schematic"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/simulator"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testscript"
 	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/bauerbrun0/nand2tetris-web/internal/textdiff"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 var (
-	ErrChipNotFound = errors.New("chipservice: chip not found")
+	ErrChipNotFound            = errors.New("chipservice: chip not found")
+	ErrChipRevisionNotFound    = errors.New("chipservice: chip revision not found")
+	ErrSimulationLimitExceeded = errors.New("chipservice: simulation limit exceeded")
+	ErrChipDiffTooLarge        = errors.New("chipservice: chip revisions too large to diff")
 )
 
+// The content of a chip is saved as a revision before it is updated. As the
+// editor saves while typing, no revision is saved within chipRevisionInterval
+// of the previous one, and only the newest maxChipRevisions are kept.
+const (
+	chipRevisionInterval = 5 * time.Minute
+	maxChipRevisions     = 50
+)
+
+// Simulations on the server are limited, so that a runaway test script cannot
+// tie it up: each one runs for at most simulationTimeout, and test scripts run
+// at most maxTestScriptSteps commands. The chip endpoints run one simulation per
+// CPU at a time, but progress checks and grading runs have limits of their own,
+// so more simulations than CPUs can run at once. The timeout is only checked
+// between steps, so a single slow step of a large chip can run past it.
+const (
+	simulationTimeout  = 5 * time.Second
+	maxTestScriptSteps = 1_000_000
+)
+
+// HdlError is returned when the HDL of a chip cannot be processed by the hardware simulator.
+type HdlError struct {
+	Err error
+}
+
+func (e *HdlError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HdlError) Unwrap() error {
+	return e.Err
+}
+
+// SimulationError is returned when the inputs or the test script of a simulation are invalid.
+type SimulationError struct {
+	Err error
+}
+
+func (e *SimulationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SimulationError) Unwrap() error {
+	return e.Err
+}
+
+// ChipVersionConflictError is returned when a chip is updated with a version
+// other than its current one, that is, it has been changed since the client got it.
+type ChipVersionConflictError struct {
+	Chip *apidata.Chip
+}
+
+func (e *ChipVersionConflictError) Error() string {
+	return fmt.Sprintf("chipservice: chip has been changed, its current version is %d", e.Chip.Version)
+}
+
 type ChipService interface {
 	CreateChip(name string, projectId int32, userId int32) (*apidata.Chip, error)
 	GetChips(projectId int32, userId int32) ([]apidata.Chip, error)
 	DeleteChip(chipId int32, projectId int32, userId int32) (*apidata.Chip, error)
-	UpdateChip(chipId int32, projectId int32, userId int32, name *string, hdl *string) (*apidata.Chip, error)
+	UpdateChip(chipId int32, projectId int32, userId int32, name *string, hdl *string, version *int32) (*apidata.Chip, error)
+	GetChipStats(chipId int32, projectId int32, userId int32) (*analysis.Stats, error)
+	GetChipSchematic(chipId int32, projectId int32, userId int32, depth int) (*schematic.Schematic, error)
+	GetProjectVerilog(projectId int32, userId int32) (map[string]string, error)
+	SimulateChip(chipId int32, projectId int32, userId int32, steps []apidata.SimulationStep) ([]apidata.SimulationStepResult, error)
+	TestChip(chipId int32, projectId int32, userId int32, script string, compare string) (*testscript.Result, error)
+	GetChipRevisions(chipId int32, projectId int32, userId int32) ([]apidata.ChipRevision, error)
+	GetChipRevision(revisionId int32, chipId int32, projectId int32, userId int32) (*apidata.ChipRevision, error)
+	DiffChipRevisions(fromId int32, toId int32, chipId int32, projectId int32, userId int32) (*apidata.ChipRevisionDiff, error)
+	RestoreChipRevision(revisionId int32, chipId int32, projectId int32, userId int32) (*apidata.Chip, error)
 }
 
 type chipService struct {
@@ -28,6 +104,9 @@ type chipService struct {
 	ctx       context.Context
 	queries   models.DBQueries
 	txStarter models.TxStarter
+	// simulations holds a token for every simulation of the chip endpoints that
+	// is running
+	simulations chan struct{}
 }
 
 func NewChipService(
@@ -37,10 +116,11 @@ func NewChipService(
 	txStarter models.TxStarter,
 ) ChipService {
 	return &chipService{
-		logger:    logger,
-		ctx:       ctx,
-		queries:   queries,
-		txStarter: txStarter,
+		logger:      logger,
+		ctx:         ctx,
+		queries:     queries,
+		txStarter:   txStarter,
+		simulations: make(chan struct{}, runtime.NumCPU()),
 	}
 }
 
@@ -89,6 +169,7 @@ func (s *chipService) CreateChip(name string, projectId int32, userId int32) (*a
 		Name:      chipRecord.Name,
 		Created:   chipRecord.Created.Time,
 		Updated:   chipRecord.Updated.Time,
+		Version:   chipRecord.Version,
 	}
 
 	return chip, nil
@@ -129,6 +210,7 @@ func (s *chipService) GetChips(projectId int32, userId int32) ([]apidata.Chip, e
 			Name:      chip.Name,
 			Created:   chip.Created.Time,
 			Updated:   chip.Updated.Time,
+			Version:   chip.Version,
 		})
 	}
 
@@ -180,10 +262,21 @@ func (s *chipService) DeleteChip(chipId int32, projectId int32, userId int32) (*
 		Name:      chipRecord.Name,
 		Created:   chipRecord.Created.Time,
 		Updated:   chipRecord.Updated.Time,
+		Version:   chipRecord.Version,
 	}, nil
 }
 
-func (s *chipService) UpdateChip(chipId int32, projectId int32, userId int32, name *string, hdl *string) (*apidata.Chip, error) {
+// UpdateChip updates the name and the HDL of the chip. If version is not nil,
+// the chip is only updated if it is still at that version, otherwise a
+// *ChipVersionConflictError holding the current chip is returned.
+func (s *chipService) UpdateChip(
+	chipId int32,
+	projectId int32,
+	userId int32,
+	name *string,
+	hdl *string,
+	version *int32,
+) (*apidata.Chip, error) {
 	tx, qtx, err := s.txStarter.Begin(s.ctx)
 	if err != nil {
 		return nil, err
@@ -215,6 +308,10 @@ func (s *chipService) UpdateChip(chipId int32, projectId int32, userId int32, na
 		return nil, err
 	}
 
+	if version != nil && *version != oldChip.Version {
+		return nil, &ChipVersionConflictError{Chip: toChip(oldChip)}
+	}
+
 	var newName string
 	var newHdl string
 
@@ -230,6 +327,11 @@ func (s *chipService) UpdateChip(chipId int32, projectId int32, userId int32, na
 		newHdl = oldChip.Hdl.String
 	}
 
+	err = s.saveChipRevision(qtx, oldChip, newName, newHdl, false)
+	if err != nil {
+		return nil, err
+	}
+
 	chip, err := qtx.UpdateChip(s.ctx, models.UpdateChipParams{
 		ID:   chipId,
 		Name: newName,
@@ -237,9 +339,430 @@ func (s *chipService) UpdateChip(chipId int32, projectId int32, userId int32, na
 			String: newHdl,
 			Valid:  true,
 		},
+		Version: oldChip.Version,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, s.chipVersionConflict(qtx, chipId, projectId)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == models.ErrorCodeUniqueViolation {
+				return nil, models.ErrChipNameTaken
+			}
+		}
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apidata.Chip{
+		ID:        chip.ID,
+		ProjectID: chip.ProjectID,
+		Name:      chip.Name,
+		Hdl:       chip.Hdl.String,
+		Created:   chip.Created.Time,
+		Updated:   chip.Updated.Time,
+		Version:   chip.Version,
+	}, nil
+}
+
+func (s *chipService) GetChipStats(chipId int32, projectId int32, userId int32) (*analysis.Stats, error) {
+	chipName, hdls, err := s.getProjectHdls(chipId, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	hs := simulator.New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+	g, err := hs.BuildGraph(chipName)
+	if err != nil {
+		return nil, &HdlError{Err: err}
+	}
+
+	stats := analysis.Analyze(chipName, g, analysis.DefaultCosts())
+	return &stats, nil
+}
+
+func (s *chipService) GetChipSchematic(chipId int32, projectId int32, userId int32, depth int) (*schematic.Schematic, error) {
+	chipName, hdls, err := s.getProjectHdls(chipId, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	hs := simulator.New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+	chipSchematic, err := hs.Schematic(chipName, depth)
+	if err != nil {
+		return nil, &HdlError{Err: err}
+	}
+	return chipSchematic, nil
+}
+
+// GetProjectVerilog translates every chip of the project to Verilog, returning
+// the modules by name.
+func (s *chipService) GetProjectVerilog(projectId int32, userId int32) (map[string]string, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	projectOwnedByUser, err := qtx.IsProjectOwnedByUser(s.ctx, models.IsProjectOwnedByUserParams{
+		ID:     projectId,
+		UserID: userId,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !projectOwnedByUser {
+		return nil, ErrProjectNotFound
+	}
+
+	chipRecords, err := qtx.GetChipsByProject(s.ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chipNames := make([]string, 0, len(chipRecords))
+	hdls := make(map[string]string, len(chipRecords))
+	for _, chip := range chipRecords {
+		chipNames = append(chipNames, chip.Name)
+		hdls[chip.Name] = chip.Hdl.String
+	}
+
+	hs := simulator.New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+	modules, err := hs.Verilog(chipNames...)
+	if err != nil {
+		return nil, &HdlError{Err: err}
+	}
+	return modules, nil
+}
+
+// SimulateChip runs the steps on the chip, returning the values of its pins
+// after each of them. The time limit is checked before every step, a step that
+// is already running is not interrupted.
+func (s *chipService) SimulateChip(
+	chipId int32,
+	projectId int32,
+	userId int32,
+	steps []apidata.SimulationStep,
+) ([]apidata.SimulationStepResult, error) {
+	chipName, hdls, err := s.getProjectHdls(chipId, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel, err := s.startSimulation()
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	hs := simulator.New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+	inputWidths, _, _, err := hs.Process(chipName)
+	if err != nil {
+		return nil, &HdlError{Err: err}
+	}
+
+	inputs := make(map[string][]bool, len(inputWidths))
+	for name, width := range inputWidths {
+		inputs[name] = make([]bool, width)
+	}
+
+	results := make([]apidata.SimulationStepResult, 0, len(steps))
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			return nil, ErrSimulationLimitExceeded
+		}
+
+		for name, value := range step.Inputs {
+			width, ok := inputWidths[name]
+			if !ok {
+				return nil, &SimulationError{Err: fmt.Errorf("step %d: '%s' is not an input of the chip", i+1, name)}
+			}
+			inputs[name] = valueToBits(value, width)
+		}
+
+		var outputs, internals map[string][]bool
+		switch step.Action {
+		case "", "eval":
+			outputs, internals = hs.Evaluate(inputs)
+		case "tick":
+			outputs, internals = hs.Tick(inputs)
+		case "tock":
+			outputs, internals = hs.Tock(inputs)
+		case "cycle":
+			hs.Tick(inputs)
+			outputs, internals = hs.Tock(inputs)
+		default:
+			return nil, &SimulationError{Err: fmt.Errorf("step %d: unknown action '%s'", i+1, step.Action)}
+		}
+
+		results = append(results, apidata.SimulationStepResult{
+			Outputs:   pinValues(outputs),
+			Internals: pinValues(internals),
+		})
+	}
+
+	return results, nil
+}
+
+// TestChip runs the test script on the project, starting with the chip loaded,
+// and compares its output with the compare file if there is one.
+func (s *chipService) TestChip(
+	chipId int32,
+	projectId int32,
+	userId int32,
+	script string,
+	compare string,
+) (*testscript.Result, error) {
+	chipName, hdls, err := s.getProjectHdls(chipId, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedScript, err := testscript.Parse(script)
+	if err != nil {
+		return nil, &SimulationError{Err: err}
+	}
+
+	ctx, cancel, err := s.startSimulation()
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	hs := simulator.New(chips.NewDefaultRegistry())
+	hs.SetChipHDLs(hdls)
+	result, err := testscript.Run(ctx, hs, parsedScript, testscript.Options{
+		Chip:     chipName,
+		Compare:  compare,
+		MaxSteps: maxTestScriptSteps,
 	})
+	if err != nil {
+		var scriptErr *testscript.Error
+		switch {
+		case errors.As(err, &scriptErr):
+			return nil, &SimulationError{Err: err}
+		case errors.Is(err, testscript.ErrStepLimitExceeded), ctx.Err() != nil:
+			return nil, ErrSimulationLimitExceeded
+		default:
+			return nil, &HdlError{Err: err}
+		}
+	}
+	return result, nil
+}
+
+// startSimulation waits for a free CPU, returning a context that is done when
+// the simulation ran out of time. The returned function has to be called when
+// the simulation is over.
+func (s *chipService) startSimulation() (context.Context, context.CancelFunc, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, simulationTimeout)
+	select {
+	case s.simulations <- struct{}{}:
+	case <-ctx.Done():
+		cancel()
+		return nil, nil, ErrSimulationLimitExceeded
+	}
+
+	return ctx, func() {
+		<-s.simulations
+		cancel()
+	}, nil
+}
+
+// valueToBits returns the lowest bits of the value, in two's complement for negative values.
+func valueToBits(value int64, width int) []bool {
+	bits := make([]bool, width)
+	for i := range bits {
+		bits[i] = value>>i&1 == 1
+	}
+	return bits
+}
+
+// pinValues returns the values of the pins, 16-bit values signed and narrower ones unsigned.
+func pinValues(pins map[string][]bool) map[string]int64 {
+	values := make(map[string]int64, len(pins))
+	for name, bits := range pins {
+		var value int64
+		for i, bit := range bits {
+			if bit {
+				value |= 1 << i
+			}
+		}
+		if len(bits) == 16 && bits[15] {
+			value -= 1 << 16
+		}
+		values[name] = value
+	}
+	return values
+}
+
+// GetChipRevisions returns the revisions of the chip, the newest first.
+func (s *chipService) GetChipRevisions(chipId int32, projectId int32, userId int32) ([]apidata.ChipRevision, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	_, err = s.getOwnedChip(qtx, chipId, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionRecords, err := qtx.GetChipRevisions(s.ctx, chipId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]apidata.ChipRevision, 0, len(revisionRecords))
+	for _, revision := range revisionRecords {
+		revisions = append(revisions, toChipRevision(revision))
+	}
+	return revisions, nil
+}
+
+func (s *chipService) GetChipRevision(revisionId int32, chipId int32, projectId int32, userId int32) (*apidata.ChipRevision, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	_, err = s.getOwnedChip(qtx, chipId, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.getChipRevision(qtx, revisionId, chipId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toChipRevision(revision)
+	return &result, nil
+}
+
+// DiffChipRevisions returns the line diff of the HDL from one revision to
+// another, 0 standing for the current content of the chip.
+func (s *chipService) DiffChipRevisions(
+	fromId int32,
+	toId int32,
+	chipId int32,
+	projectId int32,
+	userId int32,
+) (*apidata.ChipRevisionDiff, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	chip, err := s.getOwnedChip(qtx, chipId, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	hdlOf := func(revisionId int32) (string, error) {
+		if revisionId == 0 {
+			return chip.Hdl.String, nil
+		}
+		revision, err := s.getChipRevision(qtx, revisionId, chipId)
+		if err != nil {
+			return "", err
+		}
+		return revision.Hdl.String, nil
+	}
+
+	fromHdl, err := hdlOf(fromId)
+	if err != nil {
+		return nil, err
+	}
+	toHdl, err := hdlOf(toId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := textdiff.Diff(fromHdl, toHdl)
+	if err != nil {
+		if errors.Is(err, textdiff.ErrTooManyLines) {
+			return nil, ErrChipDiffTooLarge
+		}
+		return nil, err
+	}
+
+	return &apidata.ChipRevisionDiff{
+		From:  fromId,
+		To:    toId,
+		Lines: lines,
+	}, nil
+}
+
+// RestoreChipRevision sets the name and the HDL of the chip to the ones of the
+// revision. The current content is saved as a revision first, so that
+// restoring can be undone.
+func (s *chipService) RestoreChipRevision(revisionId int32, chipId int32, projectId int32, userId int32) (*apidata.Chip, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	oldChip, err := s.getOwnedChip(qtx, chipId, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.getChipRevision(qtx, revisionId, chipId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.saveChipRevision(qtx, oldChip, revision.Name, revision.Hdl.String, true)
+	if err != nil {
+		return nil, err
+	}
 
+	chip, err := qtx.UpdateChip(s.ctx, models.UpdateChipParams{
+		ID:      chipId,
+		Name:    revision.Name,
+		Hdl:     revision.Hdl,
+		Version: oldChip.Version,
+	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, s.chipVersionConflict(qtx, chipId, projectId)
+		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			if pgErr.Code == models.ErrorCodeUniqueViolation {
@@ -261,5 +784,166 @@ func (s *chipService) UpdateChip(chipId int32, projectId int32, userId int32, na
 		Hdl:       chip.Hdl.String,
 		Created:   chip.Created.Time,
 		Updated:   chip.Updated.Time,
+		Version:   chip.Version,
 	}, nil
 }
+
+// saveChipRevision saves the content of the chip as a revision before it is
+// changed to the new name and HDL. Unless forced, nothing is saved within
+// chipRevisionInterval of the previous revision.
+func (s *chipService) saveChipRevision(qtx models.DBQueries, chip models.Chip, newName string, newHdl string, force bool) error {
+	if chip.Name == newName && chip.Hdl.String == newHdl {
+		return nil
+	}
+
+	if !force {
+		latest, err := qtx.GetLatestChipRevision(s.ctx, chip.ID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if err == nil && time.Since(latest.Created.Time) < chipRevisionInterval {
+			return nil
+		}
+	}
+
+	_, err := qtx.CreateChipRevision(s.ctx, models.CreateChipRevisionParams{
+		ChipID: chip.ID,
+		Name:   chip.Name,
+		Hdl:    chip.Hdl,
+	})
+	if err != nil {
+		return err
+	}
+
+	return qtx.DeleteOldChipRevisions(s.ctx, models.DeleteOldChipRevisionsParams{
+		ChipID: chip.ID,
+		Keep:   maxChipRevisions,
+	})
+}
+
+// getOwnedChip returns the chip if it is in the project and the project is owned by the user.
+func (s *chipService) getOwnedChip(qtx models.DBQueries, chipId int32, projectId int32, userId int32) (models.Chip, error) {
+	projectOwnedByUser, err := qtx.IsProjectOwnedByUser(s.ctx, models.IsProjectOwnedByUserParams{
+		ID:     projectId,
+		UserID: userId,
+	})
+
+	if err != nil {
+		return models.Chip{}, err
+	}
+
+	if !projectOwnedByUser {
+		return models.Chip{}, ErrChipNotFound
+	}
+
+	chip, err := qtx.GetChip(s.ctx, models.GetChipParams{
+		ID:        chipId,
+		ProjectID: projectId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Chip{}, ErrChipNotFound
+		}
+		return models.Chip{}, err
+	}
+	return chip, nil
+}
+
+func (s *chipService) getChipRevision(qtx models.DBQueries, revisionId int32, chipId int32) (models.ChipRevision, error) {
+	revision, err := qtx.GetChipRevision(s.ctx, models.GetChipRevisionParams{
+		ID:     revisionId,
+		ChipID: chipId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ChipRevision{}, ErrChipRevisionNotFound
+		}
+		return models.ChipRevision{}, err
+	}
+	return revision, nil
+}
+
+// chipVersionConflict returns the error for a chip that has been changed
+// between reading and updating it in the transaction.
+func (s *chipService) chipVersionConflict(qtx models.DBQueries, chipId int32, projectId int32) error {
+	chip, err := qtx.GetChip(s.ctx, models.GetChipParams{
+		ID:        chipId,
+		ProjectID: projectId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrChipNotFound
+		}
+		return err
+	}
+	return &ChipVersionConflictError{Chip: toChip(chip)}
+}
+
+func toChip(chip models.Chip) *apidata.Chip {
+	return &apidata.Chip{
+		ID:        chip.ID,
+		ProjectID: chip.ProjectID,
+		Name:      chip.Name,
+		Hdl:       chip.Hdl.String,
+		Created:   chip.Created.Time,
+		Updated:   chip.Updated.Time,
+		Version:   chip.Version,
+	}
+}
+
+func toChipRevision(revision models.ChipRevision) apidata.ChipRevision {
+	return apidata.ChipRevision{
+		ID:      revision.ID,
+		ChipID:  revision.ChipID,
+		Name:    revision.Name,
+		Hdl:     revision.Hdl.String,
+		Created: revision.Created.Time,
+	}
+}
+
+// getProjectHdls returns the name of the chip and the HDL of every chip of the project by name.
+func (s *chipService) getProjectHdls(chipId int32, projectId int32, userId int32) (string, map[string]string, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	projectOwnedByUser, err := qtx.IsProjectOwnedByUser(s.ctx, models.IsProjectOwnedByUserParams{
+		ID:     projectId,
+		UserID: userId,
+	})
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !projectOwnedByUser {
+		return "", nil, ErrChipNotFound
+	}
+
+	chipRecords, err := qtx.GetChipsByProject(s.ctx, projectId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	chipName := ""
+	hdls := make(map[string]string, len(chipRecords))
+	for _, chip := range chipRecords {
+		hdls[chip.Name] = chip.Hdl.String
+		if chip.ID == chipId {
+			chipName = chip.Name
+		}
+	}
+
+	if chipName == "" {
+		return "", nil, ErrChipNotFound
+	}
+
+	return chipName, hdls, nil
+}
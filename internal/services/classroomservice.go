@@ -0,0 +1,673 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/crypto"
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrClassroomNotFound       = errors.New("classroomservice: classroom not found")
+	ErrClassroomForbidden      = errors.New("classroomservice: action is not allowed for the role of the user")
+	ErrAlreadyClassroomMember  = errors.New("classroomservice: user is already a member of the classroom")
+	ErrClassroomMemberNotFound = errors.New("classroomservice: classroom member not found")
+	ErrAssignmentNotFound      = errors.New("classroomservice: assignment not found")
+	ErrSubmissionNotFound      = errors.New("classroomservice: submission not found")
+)
+
+const classroomInviteCodeLength = 10
+
+// maxInviteCodeAttempts is how many invite codes are generated for a new
+// classroom before giving up, in case the codes are taken by others.
+const maxInviteCodeAttempts = 3
+
+type ClassroomService interface {
+	CreateClassroom(name string, userId int32) (*apidata.Classroom, error)
+	GetClassrooms(userId int32) ([]apidata.Classroom, error)
+	GetClassroom(classroomId int32, userId int32) (*apidata.Classroom, error)
+	JoinClassroom(inviteCode string, userId int32) (*apidata.Classroom, error)
+	GetClassroomMembers(classroomId int32, userId int32) ([]apidata.ClassroomMember, error)
+	UpdateClassroomMemberRole(classroomId int32, memberId int32, role string, userId int32) (*apidata.ClassroomMember, error)
+	CreateAssignment(classroomId int32, request apidata.CreateAssignmentRequest, userId int32) (*apidata.Assignment, error)
+	GetAssignments(classroomId int32, userId int32) ([]apidata.Assignment, error)
+	GetSubmissions(classroomId int32, assignmentId int32, userId int32) ([]apidata.Submission, error)
+	GetSubmission(classroomId int32, assignmentId int32, studentId int32, userId int32) (*apidata.SubmissionDetail, error)
+}
+
+// classroomService authorizes every action by the role of the user in the
+// classroom. A user who is not a member gets ErrClassroomNotFound, so that
+// classrooms cannot be probed; a member whose role does not allow the action
+// gets ErrClassroomForbidden.
+type classroomService struct {
+	logger    *slog.Logger
+	ctx       context.Context
+	queries   models.DBQueries
+	txStarter models.TxStarter
+}
+
+func NewClassroomService(
+	logger *slog.Logger,
+	ctx context.Context,
+	queries models.DBQueries,
+	txStarter models.TxStarter,
+) ClassroomService {
+	return &classroomService{
+		logger:    logger,
+		ctx:       ctx,
+		queries:   queries,
+		txStarter: txStarter,
+	}
+}
+
+// CreateClassroom creates a classroom with the user as its teacher. The invite
+// code is generated again if another classroom has it.
+func (s *classroomService) CreateClassroom(name string, userId int32) (*apidata.Classroom, error) {
+	for attempt := 1; ; attempt++ {
+		inviteCode := crypto.GenerateRandomString(classroomInviteCodeLength)
+		classroom, err := s.createClassroom(name, inviteCode, userId)
+		if err != nil && attempt < maxInviteCodeAttempts {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				if pgErr.Code == models.ErrorCodeUniqueViolation &&
+					pgErr.ConstraintName == models.ConstraintNameClassroomsUniqueInviteCode {
+					continue
+				}
+			}
+		}
+		return classroom, err
+	}
+}
+
+// createClassroom creates the classroom in a transaction of its own, as the
+// transaction can not be used any more after the invite code turned out to be
+// taken.
+func (s *classroomService) createClassroom(name string, inviteCode string, userId int32) (*apidata.Classroom, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	classroom, err := qtx.CreateClassroom(s.ctx, models.CreateClassroomParams{
+		Name:       name,
+		InviteCode: inviteCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = qtx.AddClassroomMember(s.ctx, models.AddClassroomMemberParams{
+		ClassroomID: classroom.ID,
+		UserID:      userId,
+		Role:        apidata.ClassroomRoleTeacher,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toClassroom(classroom, apidata.ClassroomRoleTeacher), nil
+}
+
+func (s *classroomService) GetClassrooms(userId int32) ([]apidata.Classroom, error) {
+	rows, err := s.queries.GetClassroomsOfMember(s.ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	classrooms := make([]apidata.Classroom, 0, len(rows))
+	for _, row := range rows {
+		classrooms = append(classrooms, *toClassroom(row.Classroom, row.Role))
+	}
+	return classrooms, nil
+}
+
+func (s *classroomService) GetClassroom(classroomId int32, userId int32) (*apidata.Classroom, error) {
+	row, err := getClassroomMembership(s.ctx, s.queries, classroomId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return toClassroom(row.Classroom, row.Role), nil
+}
+
+// JoinClassroom adds the user to the classroom of the invite code as a
+// student, giving them a project for every assignment of the classroom.
+func (s *classroomService) JoinClassroom(inviteCode string, userId int32) (*apidata.Classroom, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	classroom, err := qtx.GetClassroomByInviteCode(s.ctx, inviteCode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClassroomNotFound
+		}
+		return nil, err
+	}
+
+	_, err = qtx.AddClassroomMember(s.ctx, models.AddClassroomMemberParams{
+		ClassroomID: classroom.ID,
+		UserID:      userId,
+		Role:        apidata.ClassroomRoleStudent,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == models.ErrorCodeUniqueViolation {
+				return nil, ErrAlreadyClassroomMember
+			}
+		}
+		return nil, err
+	}
+
+	assignments, err := qtx.GetAssignmentsByClassroom(s.ctx, classroom.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.createSubmissions(qtx, assignments, []int32{userId})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toClassroom(classroom, apidata.ClassroomRoleStudent), nil
+}
+
+func (s *classroomService) GetClassroomMembers(classroomId int32, userId int32) ([]apidata.ClassroomMember, error) {
+	_, err := requireClassroomRole(s.ctx, s.queries, classroomId, userId, apidata.ClassroomRoleTeacher)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.GetClassroomMembers(s.ctx, classroomId)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]apidata.ClassroomMember, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, apidata.ClassroomMember{
+			UserID:   row.UserID,
+			Username: row.Username,
+			Role:     row.Role,
+			Joined:   row.Joined.Time,
+		})
+	}
+	return members, nil
+}
+
+// UpdateClassroomMemberRole makes a member a teacher or a student. Teachers
+// cannot change their own role, so that a classroom is never left without one.
+func (s *classroomService) UpdateClassroomMemberRole(
+	classroomId int32,
+	memberId int32,
+	role string,
+	userId int32,
+) (*apidata.ClassroomMember, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	_, err = requireClassroomRole(s.ctx, qtx, classroomId, userId, apidata.ClassroomRoleTeacher)
+	if err != nil {
+		return nil, err
+	}
+
+	if memberId == userId {
+		return nil, ErrClassroomForbidden
+	}
+
+	member, err := qtx.UpdateClassroomMemberRole(s.ctx, models.UpdateClassroomMemberRoleParams{
+		ClassroomID: classroomId,
+		UserID:      memberId,
+		Role:        role,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClassroomMemberNotFound
+		}
+		return nil, err
+	}
+
+	members, err := qtx.GetClassroomMembers(s.ctx, classroomId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var username string
+	for _, m := range members {
+		if m.UserID == member.UserID {
+			username = m.Username
+		}
+	}
+
+	return &apidata.ClassroomMember{
+		UserID:   member.UserID,
+		Username: username,
+		Role:     member.Role,
+		Joined:   member.Joined.Time,
+	}, nil
+}
+
+// CreateAssignment creates an assignment and gives every student of the
+// classroom a copy of the starter project, which has to be owned by the teacher.
+func (s *classroomService) CreateAssignment(
+	classroomId int32,
+	request apidata.CreateAssignmentRequest,
+	userId int32,
+) (*apidata.Assignment, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	_, err = requireClassroomRole(s.ctx, qtx, classroomId, userId, apidata.ClassroomRoleTeacher)
+	if err != nil {
+		return nil, err
+	}
+
+	params := models.CreateAssignmentParams{
+		ClassroomID: classroomId,
+		Title:       request.Title,
+		Description: pgtype.Text{
+			String: request.Description,
+			Valid:  true,
+		},
+	}
+
+	if request.StarterProjectID != nil {
+		projectOwnedByUser, err := qtx.IsProjectOwnedByUser(s.ctx, models.IsProjectOwnedByUserParams{
+			ID:     *request.StarterProjectID,
+			UserID: userId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !projectOwnedByUser {
+			return nil, ErrProjectNotFound
+		}
+		params.StarterProjectID = pgtype.Int4{Int32: *request.StarterProjectID, Valid: true}
+	}
+
+	if request.Due != nil {
+		params.Due = pgtype.Timestamptz{Time: *request.Due, Valid: true}
+	}
+
+	assignment, err := qtx.CreateAssignment(s.ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := qtx.GetClassroomMembers(s.ctx, classroomId)
+	if err != nil {
+		return nil, err
+	}
+
+	var studentIds []int32
+	for _, member := range members {
+		if member.Role == apidata.ClassroomRoleStudent {
+			studentIds = append(studentIds, member.UserID)
+		}
+	}
+
+	err = s.createSubmissions(qtx, []models.Assignment{assignment}, studentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAssignment(assignment, pgtype.Int4{}), nil
+}
+
+// GetAssignments returns the assignments of the classroom. For students, they
+// hold the projects of the student for them.
+func (s *classroomService) GetAssignments(classroomId int32, userId int32) ([]apidata.Assignment, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	_, err = getClassroomMembership(s.ctx, qtx, classroomId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := qtx.GetAssignmentsOfStudent(s.ctx, models.GetAssignmentsOfStudentParams{
+		ClassroomID: classroomId,
+		UserID:      userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]apidata.Assignment, 0, len(rows))
+	for _, row := range rows {
+		assignments = append(assignments, *toAssignment(row.Assignment, row.ProjectID))
+	}
+	return assignments, nil
+}
+
+func (s *classroomService) GetSubmissions(classroomId int32, assignmentId int32, userId int32) ([]apidata.Submission, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	assignment, err := getTeacherAssignment(s.ctx, qtx, classroomId, assignmentId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := qtx.GetAssignmentSubmissions(s.ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions := make([]apidata.Submission, 0, len(rows))
+	for _, row := range rows {
+		submissions = append(submissions, apidata.Submission{
+			AssignmentID: assignment.ID,
+			UserID:       row.UserID,
+			Username:     row.Username,
+			ProjectID:    row.ProjectID,
+			Title:        row.Title,
+			Updated:      row.Updated.Time,
+		})
+	}
+	return submissions, nil
+}
+
+// GetSubmission returns the project of a student for an assignment with its
+// chips. Teachers can only read it, it is changed through the project
+// endpoints, which only its owner, the student, can use.
+func (s *classroomService) GetSubmission(
+	classroomId int32,
+	assignmentId int32,
+	studentId int32,
+	userId int32,
+) (*apidata.SubmissionDetail, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	assignment, err := getTeacherAssignment(s.ctx, qtx, classroomId, assignmentId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	submission, err := qtx.GetAssignmentSubmission(s.ctx, models.GetAssignmentSubmissionParams{
+		AssignmentID: assignment.ID,
+		UserID:       studentId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+
+	project, err := qtx.GetProject(s.ctx, models.GetProjectParams{
+		ID:     submission.ProjectID,
+		UserID: studentId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+
+	members, err := qtx.GetClassroomMembers(s.ctx, classroomId)
+	if err != nil {
+		return nil, err
+	}
+
+	chipRecords, err := qtx.GetChipsByProject(s.ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var username string
+	for _, member := range members {
+		if member.UserID == studentId {
+			username = member.Username
+		}
+	}
+
+	chips := make([]apidata.SharedChip, 0, len(chipRecords))
+	for _, chip := range chipRecords {
+		chips = append(chips, apidata.SharedChip{
+			Name: chip.Name,
+			Hdl:  chip.Hdl.String,
+		})
+	}
+
+	return &apidata.SubmissionDetail{
+		Submission: apidata.Submission{
+			AssignmentID: assignment.ID,
+			UserID:       studentId,
+			Username:     username,
+			ProjectID:    project.ID,
+			Title:        project.Title,
+			Updated:      project.Updated.Time,
+		},
+		Description: project.Description.String,
+		Chips:       chips,
+	}, nil
+}
+
+// createSubmissions gives each of the students a project for each of the
+// assignments, a copy of the starter project of the assignment, or an empty
+// project if it has none.
+func (s *classroomService) createSubmissions(qtx models.DBQueries, assignments []models.Assignment, studentIds []int32) error {
+	if len(assignments) == 0 || len(studentIds) == 0 {
+		return nil
+	}
+
+	err := qtx.SkipDefaultChips(s.ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, assignment := range assignments {
+		for _, studentId := range studentIds {
+			project, err := qtx.CreateProjectWithUniqueTitle(s.ctx, models.CreateProjectWithUniqueTitleParams{
+				UserID:      studentId,
+				Title:       assignment.Title,
+				Description: assignment.Description,
+			})
+			if err != nil {
+				return err
+			}
+
+			if assignment.StarterProjectID.Valid {
+				err = qtx.CopyChips(s.ctx, models.CopyChipsParams{
+					ToProjectID:   project.ID,
+					FromProjectID: assignment.StarterProjectID.Int32,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err = qtx.CreateAssignmentSubmission(s.ctx, models.CreateAssignmentSubmissionParams{
+				AssignmentID: assignment.ID,
+				UserID:       studentId,
+				ProjectID:    project.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getClassroomMembership returns the classroom with the role of the user in
+// it, or ErrClassroomNotFound if the user is not a member.
+func getClassroomMembership(
+	ctx context.Context,
+	qtx models.DBQueries,
+	classroomId int32,
+	userId int32,
+) (models.GetClassroomOfMemberRow, error) {
+	row, err := qtx.GetClassroomOfMember(ctx, models.GetClassroomOfMemberParams{
+		ID:     classroomId,
+		UserID: userId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.GetClassroomOfMemberRow{}, ErrClassroomNotFound
+		}
+		return models.GetClassroomOfMemberRow{}, err
+	}
+	return row, nil
+}
+
+// requireClassroomRole is getClassroomMembership that also returns
+// ErrClassroomForbidden if the user does not have the role in the classroom.
+func requireClassroomRole(
+	ctx context.Context,
+	qtx models.DBQueries,
+	classroomId int32,
+	userId int32,
+	role string,
+) (models.GetClassroomOfMemberRow, error) {
+	row, err := getClassroomMembership(ctx, qtx, classroomId, userId)
+	if err != nil {
+		return models.GetClassroomOfMemberRow{}, err
+	}
+	if row.Role != role {
+		return models.GetClassroomOfMemberRow{}, ErrClassroomForbidden
+	}
+	return row, nil
+}
+
+// getClassroomAssignment returns the assignment of the classroom with the
+// role of the user in the classroom.
+func getClassroomAssignment(
+	ctx context.Context,
+	qtx models.DBQueries,
+	classroomId int32,
+	assignmentId int32,
+	userId int32,
+) (models.Assignment, string, error) {
+	row, err := getClassroomMembership(ctx, qtx, classroomId, userId)
+	if err != nil {
+		return models.Assignment{}, "", err
+	}
+
+	assignment, err := qtx.GetAssignment(ctx, models.GetAssignmentParams{
+		ID:          assignmentId,
+		ClassroomID: classroomId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Assignment{}, "", ErrAssignmentNotFound
+		}
+		return models.Assignment{}, "", err
+	}
+	return assignment, row.Role, nil
+}
+
+// getTeacherAssignment is getClassroomAssignment for actions that only the
+// teachers of the classroom are allowed to take.
+func getTeacherAssignment(
+	ctx context.Context,
+	qtx models.DBQueries,
+	classroomId int32,
+	assignmentId int32,
+	userId int32,
+) (models.Assignment, error) {
+	assignment, role, err := getClassroomAssignment(ctx, qtx, classroomId, assignmentId, userId)
+	if err != nil {
+		return models.Assignment{}, err
+	}
+	if role != apidata.ClassroomRoleTeacher {
+		return models.Assignment{}, ErrClassroomForbidden
+	}
+	return assignment, nil
+}
+
+func toClassroom(classroom models.Classroom, role string) *apidata.Classroom {
+	result := &apidata.Classroom{
+		ID:      classroom.ID,
+		Name:    classroom.Name,
+		Role:    role,
+		Created: classroom.Created.Time,
+	}
+	if role == apidata.ClassroomRoleTeacher {
+		result.InviteCode = classroom.InviteCode
+	}
+	return result
+}
+
+func toAssignment(assignment models.Assignment, projectId pgtype.Int4) *apidata.Assignment {
+	result := &apidata.Assignment{
+		ID:          assignment.ID,
+		ClassroomID: assignment.ClassroomID,
+		Title:       assignment.Title,
+		Description: assignment.Description.String,
+		Created:     assignment.Created.Time,
+	}
+	if assignment.StarterProjectID.Valid {
+		result.StarterProjectID = &assignment.StarterProjectID.Int32
+	}
+	if assignment.Due.Valid {
+		due := assignment.Due.Time
+		result.Due = &due
+	}
+	if projectId.Valid {
+		result.ProjectID = &projectId.Int32
+	}
+	return result
+}
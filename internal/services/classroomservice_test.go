@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/bauerbrun0/nand2tetris-web/internal/models/mocks"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateClassroomInviteCodeTaken(t *testing.T) {
+	inviteCodeTaken := &pgconn.PgError{
+		Code:           models.ErrorCodeUniqueViolation,
+		ConstraintName: models.ConstraintNameClassroomsUniqueInviteCode,
+	}
+
+	t.Run("Taken once", func(t *testing.T) {
+		queries := mocks.NewMockDBQueries(t)
+		ctx := context.Background()
+		s := NewClassroomService(slog.New(slog.DiscardHandler), ctx, queries, mocks.NewMockTxStarter(queries))
+
+		var inviteCodes []string
+		queries.EXPECT().CreateClassroom(ctx, mock.Anything).RunAndReturn(func(ctx context.Context, params models.CreateClassroomParams) (models.Classroom, error) {
+			inviteCodes = append(inviteCodes, params.InviteCode)
+			if len(inviteCodes) == 1 {
+				return models.Classroom{}, inviteCodeTaken
+			}
+			return models.Classroom{ID: 1, Name: params.Name, InviteCode: params.InviteCode}, nil
+		}).Twice()
+		queries.EXPECT().AddClassroomMember(ctx, models.AddClassroomMemberParams{
+			ClassroomID: 1,
+			UserID:      2,
+			Role:        apidata.ClassroomRoleTeacher,
+		}).Return(models.ClassroomMember{}, nil).Once()
+
+		classroom, err := s.CreateClassroom("Class", 2)
+		assert.NoError(t, err)
+		assert.Len(t, inviteCodes, 2)
+		assert.Equal(t, inviteCodes[1], classroom.InviteCode)
+	})
+
+	t.Run("Always taken", func(t *testing.T) {
+		queries := mocks.NewMockDBQueries(t)
+		ctx := context.Background()
+		s := NewClassroomService(slog.New(slog.DiscardHandler), ctx, queries, mocks.NewMockTxStarter(queries))
+
+		queries.EXPECT().CreateClassroom(ctx, mock.Anything).
+			Return(models.Classroom{}, inviteCodeTaken).Times(maxInviteCodeAttempts)
+
+		_, err := s.CreateClassroom("Class", 2)
+		assert.ErrorIs(t, err, inviteCodeTaken)
+	})
+}
@@ -0,0 +1,514 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/grader"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/testscript"
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrAssignmentTestNotFound  = errors.New("gradingservice: assignment test not found")
+	ErrAssignmentTestNameTaken = errors.New("gradingservice: assignment test name is already taken")
+	ErrGradingRunNotFound      = errors.New("gradingservice: grading run not found")
+)
+
+// Submissions are graded by workers in the background. A worker is woken up
+// when runs are queued, and checks the queue every gradingPollInterval in case
+// it missed it, such as for the runs queued before a restart.
+const gradingPollInterval = 30 * time.Second
+
+type GradingService interface {
+	CreateAssignmentTest(classroomId int32, assignmentId int32, request apidata.CreateAssignmentTestRequest, userId int32) (*apidata.AssignmentTest, error)
+	GetAssignmentTests(classroomId int32, assignmentId int32, userId int32) ([]apidata.AssignmentTest, error)
+	DeleteAssignmentTest(testId int32, classroomId int32, assignmentId int32, userId int32) (*apidata.AssignmentTest, error)
+	QueueGrading(classroomId int32, assignmentId int32, userId int32) ([]apidata.GradingRun, error)
+	GetGrade(classroomId int32, assignmentId int32, studentId int32, userId int32) (*apidata.GradingRun, error)
+	GetGradebook(classroomId int32, assignmentId int32, userId int32) ([]byte, error)
+	StartWorkers(count int) error
+}
+
+type gradingService struct {
+	logger    *slog.Logger
+	ctx       context.Context
+	queries   models.DBQueries
+	txStarter models.TxStarter
+	// queued wakes up a waiting worker when runs are queued
+	queued chan struct{}
+}
+
+func NewGradingService(
+	logger *slog.Logger,
+	ctx context.Context,
+	queries models.DBQueries,
+	txStarter models.TxStarter,
+) GradingService {
+	return &gradingService{
+		logger:    logger,
+		ctx:       ctx,
+		queries:   queries,
+		txStarter: txStarter,
+		queued:    make(chan struct{}, 1),
+	}
+}
+
+// CreateAssignmentTest adds a reference test to the assignment, which only the
+// teachers of the classroom can do.
+func (s *gradingService) CreateAssignmentTest(
+	classroomId int32,
+	assignmentId int32,
+	request apidata.CreateAssignmentTestRequest,
+	userId int32,
+) (*apidata.AssignmentTest, error) {
+	_, err := testscript.Parse(request.Script)
+	if err != nil {
+		return nil, &SimulationError{Err: err}
+	}
+
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	assignment, err := getTeacherAssignment(s.ctx, qtx, classroomId, assignmentId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	test, err := qtx.CreateAssignmentTest(s.ctx, models.CreateAssignmentTestParams{
+		AssignmentID: assignment.ID,
+		Name:         request.Name,
+		Chip:         request.Chip,
+		Script:       request.Script,
+		Compare:      request.Compare,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == models.ErrorCodeUniqueViolation {
+				return nil, ErrAssignmentTestNameTaken
+			}
+		}
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAssignmentTest(test), nil
+}
+
+func (s *gradingService) GetAssignmentTests(classroomId int32, assignmentId int32, userId int32) ([]apidata.AssignmentTest, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	assignment, err := getTeacherAssignment(s.ctx, qtx, classroomId, assignmentId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := qtx.GetAssignmentTests(s.ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tests := make([]apidata.AssignmentTest, 0, len(records))
+	for _, test := range records {
+		tests = append(tests, *toAssignmentTest(test))
+	}
+	return tests, nil
+}
+
+func (s *gradingService) DeleteAssignmentTest(
+	testId int32,
+	classroomId int32,
+	assignmentId int32,
+	userId int32,
+) (*apidata.AssignmentTest, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	assignment, err := getTeacherAssignment(s.ctx, qtx, classroomId, assignmentId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	test, err := qtx.DeleteAssignmentTest(s.ctx, models.DeleteAssignmentTestParams{
+		ID:           testId,
+		AssignmentID: assignment.ID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAssignmentTestNotFound
+		}
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAssignmentTest(test), nil
+}
+
+// QueueGrading queues the submissions of the assignment for grading, every
+// submission if the user is a teacher of the classroom, their own if they are
+// a student. Submissions that are already queued are not queued again, nor
+// returned.
+func (s *gradingService) QueueGrading(classroomId int32, assignmentId int32, userId int32) ([]apidata.GradingRun, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	assignment, role, err := getClassroomAssignment(s.ctx, qtx, classroomId, assignmentId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	params := models.QueueGradingRunsParams{
+		AssignmentID: assignment.ID,
+	}
+	if role == apidata.ClassroomRoleStudent {
+		params.UserID = pgtype.Int4{Int32: userId, Valid: true}
+	}
+
+	records, err := qtx.QueueGradingRuns(s.ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) > 0 {
+		select {
+		case s.queued <- struct{}{}:
+		default:
+		}
+	}
+
+	runs := make([]apidata.GradingRun, 0, len(records))
+	for _, run := range records {
+		runs = append(runs, *toGradingRun(run))
+	}
+	return runs, nil
+}
+
+// GetGrade returns the latest grading run of the submission of the student
+// with its results. Students can only get their own.
+func (s *gradingService) GetGrade(
+	classroomId int32,
+	assignmentId int32,
+	studentId int32,
+	userId int32,
+) (*apidata.GradingRun, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	assignment, role, err := getClassroomAssignment(s.ctx, qtx, classroomId, assignmentId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if role != apidata.ClassroomRoleTeacher && studentId != userId {
+		return nil, ErrClassroomForbidden
+	}
+
+	record, err := qtx.GetLatestGradingRun(s.ctx, models.GetLatestGradingRunParams{
+		AssignmentID: assignment.ID,
+		UserID:       studentId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrGradingRunNotFound
+		}
+		return nil, err
+	}
+
+	results, err := qtx.GetGradingResults(s.ctx, record.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	run := toGradingRun(record)
+	for _, result := range results {
+		run.Results = append(run.Results, toGradingResult(result))
+	}
+	return run, nil
+}
+
+// GetGradebook returns the latest results of every student of the assignment
+// as CSV, see grader.WriteGradebook.
+func (s *gradingService) GetGradebook(classroomId int32, assignmentId int32, userId int32) ([]byte, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	assignment, err := getTeacherAssignment(s.ctx, qtx, classroomId, assignmentId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tests, err := qtx.GetAssignmentTests(s.ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions, err := qtx.GetAssignmentSubmissions(s.ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resultRecords, err := qtx.GetLatestGradingResultsOfAssignment(s.ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	testNames := make([]string, 0, len(tests))
+	for _, test := range tests {
+		testNames = append(testNames, test.Name)
+	}
+
+	results := map[int32][]grader.Result{}
+	for _, record := range resultRecords {
+		results[record.UserID] = append(results[record.UserID], grader.Result{
+			Name:   record.GradingResult.TestName,
+			Passed: record.GradingResult.Passed,
+		})
+	}
+
+	rows := make([]grader.GradebookRow, 0, len(submissions))
+	for _, submission := range submissions {
+		rows = append(rows, grader.GradebookRow{
+			Student: submission.Username,
+			Results: results[submission.UserID],
+		})
+	}
+
+	var buf bytes.Buffer
+	err = grader.WriteGradebook(&buf, testNames, rows)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// StartWorkers starts count workers grading the queued runs until the context
+// of the service is done. The runs left running by a previous process are
+// queued again first, so only one process may run workers.
+func (s *gradingService) StartWorkers(count int) error {
+	err := s.queries.RequeueRunningGradingRuns(s.ctx)
+	if err != nil {
+		return err
+	}
+
+	for range count {
+		go s.work()
+	}
+	return nil
+}
+
+func (s *gradingService) work() {
+	for {
+		graded, err := s.gradeNext()
+		if err != nil {
+			s.logger.Error("failed to grade submission", slog.String("error", err.Error()))
+		}
+		if graded {
+			continue
+		}
+
+		select {
+		case <-s.queued:
+		case <-time.After(gradingPollInterval):
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// gradeNext grades the oldest queued run, telling whether there was one.
+func (s *gradingService) gradeNext() (bool, error) {
+	run, err := s.queries.ClaimGradingRun(s.ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	err = s.grade(run)
+	if err != nil {
+		_, finishErr := s.queries.FinishGradingRun(s.ctx, models.FinishGradingRunParams{
+			ID:     run.ID,
+			Status: apidata.GradingStatusFailed,
+			Error:  pgtype.Text{String: err.Error(), Valid: true},
+		})
+		return true, errors.Join(err, finishErr)
+	}
+	return true, nil
+}
+
+// grade runs the tests of the assignment of the run on the chips of the
+// submission, each one limited the same way as the tests run from the editor.
+func (s *gradingService) grade(run models.GradingRun) error {
+	testRecords, err := s.queries.GetAssignmentTests(s.ctx, run.AssignmentID)
+	if err != nil {
+		return err
+	}
+
+	chipRecords, err := s.queries.GetChipsByProject(s.ctx, run.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	tests := make([]grader.Test, 0, len(testRecords))
+	for _, test := range testRecords {
+		tests = append(tests, grader.Test{
+			Name:    test.Name,
+			Chip:    test.Chip,
+			Script:  test.Script,
+			Compare: test.Compare,
+		})
+	}
+
+	hdls := make(map[string]string, len(chipRecords))
+	for _, chip := range chipRecords {
+		hdls[chip.Name] = chip.Hdl.String
+	}
+
+	results := grader.Grade(s.ctx, hdls, tests, grader.Options{
+		MaxSteps: maxTestScriptSteps,
+		Timeout:  simulationTimeout,
+	})
+
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(s.ctx)
+
+	var passed int32
+	for _, result := range results {
+		params := models.CreateGradingResultParams{
+			RunID:    run.ID,
+			TestName: result.Name,
+			Passed:   result.Passed,
+			Error:    pgtype.Text{String: result.Error, Valid: result.Error != ""},
+		}
+		if result.Mismatch != nil {
+			params.MismatchLine = pgtype.Int4{Int32: int32(result.Mismatch.Line), Valid: true}
+			params.Expected = pgtype.Text{String: result.Mismatch.Expected, Valid: true}
+			params.Actual = pgtype.Text{String: result.Mismatch.Actual, Valid: true}
+		}
+		if result.Passed {
+			passed++
+		}
+
+		err = qtx.CreateGradingResult(s.ctx, params)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = qtx.FinishGradingRun(s.ctx, models.FinishGradingRunParams{
+		ID:     run.ID,
+		Status: apidata.GradingStatusDone,
+		Passed: passed,
+		Total:  int32(len(results)),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(s.ctx)
+}
+
+func toAssignmentTest(test models.AssignmentTest) *apidata.AssignmentTest {
+	return &apidata.AssignmentTest{
+		ID:           test.ID,
+		AssignmentID: test.AssignmentID,
+		Name:         test.Name,
+		Chip:         test.Chip,
+		Script:       test.Script,
+		Compare:      test.Compare,
+		Created:      test.Created.Time,
+	}
+}
+
+func toGradingRun(run models.GradingRun) *apidata.GradingRun {
+	result := &apidata.GradingRun{
+		ID:           run.ID,
+		AssignmentID: run.AssignmentID,
+		UserID:       run.UserID,
+		ProjectID:    run.ProjectID,
+		Status:       run.Status,
+		Error:        run.Error.String,
+		Passed:       run.Passed,
+		Total:        run.Total,
+		Created:      run.Created.Time,
+	}
+	if run.Finished.Valid {
+		finished := run.Finished.Time
+		result.Finished = &finished
+	}
+	return result
+}
+
+func toGradingResult(result models.GradingResult) apidata.GradingResult {
+	gradingResult := apidata.GradingResult{
+		TestName: result.TestName,
+		Passed:   result.Passed,
+		Error:    result.Error.String,
+	}
+	if result.MismatchLine.Valid {
+		gradingResult.Mismatch = &testscript.Mismatch{
+			Line:     int(result.MismatchLine.Int32),
+			Expected: result.Expected.String,
+			Actual:   result.Actual.String,
+		}
+	}
+	return gradingResult
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/curriculum"
+	"github.com/bauerbrun0/nand2tetris-web/internal/grader"
+	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+)
+
+type ProgressService interface {
+	GetCurriculum() []apidata.CurriculumProject
+	GetProjectProgress(id int32, userId int32) (*apidata.ProjectProgress, error)
+}
+
+type progressService struct {
+	logger    *slog.Logger
+	ctx       context.Context
+	queries   models.DBQueries
+	txStarter models.TxStarter
+	// checks holds a token for every progress check that is running
+	checks chan struct{}
+}
+
+func NewProgressService(
+	logger *slog.Logger,
+	ctx context.Context,
+	queries models.DBQueries,
+	txStarter models.TxStarter,
+) ProgressService {
+	return &progressService{
+		logger:    logger,
+		ctx:       ctx,
+		queries:   queries,
+		txStarter: txStarter,
+		checks:    make(chan struct{}, runtime.NumCPU()),
+	}
+}
+
+func (s *progressService) GetCurriculum() []apidata.CurriculumProject {
+	projects := curriculum.Projects()
+	result := make([]apidata.CurriculumProject, 0, len(projects))
+	for _, project := range projects {
+		chips := make([]apidata.CurriculumChip, 0, len(project.Chips))
+		for _, chip := range project.Chips {
+			chips = append(chips, apidata.CurriculumChip{
+				Name:    chip.Name,
+				Spec:    chip.Spec,
+				Stub:    chip.Stub,
+				Test:    chip.Test,
+				Compare: chip.Compare,
+			})
+		}
+		result = append(result, apidata.CurriculumProject{
+			Number: project.Number,
+			Title:  project.Title,
+			Chips:  chips,
+		})
+	}
+	return result
+}
+
+// GetProjectProgress runs the reference test of every chip of the curriculum
+// that the project has. The tests share the limits of a simulation: the chips
+// not tested within simulationTimeout are reported as untested.
+func (s *progressService) GetProjectProgress(id int32, userId int32) (*apidata.ProjectProgress, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	projectOwnedByUser, err := qtx.IsProjectOwnedByUser(s.ctx, models.IsProjectOwnedByUserParams{
+		ID:     id,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !projectOwnedByUser {
+		return nil, ErrProjectNotFound
+	}
+
+	chipRecords, err := qtx.GetChipsByProject(s.ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hdls := make(map[string]string, len(chipRecords))
+	chipIds := make(map[string]int32, len(chipRecords))
+	for _, chip := range chipRecords {
+		hdls[chip.Name] = chip.Hdl.String
+		chipIds[chip.Name] = chip.ID
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, simulationTimeout)
+	defer cancel()
+	select {
+	case s.checks <- struct{}{}:
+		defer func() { <-s.checks }()
+	case <-ctx.Done():
+		return nil, ErrSimulationLimitExceeded
+	}
+
+	progress := &apidata.ProjectProgress{}
+	for _, project := range curriculum.Projects() {
+		projectProgress := apidata.CurriculumProjectProgress{
+			Number: project.Number,
+			Title:  project.Title,
+			Total:  len(project.Chips),
+		}
+
+		for _, chip := range project.Chips {
+			chipProgress := apidata.ChipProgress{Name: chip.Name}
+			chipId, ok := chipIds[chip.Name]
+
+			switch {
+			case !ok:
+				chipProgress.Status = apidata.ChipProgressMissing
+			case ctx.Err() != nil:
+				chipProgress.ChipID = &chipId
+				chipProgress.Status = apidata.ChipProgressUntested
+			default:
+				chipProgress = checkChip(ctx, hdls, chip)
+				chipProgress.ChipID = &chipId
+				if chipProgress.Status == apidata.ChipProgressPassed {
+					projectProgress.Passed++
+				}
+			}
+
+			projectProgress.Chips = append(projectProgress.Chips, chipProgress)
+		}
+
+		progress.Passed += projectProgress.Passed
+		progress.Total += projectProgress.Total
+		progress.Projects = append(progress.Projects, projectProgress)
+	}
+
+	return progress, nil
+}
+
+// checkChip runs the reference test of the chip, which is untested if the
+// context is done before the test ends.
+func checkChip(ctx context.Context, hdls map[string]string, chip curriculum.Chip) apidata.ChipProgress {
+	chipProgress := apidata.ChipProgress{Name: chip.Name}
+	results := grader.Grade(ctx, hdls, []grader.Test{{
+		Name:    chip.Name,
+		Chip:    chip.Name,
+		Script:  chip.Test,
+		Compare: chip.Compare,
+	}}, grader.Options{MaxSteps: maxTestScriptSteps})
+
+	result := results[0]
+	switch {
+	case result.Passed:
+		chipProgress.Status = apidata.ChipProgressPassed
+	case ctx.Err() != nil:
+		chipProgress.Status = apidata.ChipProgressUntested
+	default:
+		chipProgress.Status = apidata.ChipProgressFailed
+		chipProgress.Error = result.Error
+		chipProgress.Mismatch = result.Mismatch
+	}
+	return chipProgress
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/curriculum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckChip(t *testing.T) {
+	project, _ := curriculum.FindProject(1)
+	not := project.Chips[0]
+	hdls := map[string]string{
+		"Not": `CHIP Not {
+			IN in;
+			OUT out;
+
+			PARTS:
+			Nand(a = in, b = in, out = out);
+		}`,
+	}
+
+	t.Run("Reference test", func(t *testing.T) {
+		progress := checkChip(context.Background(), hdls, not)
+		assert.Equal(t, apidata.ChipProgress{Name: "Not", Status: apidata.ChipProgressPassed}, progress)
+	})
+
+	t.Run("Wrong chip", func(t *testing.T) {
+		progress := checkChip(context.Background(), map[string]string{
+			"Not": "CHIP Not {\n    IN in;\n    OUT out;\n\n    PARTS:\n    Or(a = in, b = in, out = out);\n}",
+		}, not)
+		assert.Equal(t, apidata.ChipProgressFailed, progress.Status)
+		assert.NotNil(t, progress.Mismatch)
+	})
+
+	t.Run("Runaway test", func(t *testing.T) {
+		runaway := not
+		runaway.Test = "load Not.hdl, repeat { }"
+
+		progress := checkChip(context.Background(), hdls, runaway)
+		assert.Equal(t, apidata.ChipProgress{
+			Name:   "Not",
+			Status: apidata.ChipProgressFailed,
+			Error:  "step limit exceeded",
+		}, progress)
+	})
+
+	t.Run("Out of time", func(t *testing.T) {
+		runaway := not
+		runaway.Test = "load Not.hdl, repeat { eval; }"
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		progress := checkChip(ctx, hdls, runaway)
+		assert.Equal(t, apidata.ChipProgress{Name: "Not", Status: apidata.ChipProgressUntested}, progress)
+	})
+}
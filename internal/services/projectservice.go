@@ -3,26 +3,57 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/bauerbrun0/nand2tetris-web/internal/apidata"
+	"github.com/bauerbrun0/nand2tetris-web/internal/crypto"
+	"github.com/bauerbrun0/nand2tetris-web/internal/curriculum"
 	"github.com/bauerbrun0/nand2tetris-web/internal/models"
+	"github.com/bauerbrun0/nand2tetris-web/internal/projectarchive"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 var (
-	ErrProjectNotFound = errors.New("projectservice: project not found")
+	ErrProjectNotFound      = errors.New("projectservice: project not found")
+	ErrProjectShareNotFound = errors.New("projectservice: project share not found")
+	// ErrCurriculumProjectNotFound is returned when a project is seeded with a
+	// project of the course that the curriculum does not have.
+	ErrCurriculumProjectNotFound = errors.New("projectservice: curriculum project not found")
 )
 
+// projectShareTokenLength is the length of the random token of a share link,
+// long enough for the link not to be guessed.
+const projectShareTokenLength = 32
+
+// ProjectVersionConflictError is returned when a project is updated with a version
+// other than its current one, that is, it has been changed since the client got it.
+type ProjectVersionConflictError struct {
+	Project *apidata.Project
+}
+
+func (e *ProjectVersionConflictError) Error() string {
+	return fmt.Sprintf("projectservice: project has been changed, its current version is %d", e.Project.Version)
+}
+
 type ProjectService interface {
 	CreateProject(name string, description string, userId int32) (*apidata.Project, error)
 	GetPoject(id int32, userId int32) (*apidata.Project, error)
 	DeleteProject(id int32, userId int32) (*apidata.Project, error)
-	UpdateProject(id int32, title *string, description *string, userId int32) (*apidata.Project, error)
+	UpdateProject(id int32, title *string, description *string, version *int32, userId int32) (*apidata.Project, error)
 	GetProjectBySlug(slug string, userId int32) (*apidata.Project, error)
 	GetPaginatedProjects(page int32, pageSize int32, userId int32) (projects []apidata.Project, totalCount int32, err error)
+	ExportProject(id int32, userId int32) (*apidata.Project, []apidata.Chip, error)
+	ImportProject(title string, description string, chips []projectarchive.Chip, userId int32) (*apidata.Project, error)
+	DuplicateProject(id int32, userId int32) (*apidata.Project, error)
+	CreateProjectShare(id int32, expires *time.Time, userId int32) (*apidata.ProjectShare, error)
+	GetProjectShares(id int32, userId int32) ([]apidata.ProjectShare, error)
+	DeleteProjectShare(shareId int32, id int32, userId int32) (*apidata.ProjectShare, error)
+	GetSharedProject(token string) (*apidata.SharedProject, error)
+	SeedProject(id int32, curriculumProject int, userId int32) ([]apidata.Chip, error)
 }
 
 type projectService struct {
@@ -74,6 +105,7 @@ func (s *projectService) CreateProject(title string, description string, userId
 		Description: project.Description.String,
 		Created:     project.Created.Time,
 		Updated:     project.Updated.Time,
+		Version:     project.Version,
 	}, nil
 }
 
@@ -98,6 +130,7 @@ func (s *projectService) GetPoject(id int32, userId int32) (*apidata.Project, er
 		Description: project.Description.String,
 		Created:     project.Created.Time,
 		Updated:     project.Updated.Time,
+		Version:     project.Version,
 	}, nil
 }
 
@@ -122,10 +155,20 @@ func (s *projectService) DeleteProject(id int32, userId int32) (*apidata.Project
 		Description: project.Description.String,
 		Created:     project.Created.Time,
 		Updated:     project.Updated.Time,
+		Version:     project.Version,
 	}, nil
 }
 
-func (s *projectService) UpdateProject(id int32, title *string, description *string, userId int32) (*apidata.Project, error) {
+// UpdateProject updates the title and the description of the project. If version
+// is not nil, the project is only updated if it is still at that version,
+// otherwise a *ProjectVersionConflictError holding the current project is returned.
+func (s *projectService) UpdateProject(
+	id int32,
+	title *string,
+	description *string,
+	version *int32,
+	userId int32,
+) (*apidata.Project, error) {
 	tx, qtx, err := s.txStarter.Begin(s.ctx)
 	if err != nil {
 		return nil, err
@@ -144,6 +187,10 @@ func (s *projectService) UpdateProject(id int32, title *string, description *str
 		return nil, err
 	}
 
+	if version != nil && *version != oldProject.Version {
+		return nil, &ProjectVersionConflictError{Project: toProject(oldProject)}
+	}
+
 	var newTitle string
 	var newDescription string
 
@@ -167,11 +214,12 @@ func (s *projectService) UpdateProject(id int32, title *string, description *str
 			String: newDescription,
 			Valid:  true,
 		},
+		Version: oldProject.Version,
 	})
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrProjectNotFound
+			return nil, s.projectVersionConflict(qtx, id, userId)
 		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -195,6 +243,7 @@ func (s *projectService) UpdateProject(id int32, title *string, description *str
 		Description: project.Description.String,
 		Created:     project.Created.Time,
 		Updated:     project.Updated.Time,
+		Version:     project.Version,
 	}, nil
 }
 
@@ -219,6 +268,7 @@ func (s *projectService) GetProjectBySlug(slug string, userId int32) (*apidata.P
 		Description: project.Description.String,
 		Created:     project.Created.Time,
 		Updated:     project.Updated.Time,
+		Version:     project.Version,
 	}, nil
 }
 
@@ -254,6 +304,7 @@ func (s *projectService) GetPaginatedProjects(page int32, pageSize int32, userId
 			Description: project.Description.String,
 			Created:     project.Created.Time,
 			Updated:     project.Updated.Time,
+			Version:     project.Version,
 		})
 	}
 
@@ -271,3 +322,406 @@ func (s *projectService) GetPaginatedProjects(page int32, pageSize int32, userId
 
 	return projects, totalCount, nil
 }
+
+// ExportProject returns the project and its chips, sorted by name.
+func (s *projectService) ExportProject(id int32, userId int32) (*apidata.Project, []apidata.Chip, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	project, err := qtx.GetProject(s.ctx, models.GetProjectParams{
+		ID:     id,
+		UserID: userId,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrProjectNotFound
+		}
+		return nil, nil, err
+	}
+
+	chipRecords, err := qtx.GetChipsByProject(s.ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chips := make([]apidata.Chip, 0, len(chipRecords))
+	for _, chip := range chipRecords {
+		chips = append(chips, *toChip(chip))
+	}
+
+	return toProject(project), chips, nil
+}
+
+// ImportProject creates a project with the chips in one transaction. The
+// project gets only these chips, not the default ones of a new project.
+func (s *projectService) ImportProject(
+	title string,
+	description string,
+	chips []projectarchive.Chip,
+	userId int32,
+) (*apidata.Project, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	// the trigger on the projects table does not create the default chips,
+	// instead of them being deleted after the project is created
+	err = qtx.SkipDefaultChips(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := qtx.CreateProject(s.ctx, models.CreateProjectParams{
+		UserID: userId,
+		Title:  title,
+		Description: pgtype.Text{
+			String: description,
+			Valid:  true,
+		},
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == models.ErrorCodeUniqueViolation {
+				return nil, models.ErrProjectTitleTaken
+			}
+		}
+		return nil, err
+	}
+
+	for _, chip := range chips {
+		_, err := qtx.CreateChipWithHdl(s.ctx, models.CreateChipWithHdlParams{
+			ProjectID: project.ID,
+			Name:      chip.Name,
+			Hdl: pgtype.Text{
+				String: chip.Hdl,
+				Valid:  true,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProject(project), nil
+}
+
+// DuplicateProject copies the project and its chips in one transaction. The
+// copy is titled after the project, e.g. "Adder (copy)", "Adder (copy 2)".
+func (s *projectService) DuplicateProject(id int32, userId int32) (*apidata.Project, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	err = qtx.SkipDefaultChips(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := qtx.DuplicateProject(s.ctx, models.DuplicateProjectParams{
+		UserID: userId,
+		ID:     id,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProjectNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == models.ErrorCodeUniqueViolation {
+				return nil, models.ErrProjectTitleTaken
+			}
+		}
+		return nil, err
+	}
+
+	err = qtx.CopyChips(s.ctx, models.CopyChipsParams{
+		ToProjectID:   project.ID,
+		FromProjectID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProject(project), nil
+}
+
+// CreateProjectShare creates a link through which anyone can view the project
+// until it expires or is deleted. It never expires if expires is nil.
+func (s *projectService) CreateProjectShare(id int32, expires *time.Time, userId int32) (*apidata.ProjectShare, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	err = s.checkProjectOwnedByUser(qtx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	params := models.CreateProjectShareParams{
+		ProjectID: id,
+		Token:     crypto.GenerateRandomString(projectShareTokenLength),
+	}
+	if expires != nil {
+		params.Expires = pgtype.Timestamptz{Time: *expires, Valid: true}
+	}
+
+	share, err := qtx.CreateProjectShare(s.ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProjectShare(share), nil
+}
+
+func (s *projectService) GetProjectShares(id int32, userId int32) ([]apidata.ProjectShare, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	err = s.checkProjectOwnedByUser(qtx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	shareRecords, err := qtx.GetProjectShares(s.ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]apidata.ProjectShare, 0, len(shareRecords))
+	for _, share := range shareRecords {
+		shares = append(shares, *toProjectShare(share))
+	}
+	return shares, nil
+}
+
+// DeleteProjectShare revokes a share link of the project.
+func (s *projectService) DeleteProjectShare(shareId int32, id int32, userId int32) (*apidata.ProjectShare, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	err = s.checkProjectOwnedByUser(qtx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := qtx.DeleteProjectShare(s.ctx, models.DeleteProjectShareParams{
+		ID:        shareId,
+		ProjectID: id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProjectShareNotFound
+		}
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProjectShare(share), nil
+}
+
+// GetSharedProject returns the project and its chips by the token of a share
+// link. ErrProjectShareNotFound is returned if there is no such link or it has expired.
+func (s *projectService) GetSharedProject(token string) (*apidata.SharedProject, error) {
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	row, err := qtx.GetSharedProject(s.ctx, token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProjectShareNotFound
+		}
+		return nil, err
+	}
+
+	chipRecords, err := qtx.GetChipsByProject(s.ctx, row.Project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chips := make([]apidata.SharedChip, 0, len(chipRecords))
+	for _, chip := range chipRecords {
+		chips = append(chips, apidata.SharedChip{
+			Name: chip.Name,
+			Hdl:  chip.Hdl.String,
+		})
+	}
+
+	return &apidata.SharedProject{
+		Title:       row.Project.Title,
+		Description: row.Project.Description.String,
+		Updated:     row.Project.Updated.Time,
+		Chips:       chips,
+	}, nil
+}
+
+// SeedProject adds the stubs of the chips of the project of the course to the
+// project, skipping the chips that the project already has, and returns the
+// added chips.
+func (s *projectService) SeedProject(id int32, curriculumProject int, userId int32) ([]apidata.Chip, error) {
+	project, ok := curriculum.FindProject(curriculumProject)
+	if !ok {
+		return nil, ErrCurriculumProjectNotFound
+	}
+
+	tx, qtx, err := s.txStarter.Begin(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(s.ctx)
+
+	err = s.checkProjectOwnedByUser(qtx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := qtx.GetChipsByProject(s.ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(existing))
+	for _, chip := range existing {
+		names[chip.Name] = true
+	}
+
+	chips := []apidata.Chip{}
+	for _, chip := range project.Chips {
+		if names[chip.Name] {
+			continue
+		}
+
+		created, err := qtx.CreateChipWithHdl(s.ctx, models.CreateChipWithHdlParams{
+			ProjectID: id,
+			Name:      chip.Name,
+			Hdl: pgtype.Text{
+				String: chip.Stub,
+				Valid:  true,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		chips = append(chips, *toChip(created))
+	}
+
+	err = tx.Commit(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return chips, nil
+}
+
+func (s *projectService) checkProjectOwnedByUser(qtx models.DBQueries, id int32, userId int32) error {
+	projectOwnedByUser, err := qtx.IsProjectOwnedByUser(s.ctx, models.IsProjectOwnedByUserParams{
+		ID:     id,
+		UserID: userId,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if !projectOwnedByUser {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+// projectVersionConflict returns the error for a project that has been changed
+// between reading and updating it in the transaction.
+func (s *projectService) projectVersionConflict(qtx models.DBQueries, id int32, userId int32) error {
+	project, err := qtx.GetProject(s.ctx, models.GetProjectParams{
+		ID:     id,
+		UserID: userId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	return &ProjectVersionConflictError{Project: toProject(project)}
+}
+
+func toProject(project models.Project) *apidata.Project {
+	return &apidata.Project{
+		ID:          project.ID,
+		UserID:      project.UserID,
+		Title:       project.Title,
+		Slug:        project.Slug,
+		Description: project.Description.String,
+		Created:     project.Created.Time,
+		Updated:     project.Updated.Time,
+		Version:     project.Version,
+	}
+}
+
+func toProjectShare(share models.ProjectShare) *apidata.ProjectShare {
+	result := &apidata.ProjectShare{
+		ID:        share.ID,
+		ProjectID: share.ProjectID,
+		Token:     share.Token,
+		Created:   share.Created.Time,
+	}
+	if share.Expires.Valid {
+		result.Expires = &share.Expires.Time
+	}
+	return result
+}
@@ -2,8 +2,10 @@ package testutils
 
 import (
 	"strings"
+	"time"
 
 	"github.com/bauerbrun0/nand2tetris-web/internal/services"
+	"github.com/bauerbrun0/nand2tetris-web/ui/pages"
 )
 
 var (
@@ -26,4 +28,13 @@ var (
 	MockPasswordHash                 = HashPassword(MockPassword)
 	MockEmailVerificationRequestCode = "123456789"
 	MockPasswordResetRequestCode     = "1234567890123"
+	MockApiTokenName                 = "upload-script"
+	MockApiTokens                    = []pages.ApiToken{
+		{
+			ID:      MockId,
+			Name:    MockApiTokenName,
+			Scope:   pages.ApiTokenScopeWrite,
+			Created: time.Now().Add(-time.Hour),
+		},
+	}
 )
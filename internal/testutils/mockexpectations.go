@@ -337,3 +337,8 @@ func ExpectGetPasswordResetRequestByCodeReturnsExpiredRequest(t *testing.T, quer
 			},
 		}, nil).Once()
 }
+
+func ExpectGetApiTokensReturnsTokens(t *testing.T, apiTokenService *servicesmocks.MockApiTokenService) {
+	apiTokenService.EXPECT().GetApiTokens(MockUserId).
+		Return(MockApiTokens, nil).Once()
+}
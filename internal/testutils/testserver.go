@@ -5,6 +5,7 @@ import (
 	"encoding/gob"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httptest"
@@ -38,7 +39,11 @@ import (
 )
 
 func NewTestApplication(
-	t *testing.T, queries models.DBQueries, githubOauthService, googleOauthService services.OAuthService, logs bool,
+	t *testing.T,
+	queries models.DBQueries,
+	githubOauthService, googleOauthService services.OAuthService,
+	apiTokenService services.ApiTokenService,
+	logs bool,
 ) *application.Application {
 	gob.Register([]pages.Toast{})
 	gob.Register(userhandlers.Action(""))
@@ -80,6 +85,7 @@ func NewTestApplication(
 		cfg.BaseUrl,
 	)
 	userService := services.NewUserService(logger, emailService, queries, txStarter, ctx)
+	chipService := services.NewChipService(logger, ctx, queries, txStarter)
 
 	return &application.Application{
 		Logger:             logger,
@@ -88,6 +94,8 @@ func NewTestApplication(
 		EmailService:       emailService,
 		GithubOauthService: githubOauthService,
 		GoogleOauthService: googleOauthService,
+		ApiTokenService:    apiTokenService,
+		ChipService:        chipService,
 		SessionManager:     sessionManager,
 		FormDecoder:        formDecoder,
 		Bundle:             bundle,
@@ -113,6 +121,7 @@ type testServer struct {
 	queries            *mocks.MockDBQueries
 	githubOauthService *servicemocks.MockOAuthService
 	googleOauthService *servicemocks.MockOAuthService
+	apiTokenService    *servicemocks.MockApiTokenService
 }
 
 type TestServerOptions struct {
@@ -120,13 +129,17 @@ type TestServerOptions struct {
 }
 
 func NewTestServer(t *testing.T, options TestServerOptions) (
-	ts *testServer, queries *mocks.MockDBQueries, githubOauthService, googleOauthService *servicemocks.MockOAuthService,
+	ts *testServer,
+	queries *mocks.MockDBQueries,
+	githubOauthService, googleOauthService *servicemocks.MockOAuthService,
+	apiTokenService *servicemocks.MockApiTokenService,
 ) {
 	githubOauthService = servicemocks.NewMockOAuthService(t)
 	googleOauthService = servicemocks.NewMockOAuthService(t)
+	apiTokenService = servicemocks.NewMockApiTokenService(t)
 	queries = modelsmocks.NewMockDBQueries(t)
 
-	app := NewTestApplication(t, queries, githubOauthService, googleOauthService, options.Logs)
+	app := NewTestApplication(t, queries, githubOauthService, googleOauthService, apiTokenService, options.Logs)
 	h := NewTestHandlers(t, app)
 	m := NewTestMiddleware(t, app)
 	routes := NewTestRoutes(t, app, h, m)
@@ -149,7 +162,8 @@ func NewTestServer(t *testing.T, options TestServerOptions) (
 		queries:            queries,
 		githubOauthService: githubOauthService,
 		googleOauthService: googleOauthService,
-	}, queries, githubOauthService, googleOauthService
+		apiTokenService:    apiTokenService,
+	}, queries, githubOauthService, googleOauthService, apiTokenService
 }
 
 type GetResult struct {
@@ -228,6 +242,42 @@ func (ts *testServer) PostForm(t *testing.T, urlPath string, form url.Values) Po
 	}
 }
 
+type SendJSONResult struct {
+	Status int
+	Header http.Header
+	Body   string
+}
+
+// SendJSON sends the request with the JSON body and the headers, like the
+// frontend calls the API.
+func (ts *testServer) SendJSON(t *testing.T, method string, urlPath string, body string, header http.Header) SendJSONResult {
+	req, err := http.NewRequest(method, ts.URL+urlPath, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maps.Copy(req.Header, header)
+	req.Header.Set("Content-Type", "application/json")
+
+	rs, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rs.Body.Close()
+
+	rsBody, err := io.ReadAll(rs.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return SendJSONResult{
+		Status: rs.StatusCode,
+		Header: rs.Header,
+		Body:   string(bytes.TrimSpace(rsBody)),
+	}
+}
+
 // Removes the cookie with the provided name from the ookie jar
 // if it exists.
 func (ts *testServer) RemoveCookie(t *testing.T, name string) {
@@ -365,6 +415,7 @@ func (ts *testServer) MustSendUserSettingsOAuthAction(t *testing.T, githubOauthS
 	form.Add("Action", string(params.Action))
 	form.Add("Verification", string(params.Verification))
 
+	ExpectGetApiTokensReturnsTokens(t, ts.apiTokenService)
 	result := ts.PostForm(t, "/user/settings", form)
 	assert.Equal(t, http.StatusSeeOther, result.Status)
 	assert.NotEmpty(t, generatedState)
@@ -0,0 +1,82 @@
+// Package textdiff computes line diffs between two texts.
+package textdiff
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxLines is the number of lines a text can have at most, as the diff takes
+// memory proportional to the product of the line counts.
+const MaxLines = 2000
+
+var ErrTooManyLines = errors.New("textdiff: too many lines")
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Line is a line of the diff. OldLine and NewLine are its 1-based line numbers
+// in the old and the new text, 0 when it is not in that text.
+type Line struct {
+	Op      Op     `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"oldLine"`
+	NewLine int    `json:"newLine"`
+}
+
+// Diff returns the lines of both texts in order, as few of them deleted and
+// inserted as possible. Deleted lines come before the lines inserted in their
+// place. It returns ErrTooManyLines if a text has more than MaxLines lines.
+func Diff(oldText string, newText string) ([]Line, error) {
+	a, b := split(oldText), split(newText)
+	if len(a) > MaxLines || len(b) > MaxLines {
+		return nil, ErrTooManyLines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]Line, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, Line{Op: OpEqual, Text: a[i], OldLine: i + 1, NewLine: j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, Line{Op: OpDelete, Text: a[i], OldLine: i + 1})
+			i++
+		default:
+			lines = append(lines, Line{Op: OpInsert, Text: b[j], NewLine: j + 1})
+			j++
+		}
+	}
+	return lines, nil
+}
+
+// split returns the lines of the text, without a last empty line after a final newline.
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,95 @@
+package textdiff
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldText  string
+		newText  string
+		expected []Line
+	}{
+		{
+			name:     "Both empty",
+			expected: []Line{},
+		},
+		{
+			name:    "Same text",
+			oldText: "a\nb\n",
+			newText: "a\r\nb",
+			expected: []Line{
+				{Op: OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: OpEqual, Text: "b", OldLine: 2, NewLine: 2},
+			},
+		},
+		{
+			name:    "From empty",
+			newText: "a\nb",
+			expected: []Line{
+				{Op: OpInsert, Text: "a", NewLine: 1},
+				{Op: OpInsert, Text: "b", NewLine: 2},
+			},
+		},
+		{
+			name:    "Changed part",
+			oldText: "CHIP And {\n\tNand(a=a, b=b, out=x);\n\tNot(in=x, out=out);\n}",
+			newText: "CHIP And {\n\tNand(a=a, b=b, out=x);\n\tNand(a=x, b=x, out=out);\n}\n// done",
+			expected: []Line{
+				{Op: OpEqual, Text: "CHIP And {", OldLine: 1, NewLine: 1},
+				{Op: OpEqual, Text: "\tNand(a=a, b=b, out=x);", OldLine: 2, NewLine: 2},
+				{Op: OpDelete, Text: "\tNot(in=x, out=out);", OldLine: 3},
+				{Op: OpInsert, Text: "\tNand(a=x, b=x, out=out);", NewLine: 3},
+				{Op: OpEqual, Text: "}", OldLine: 4, NewLine: 4},
+				{Op: OpInsert, Text: "// done", NewLine: 5},
+			},
+		},
+		{
+			name:    "Moved line",
+			oldText: "a\nb\nc",
+			newText: "b\nc\na",
+			expected: []Line{
+				{Op: OpDelete, Text: "a", OldLine: 1},
+				{Op: OpEqual, Text: "b", OldLine: 2, NewLine: 1},
+				{Op: OpEqual, Text: "c", OldLine: 3, NewLine: 2},
+				{Op: OpInsert, Text: "a", NewLine: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := Diff(tt.oldText, tt.newText)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(lines, tt.expected) {
+				t.Errorf("diff is invalid. got=%+v, want=%+v", lines, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiffTooManyLines(t *testing.T) {
+	maxText := strings.Repeat("a\n", MaxLines)
+	tooLongText := maxText + "b\n"
+
+	lines, err := Diff(maxText, maxText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != MaxLines {
+		t.Errorf("number of lines is invalid. got=%d, want=%d", len(lines), MaxLines)
+	}
+
+	for _, texts := range [][2]string{{tooLongText, "a"}, {"a", tooLongText}} {
+		_, err := Diff(texts[0], texts[1])
+		if !errors.Is(err, ErrTooManyLines) {
+			t.Errorf("error is invalid. got=%v, want=%v", err, ErrTooManyLines)
+		}
+	}
+}
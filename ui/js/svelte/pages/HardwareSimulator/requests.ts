@@ -1,5 +1,10 @@
 import type { Chip } from "../../../types/chips";
-import type { Project } from "../../../types/projects";
+import type { Project, SharedProject } from "../../../types/projects";
+import {
+  ifMatch,
+  readVersioned,
+  VersionConflictError,
+} from "../../../utils/etag";
 
 export async function fetchProjectBySlug(slug: string): Promise<Project> {
   const res = await fetch(`/api/projects/${slug}/by-slug`);
@@ -9,6 +14,19 @@ export async function fetchProjectBySlug(slug: string): Promise<Project> {
   return await res.json();
 }
 
+export async function fetchSharedProject(
+  token: string,
+): Promise<SharedProject> {
+  const res = await fetch(`/api/shared/${token}`);
+  if (!res.ok) {
+    if (res.status === 404) {
+      throw new Error("This link does not exist or has expired");
+    }
+    throw new Error("Failed to fetch shared project");
+  }
+  return await res.json();
+}
+
 export async function fetchProjectChips(id: number): Promise<Chip[]> {
   const res = await fetch(`/api/projects/${id}/chips`);
   if (!res.ok) {
@@ -44,15 +62,23 @@ export async function updateChipHdl(
   projectId: number,
   id: number,
   hdl: string,
+  version: number | undefined,
 ): Promise<Chip> {
   const res = await fetch(`/api/projects/${projectId}/chips/${id}`, {
     method: "PATCH",
     headers: {
       "Content-Type": "application/json",
+      ...ifMatch(version),
     },
     body: JSON.stringify({ hdl }),
   });
 
+  if (res.status === 409) {
+    throw new VersionConflictError(
+      "The chip has been changed elsewhere, its saved content was loaded",
+      await readVersioned<Chip>(res),
+    );
+  }
   if (!res.ok) {
     const errorData = await res.json();
     if (errorData && errorData.error && typeof errorData.error === "string") {
@@ -61,22 +87,30 @@ export async function updateChipHdl(
     throw new Error("Failed to update chip");
   }
 
-  return await res.json();
+  return await readVersioned<Chip>(res);
 }
 
 export async function updateChipName(
   projectId: number,
   id: number,
   name: string,
+  version: number | undefined,
 ): Promise<Chip> {
   const res = await fetch(`/api/projects/${projectId}/chips/${id}`, {
     method: "PATCH",
     headers: {
       "Content-Type": "application/json",
+      ...ifMatch(version),
     },
     body: JSON.stringify({ name }),
   });
 
+  if (res.status === 409) {
+    throw new VersionConflictError(
+      "The chip has been changed elsewhere, its saved content was loaded",
+      await readVersioned<Chip>(res),
+    );
+  }
   if (!res.ok) {
     const errorData = await res.json();
     if (errorData && errorData.error && typeof errorData.error === "string") {
@@ -85,7 +119,7 @@ export async function updateChipName(
     throw new Error("Failed to rename chip");
   }
 
-  return await res.json();
+  return await readVersioned<Chip>(res);
 }
 
 export async function deleteChipRequest(
@@ -1,6 +1,20 @@
 import { writable, get, type Writable } from "svelte/store";
-import type { HardwareSimulatorError, Pin, SimulationSpeed } from "./types";
+import type {
+  ChipStats,
+  Coverage,
+  HardwareSimulatorError,
+  Outline,
+  Pin,
+  Schematic,
+  SemanticToken,
+  SimulationSpeed,
+  Timing,
+} from "./types";
 import { simulationSpeeds } from "./utils/simulation";
+import { getShareToken } from "./utils/routeParam";
+
+// the project of a share link can be viewed and simulated, but not changed
+export const viewOnly = getShareToken() !== null;
 
 export const currentProjectName = writable<string>("");
 
@@ -83,6 +97,14 @@ export const inputPins = writable<Pin[]>([]);
 export const outputPins = writable<Pin[]>([]);
 export const internalPins = writable<Pin[]>([]);
 
+export const semanticTokens = writable<SemanticToken[]>([]);
+export const outline = writable<Outline | null>(null);
+export const chipStats = writable<ChipStats | null>(null);
+export const timing = writable<Timing | null>(null);
+export const schematic = writable<Schematic | null>(null);
+export const schematicDot = writable<string | null>(null);
+export const coverage = writable<Coverage | null>(null);
+
 export const cycleCount = writable<number>(1);
 export const cycleStage = writable<"tick" | "tock">("tick");
 
@@ -13,3 +13,145 @@ export type Pin = {
   name: string;
   bits: boolean[];
 };
+
+export type SourcePosition = {
+  line: number;
+  column: number;
+};
+
+export type SourceRange = {
+  start: SourcePosition;
+  end: SourcePosition; // exclusive
+};
+
+export type SemanticTokenType =
+  | "keyword"
+  | "chipName"
+  | "builtinChip"
+  | "pin"
+  | "internalSignal"
+  | "constant"
+  | "comment";
+
+export type SemanticToken = {
+  type: SemanticTokenType;
+  literal: string;
+  range: SourceRange;
+};
+
+export type OutlineIO = {
+  name: string;
+  width: number;
+  range: SourceRange;
+};
+
+export type Outline = {
+  chipName: string;
+  inputs: OutlineIO[];
+  outputs: OutlineIO[];
+  parts: { name: string; isBuiltin: boolean; range: SourceRange }[];
+  internalSignals: { name: string; width: number }[];
+};
+
+export type ChipStats = {
+  chip: string;
+  nands: number;
+  dffs: number;
+  blackBoxes: Record<string, number>;
+  uncosted: Record<string, number>;
+  instances: Record<string, number>;
+  parts: ChipStats[];
+};
+
+export type PinBit = {
+  name: string;
+  bit: number;
+};
+
+export type TimingPathStep = {
+  chip: string;
+  input: PinBit;
+  output: PinBit;
+  delay: number;
+  parts: { chip: string; line: number; column: number }[];
+};
+
+export type Timing = {
+  depths: Record<string, Record<string, number>>;
+  depth: number;
+  criticalPath: TimingPathStep[];
+  from: PinBit | null;
+  to: PinBit | null;
+};
+
+export type SchematicPort = {
+  name: string;
+  width: number;
+};
+
+export type SchematicPart = {
+  id: string;
+  chip: string;
+  builtin: boolean;
+  line: number;
+  column: number;
+  inputs: SchematicPort[];
+  outputs: SchematicPort[];
+  schematic?: Schematic;
+};
+
+export type SchematicEndpoint = {
+  part?: string;
+  pin: string;
+  from: number;
+  to: number;
+  constant?: boolean;
+};
+
+export type SchematicNet = {
+  name?: string;
+  width: number;
+  source: SchematicEndpoint;
+  target: SchematicEndpoint;
+};
+
+export type Schematic = {
+  chip: string;
+  inputs: SchematicPort[];
+  outputs: SchematicPort[];
+  parts: SchematicPart[];
+  nets: SchematicNet[];
+};
+
+export type SignalCoverage = {
+  name: string;
+  line: number;
+  rose: boolean[];
+  fell: boolean[];
+  covered: boolean;
+};
+
+export type PartCoverage = {
+  chip: string;
+  line: number;
+  column: number;
+  bits: number;
+  toggledBits: number;
+  selections: number;
+  selected: number[];
+  covered: boolean;
+};
+
+export type LineCoverage = {
+  line: number;
+  status: "covered" | "partial" | "uncovered";
+};
+
+export type Coverage = {
+  samples: number;
+  signals: SignalCoverage[];
+  parts: PartCoverage[];
+  lines: LineCoverage[];
+  bits: number;
+  toggledBits: number;
+};
@@ -10,8 +10,23 @@ import {
   simulationLoopRunning,
   advanceCycle,
   cycleStage,
+  semanticTokens,
+  outline,
+  chipStats,
+  timing,
+  schematic,
+  schematicDot,
+  coverage,
 } from "../store";
-import type { Pin } from "../types";
+import type {
+  ChipStats,
+  Coverage,
+  Outline,
+  Pin,
+  Schematic,
+  SemanticToken,
+  Timing,
+} from "../types";
 
 export async function loadHardwareSimulator() {
   window.WASM = {} as typeof window.WASM;
@@ -51,6 +66,30 @@ export async function loadHardwareSimulator() {
   window.WASM.HardwareSimulator.getCycleStage = (): "tick" | "tock" => {
     return get(cycleStage);
   };
+  window.WASM.HardwareSimulator.setSemanticTokens = (
+    tokens: SemanticToken[],
+  ) => {
+    semanticTokens.set(tokens);
+  };
+  window.WASM.HardwareSimulator.setOutline = (value: Outline | null) => {
+    outline.set(value);
+  };
+  window.WASM.HardwareSimulator.setChipStats = (value: ChipStats | null) => {
+    chipStats.set(value);
+  };
+  window.WASM.HardwareSimulator.setTiming = (value: Timing | null) => {
+    timing.set(value);
+  };
+  window.WASM.HardwareSimulator.setSchematic = (
+    value: Schematic | null,
+    dot: string | null,
+  ) => {
+    schematic.set(value);
+    schematicDot.set(dot);
+  };
+  window.WASM.HardwareSimulator.setCoverage = (value: Coverage | null) => {
+    coverage.set(value);
+  };
 
   const go = new Go();
   return WebAssembly.instantiateStreaming(
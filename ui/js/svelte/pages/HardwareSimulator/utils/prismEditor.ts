@@ -32,15 +32,44 @@ import "prism-code-editor/autocomplete-icons.css";
 import "prism-code-editor/copy-button.css";
 import "prism-code-editor/guides.css";
 
-import type { HardwareSimulatorError } from "../types";
+import { get } from "svelte/store";
+import { outline } from "../store";
+import type {
+  HardwareSimulatorError,
+  SemanticToken,
+  SemanticTokenType,
+  SourcePosition,
+} from "../types";
 
-const HDL_KEYWORDS = ["CHIP", "IN", "OUT", "PARTS:"] as const;
+const HDL_KEYWORDS = [
+  "CHIP",
+  "IN",
+  "OUT",
+  "PARTS:",
+  "BUILTIN",
+  "CLOCKED",
+] as const;
 
 const options: Completion[] = HDL_KEYWORDS.map((label) => ({
   label,
   icon: "keyword",
 }));
 
+// pinOptions completes the pins and internal signals of the chip, as the
+// simulator sees them in the outline of the edited HDL
+function pinOptions(): Completion[] {
+  const current = get(outline);
+  if (!current) {
+    return [];
+  }
+  const names = new Set([
+    ...current.inputs.map((io) => io.name),
+    ...current.outputs.map((io) => io.name),
+    ...current.internalSignals.map((signal) => signal.name),
+  ]);
+  return [...names].map((label) => ({ label, icon: "variable" }));
+}
+
 const hdlSource: CompletionSource = (context, editor) => {
   if (getClosestToken(editor, ".string, .comment", 0, 0, context.pos)) {
     return; // Disable autocomplete in comments and strings
@@ -50,7 +79,7 @@ const hdlSource: CompletionSource = (context, editor) => {
   if (wordBefore || context.explicit) {
     return {
       from: context.pos - wordBefore.length,
-      options: options,
+      options: [...options, ...pinOptions()],
     };
   }
 };
@@ -128,3 +157,109 @@ export function clearErrorHighlight(editor: PrismEditor) {
     }
   }
 }
+
+// The Prism grammar only knows the syntax of HDL, the semantic tokens of the
+// simulator also tell custom and built-in chips, pins and internal signals
+// apart. They are drawn with the CSS Custom Highlight API, on top of the
+// highlighting of Prism and without changing the DOM of the editor.
+const semanticHighlightColors: Partial<
+  Record<SemanticTokenType, { light: string; dark: string }>
+> = {
+  chipName: { light: "#267f99", dark: "#4ec9b0" },
+  builtinChip: { light: "#795e26", dark: "#dcdcaa" },
+  pin: { light: "#001080", dark: "#9cdcfe" },
+  internalSignal: { light: "#0070c1", dark: "#4fc1ff" },
+};
+
+function semanticHighlightName(type: SemanticTokenType): string {
+  return `hdl-${type}`;
+}
+
+function supportsHighlights(): boolean {
+  return typeof CSS !== "undefined" && "highlights" in CSS;
+}
+
+export function addSemanticHighlightStyle(styleTagId: string) {
+  const styleTag = document.querySelector(`#${styleTagId}`);
+  if (!styleTag) {
+    return;
+  }
+
+  styleTag.textContent = Object.entries(semanticHighlightColors)
+    .map(([type, colors]) => {
+      const name = semanticHighlightName(type as SemanticTokenType);
+      return (
+        `::highlight(${name}) { color: ${colors.light}; }\n` +
+        `.dark ::highlight(${name}) { color: ${colors.dark}; }`
+      );
+    })
+    .join("\n");
+}
+
+export function highlightSemanticTokens(
+  editor: PrismEditor,
+  tokens: SemanticToken[],
+) {
+  if (!supportsHighlights()) {
+    return;
+  }
+
+  const ranges = new Map<SemanticTokenType, Range[]>();
+  for (const token of tokens) {
+    if (!semanticHighlightColors[token.type]) {
+      continue;
+    }
+    // the tokens can be of an older version of the HDL than the editor shows
+    const start = textPosition(editor, token.range.start);
+    const end = textPosition(editor, token.range.end);
+    if (!start || !end) {
+      continue;
+    }
+
+    const range = new Range();
+    range.setStart(start.node, start.offset);
+    range.setEnd(end.node, end.offset);
+    ranges.set(token.type, [...(ranges.get(token.type) ?? []), range]);
+  }
+
+  for (const type of Object.keys(semanticHighlightColors)) {
+    const typeRanges = ranges.get(type as SemanticTokenType) ?? [];
+    CSS.highlights.set(
+      semanticHighlightName(type as SemanticTokenType),
+      new Highlight(...typeRanges),
+    );
+  }
+}
+
+export function clearSemanticHighlights() {
+  if (!supportsHighlights()) {
+    return;
+  }
+
+  for (const type of Object.keys(semanticHighlightColors)) {
+    CSS.highlights.delete(semanticHighlightName(type as SemanticTokenType));
+  }
+}
+
+// textPosition finds the text node and the offset in it of the position, whose
+// line and column start at 1.
+function textPosition(
+  editor: PrismEditor,
+  position: SourcePosition,
+): { node: Text; offset: number } | null {
+  const line = editor.lines[position.line];
+  if (!line) {
+    return null;
+  }
+
+  let offset = position.column - 1;
+  const walker = document.createTreeWalker(line, NodeFilter.SHOW_TEXT);
+  for (let node = walker.nextNode(); node; node = walker.nextNode()) {
+    const text = node as Text;
+    if (offset <= text.length) {
+      return { node: text, offset };
+    }
+    offset -= text.length;
+  }
+  return null;
+}
@@ -6,3 +6,13 @@ export function getProjectSlug(): string {
   }
   return slug;
 }
+
+export function getShareToken(): string | null {
+  if (typeof window !== "undefined") {
+    const parts = window.location.pathname.split("/");
+    if (parts[1] === "shared" && parts[2]) {
+      return parts[2]; // ['', 'shared', 'some-token']
+    }
+  }
+  return null;
+}
@@ -1,4 +1,9 @@
 import type { ProjectsResponse, Project } from "../../../types/projects";
+import {
+  ifMatch,
+  readVersioned,
+  VersionConflictError,
+} from "../../../utils/etag";
 
 export async function fetchProjects(
   page: number,
@@ -57,15 +62,23 @@ export async function editProject(
   id: number,
   title: string,
   description: string,
+  version: number,
 ): Promise<Project> {
   const res = await fetch(`/api/projects/${id}`, {
     method: "PATCH",
     headers: {
       "Content-Type": "application/json",
+      ...ifMatch(version),
     },
     body: JSON.stringify({ title, description }),
   });
 
+  if (res.status === 409) {
+    throw new VersionConflictError(
+      "The project has been changed elsewhere, its saved details were loaded",
+      await readVersioned<Project>(res),
+    );
+  }
   if (!res.ok) {
     const errorData = await res.json();
     if (errorData && errorData.error && typeof errorData.error === "string") {
@@ -74,5 +87,5 @@ export async function editProject(
     throw new Error("Failed to edit project");
   }
 
-  return await res.json();
+  return await readVersioned<Project>(res);
 }
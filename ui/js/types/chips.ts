@@ -5,4 +5,5 @@ export type Chip = {
   hdl: string;
   created: string;
   updated: string;
+  version: number;
 };
@@ -1,4 +1,12 @@
-import type { Pin } from "../svelte/pages/HardwareSimulator/types";
+import type {
+  ChipStats,
+  Coverage,
+  Outline,
+  Pin,
+  Schematic,
+  SemanticToken,
+  Timing,
+} from "../svelte/pages/HardwareSimulator/types";
 
 export {};
 
@@ -18,6 +26,12 @@ declare global {
         setSimulationLoopRunning: (running: boolean) => void;
         advanceCycle: () => void;
         getCycleStage: () => "tick" | "tock";
+        setSemanticTokens: (tokens: SemanticToken[]) => void;
+        setOutline: (outline: Outline | null) => void;
+        setChipStats: (stats: ChipStats | null) => void;
+        setTiming: (timing: Timing | null) => void;
+        setSchematic: (schematic: Schematic | null, dot: string | null) => void;
+        setCoverage: (coverage: Coverage | null) => void;
 
         // exported Go functions (called *from JS*)
         startComputing: (n: number, delayNS: number) => void;
@@ -27,6 +41,12 @@ declare global {
         tock: () => void;
         startSimulationLoop: () => void;
         stopSimulationLoop: () => void;
+        analyzeHdl: () => void;
+        computeChipStats: () => void;
+        analyzeTiming: () => void;
+        exportSchematic: (depth: number) => void;
+        getCoverage: () => void;
+        resetCoverage: () => void;
       };
     };
   }
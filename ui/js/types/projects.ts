@@ -6,6 +6,7 @@ export type Project = {
   description: string;
   created: string;
   updated: string;
+  version: number;
 };
 
 export type ProjectsResponse = {
@@ -15,3 +16,10 @@ export type ProjectsResponse = {
   pageSize: number;
   totalPages: number;
 };
+
+export type SharedProject = {
+  title: string;
+  description: string;
+  updated: string;
+  chips: { name: string; hdl: string }[];
+};
@@ -0,0 +1,33 @@
+// The API tags chips and projects with their version in the ETag header, and
+// only updates them if the If-Match header sends their current version.
+
+export function ifMatch(version: number | undefined): Record<string, string> {
+  if (version === undefined) {
+    return {};
+  }
+  return { "If-Match": `"${version}"` };
+}
+
+// readVersioned returns the JSON body of the response with the version in its
+// ETag header, which proxies can send as a weak tag.
+export async function readVersioned<T extends { version: number }>(
+  res: Response,
+): Promise<T> {
+  const data: T = await res.json();
+  const match = res.headers.get("ETag")?.match(/^(?:W\/)?"(\d+)"$/);
+  if (match) {
+    data.version = Number(match[1]);
+  }
+  return data;
+}
+
+// VersionConflictError is thrown when the resource has been changed since its
+// version was read, current is the copy the server has.
+export class VersionConflictError<T> extends Error {
+  current: T;
+
+  constructor(message: string, current: T) {
+    super(message);
+    this.current = current;
+  }
+}
@@ -29,6 +29,22 @@ type UserInfo struct {
 	LinkedAccounts []Account
 }
 
+type ApiTokenScope string
+
+const (
+	ApiTokenScopeRead  ApiTokenScope = "read"
+	ApiTokenScopeWrite ApiTokenScope = "write"
+)
+
+type ApiToken struct {
+	ID       int32
+	Name     string
+	Scope    ApiTokenScope
+	Expires  *time.Time
+	LastUsed *time.Time
+	Created  time.Time
+}
+
 type SveltePage string
 
 var (
@@ -4,14 +4,19 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"syscall/js"
 	"time"
 
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/analysis"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/chips"
+	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/semantic"
 	"github.com/bauerbrun0/nand2tetris-web/internal/hardwaresimulator/simulator"
 )
 
 var jsFuncs map[string]js.Value
 
+var builtinChips = chips.NewDefaultRegistry()
 var hardwareSimulator *simulator.HardwareSimulator
 var cancelSimulationLoop context.CancelFunc
 
@@ -25,6 +30,12 @@ func main() {
 	hardwareSimulatorJsObject.Set("tock", tockWrapper())
 	hardwareSimulatorJsObject.Set("startSimulationLoop", startSimulationLoopWrapper())
 	hardwareSimulatorJsObject.Set("stopSimulationLoop", stopSimulationLoopWrapper())
+	hardwareSimulatorJsObject.Set("analyzeHdl", analyzeHdlWrapper())
+	hardwareSimulatorJsObject.Set("computeChipStats", computeChipStatsWrapper())
+	hardwareSimulatorJsObject.Set("analyzeTiming", analyzeTimingWrapper())
+	hardwareSimulatorJsObject.Set("exportSchematic", exportSchematicWrapper())
+	hardwareSimulatorJsObject.Set("getCoverage", getCoverageWrapper())
+	hardwareSimulatorJsObject.Set("resetCoverage", resetCoverageWrapper())
 
 	// getting js functions from javascript
 	jsFuncs = make(map[string]js.Value)
@@ -33,6 +44,12 @@ func main() {
 	jsFuncs["setInputPins"] = hardwareSimulatorJsObject.Get("setInputPins")
 	jsFuncs["setOutputPins"] = hardwareSimulatorJsObject.Get("setOutputPins")
 	jsFuncs["setInternalPins"] = hardwareSimulatorJsObject.Get("setInternalPins")
+	jsFuncs["setSemanticTokens"] = hardwareSimulatorJsObject.Get("setSemanticTokens")
+	jsFuncs["setOutline"] = hardwareSimulatorJsObject.Get("setOutline")
+	jsFuncs["setChipStats"] = hardwareSimulatorJsObject.Get("setChipStats")
+	jsFuncs["setTiming"] = hardwareSimulatorJsObject.Get("setTiming")
+	jsFuncs["setSchematic"] = hardwareSimulatorJsObject.Get("setSchematic")
+	jsFuncs["setCoverage"] = hardwareSimulatorJsObject.Get("setCoverage")
 	<-make(chan struct{})
 }
 
@@ -113,7 +130,7 @@ func evaluate() {
 }
 
 func processHdls() {
-	hardwareSimulator = simulator.New()
+	hardwareSimulator = simulator.New(builtinChips)
 	hardwareSimulatorJSFuncs := js.Global().Get("WASM").Get("HardwareSimulator")
 	getHdls := hardwareSimulatorJSFuncs.Get("getHdls")
 	getCurrentHdlFileName := hardwareSimulatorJSFuncs.Get("getCurrentHdlFileName")
@@ -179,6 +196,294 @@ func processHdls() {
 
 }
 
+func analyzeHdl() {
+	hardwareSimulatorJSFuncs := js.Global().Get("WASM").Get("HardwareSimulator")
+	hdls := JSValueToMap(hardwareSimulatorJSFuncs.Get("getHdls").Invoke())
+	currentHdlFileName := hardwareSimulatorJSFuncs.Get("getCurrentHdlFileName").Invoke().String()
+
+	doc := semantic.Analyze(hdls[currentHdlFileName], hdls, builtinChips)
+
+	tokensJS := js.Global().Get("Array").New()
+	for _, tok := range doc.Tokens {
+		obj := js.Global().Get("Object").New()
+		obj.Set("type", string(tok.TokenType))
+		obj.Set("literal", tok.Literal)
+		obj.Set("range", rangeToJSValue(tok.Range))
+		tokensJS.Call("push", obj)
+	}
+	jsFuncs["setSemanticTokens"].Invoke(tokensJS)
+
+	if doc.Outline == nil {
+		jsFuncs["setOutline"].Invoke(js.Null())
+		return
+	}
+
+	outlineJS := js.Global().Get("Object").New()
+	outlineJS.Set("chipName", doc.Outline.ChipName)
+	outlineJS.Set("inputs", outlineIOsToJSValue(doc.Outline.Inputs))
+	outlineJS.Set("outputs", outlineIOsToJSValue(doc.Outline.Outputs))
+
+	partsJS := js.Global().Get("Array").New()
+	for _, part := range doc.Outline.Parts {
+		obj := js.Global().Get("Object").New()
+		obj.Set("name", part.Name)
+		obj.Set("isBuiltin", part.IsBuiltin)
+		obj.Set("range", rangeToJSValue(part.Range))
+		partsJS.Call("push", obj)
+	}
+	outlineJS.Set("parts", partsJS)
+
+	internalSignalsJS := js.Global().Get("Array").New()
+	for _, signal := range doc.Outline.InternalSignals {
+		obj := js.Global().Get("Object").New()
+		obj.Set("name", signal.Name)
+		obj.Set("width", signal.Width)
+		internalSignalsJS.Call("push", obj)
+	}
+	outlineJS.Set("internalSignals", internalSignalsJS)
+
+	jsFuncs["setOutline"].Invoke(outlineJS)
+}
+
+func outlineIOsToJSValue(ios []semantic.IO) js.Value {
+	iosJS := js.Global().Get("Array").New()
+	for _, io := range ios {
+		obj := js.Global().Get("Object").New()
+		obj.Set("name", io.Name)
+		obj.Set("width", io.Width)
+		obj.Set("range", rangeToJSValue(io.Range))
+		iosJS.Call("push", obj)
+	}
+	return iosJS
+}
+
+func rangeToJSValue(r semantic.Range) js.Value {
+	start := js.Global().Get("Object").New()
+	start.Set("line", r.Start.Line)
+	start.Set("column", r.Start.Column)
+
+	end := js.Global().Get("Object").New()
+	end.Set("line", r.End.Line)
+	end.Set("column", r.End.Column)
+
+	obj := js.Global().Get("Object").New()
+	obj.Set("start", start)
+	obj.Set("end", end)
+	return obj
+}
+
+func computeChipStats() {
+	hardwareSimulatorJSFuncs := js.Global().Get("WASM").Get("HardwareSimulator")
+	hdls := JSValueToMap(hardwareSimulatorJSFuncs.Get("getHdls").Invoke())
+	currentHdlFileName := hardwareSimulatorJSFuncs.Get("getCurrentHdlFileName").Invoke().String()
+
+	hs := simulator.New(builtinChips)
+	hs.SetChipHDLs(hdls)
+	g, err := hs.BuildGraph(currentHdlFileName)
+	if err != nil {
+		jsFuncs["setChipStats"].Invoke(js.Null())
+		return
+	}
+
+	stats := analysis.Analyze(currentHdlFileName, g, analysis.DefaultCosts())
+	jsFuncs["setChipStats"].Invoke(statsToJSValue(stats))
+}
+
+func statsToJSValue(stats analysis.Stats) js.Value {
+	obj := js.Global().Get("Object").New()
+	obj.Set("chip", stats.Chip)
+	obj.Set("nands", stats.Nands)
+	obj.Set("dffs", stats.DFFs)
+	obj.Set("blackBoxes", countsToJSValue(stats.BlackBoxes))
+	obj.Set("uncosted", countsToJSValue(stats.Uncosted))
+	obj.Set("instances", countsToJSValue(stats.Instances))
+
+	partsJS := js.Global().Get("Array").New()
+	for _, part := range stats.Parts {
+		partsJS.Call("push", statsToJSValue(part))
+	}
+	obj.Set("parts", partsJS)
+	return obj
+}
+
+func countsToJSValue(counts map[string]int) js.Value {
+	obj := js.Global().Get("Object").New()
+	for chipName, count := range counts {
+		obj.Set(chipName, count)
+	}
+	return obj
+}
+
+func analyzeTiming() {
+	hardwareSimulatorJSFuncs := js.Global().Get("WASM").Get("HardwareSimulator")
+	hdls := JSValueToMap(hardwareSimulatorJSFuncs.Get("getHdls").Invoke())
+	currentHdlFileName := hardwareSimulatorJSFuncs.Get("getCurrentHdlFileName").Invoke().String()
+
+	hs := simulator.New(builtinChips)
+	hs.SetChipHDLs(hdls)
+	timing, err := hs.AnalyzeTiming(currentHdlFileName)
+	if err != nil {
+		jsFuncs["setTiming"].Invoke(js.Null())
+		return
+	}
+
+	depthsJS := js.Global().Get("Object").New()
+	for inputName, outputs := range timing.Depths {
+		depthsJS.Set(inputName, countsToJSValue(outputs))
+	}
+
+	pathJS := js.Global().Get("Array").New()
+	for _, step := range timing.CriticalPath {
+		obj := js.Global().Get("Object").New()
+		obj.Set("chip", step.Chip)
+		obj.Set("input", pinBitToJSValue(&step.Input))
+		obj.Set("output", pinBitToJSValue(&step.Output))
+		obj.Set("delay", step.Delay)
+
+		partsJS := js.Global().Get("Array").New()
+		for _, part := range step.Parts {
+			partJS := js.Global().Get("Object").New()
+			partJS.Set("chip", part.Chip)
+			partJS.Set("line", part.Line)
+			partJS.Set("column", part.Column)
+			partsJS.Call("push", partJS)
+		}
+		obj.Set("parts", partsJS)
+		pathJS.Call("push", obj)
+	}
+
+	timingJS := js.Global().Get("Object").New()
+	timingJS.Set("depths", depthsJS)
+	timingJS.Set("depth", timing.Depth)
+	timingJS.Set("criticalPath", pathJS)
+	timingJS.Set("from", pinBitToJSValue(timing.From))
+	timingJS.Set("to", pinBitToJSValue(timing.To))
+	jsFuncs["setTiming"].Invoke(timingJS)
+}
+
+// exportSchematic passes the schematic of the current chip to the UI both as an
+// object, parsed from its JSON, and rendered to DOT.
+func exportSchematic(depth int) {
+	hardwareSimulatorJSFuncs := js.Global().Get("WASM").Get("HardwareSimulator")
+	hdls := JSValueToMap(hardwareSimulatorJSFuncs.Get("getHdls").Invoke())
+	currentHdlFileName := hardwareSimulatorJSFuncs.Get("getCurrentHdlFileName").Invoke().String()
+
+	hs := simulator.New(builtinChips)
+	hs.SetChipHDLs(hdls)
+	s, err := hs.Schematic(currentHdlFileName, depth)
+	if err != nil {
+		jsFuncs["setSchematic"].Invoke(js.Null(), js.Null())
+		return
+	}
+
+	schematicJSON, err := json.Marshal(s)
+	if err != nil {
+		jsFuncs["setSchematic"].Invoke(js.Null(), js.Null())
+		return
+	}
+	jsFuncs["setSchematic"].Invoke(js.Global().Get("JSON").Call("parse", string(schematicJSON)), s.DOT())
+}
+
+// getCoverage passes the coverage of the chip being simulated to the UI, over
+// the evaluations since it was processed or since the coverage was reset.
+func getCoverage() {
+	if hardwareSimulator == nil || hardwareSimulator.Evaluator == nil {
+		jsFuncs["setCoverage"].Invoke(js.Null())
+		return
+	}
+
+	coverageJSON, err := json.Marshal(hardwareSimulator.Coverage())
+	if err != nil {
+		jsFuncs["setCoverage"].Invoke(js.Null())
+		return
+	}
+	jsFuncs["setCoverage"].Invoke(js.Global().Get("JSON").Call("parse", string(coverageJSON)))
+}
+
+func resetCoverage() {
+	if hardwareSimulator == nil || hardwareSimulator.Evaluator == nil {
+		return
+	}
+	hardwareSimulator.ResetCoverage()
+	getCoverage()
+}
+
+func pinBitToJSValue(pinBit *analysis.PinBit) js.Value {
+	if pinBit == nil {
+		return js.Null()
+	}
+	obj := js.Global().Get("Object").New()
+	obj.Set("name", pinBit.Name)
+	obj.Set("bit", pinBit.Bit)
+	return obj
+}
+
+func analyzeTimingWrapper() js.Func {
+	analyzeTimingFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 0 {
+			return "Invalid no of arguments passed"
+		}
+		go analyzeTiming()
+		return nil
+	})
+	return analyzeTimingFunc
+}
+
+func exportSchematicWrapper() js.Func {
+	exportSchematicFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 1 {
+			return "Invalid no of arguments passed"
+		}
+		go exportSchematic(args[0].Int())
+		return nil
+	})
+	return exportSchematicFunc
+}
+
+func getCoverageWrapper() js.Func {
+	getCoverageFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 0 {
+			return "Invalid no of arguments passed"
+		}
+		go getCoverage()
+		return nil
+	})
+	return getCoverageFunc
+}
+
+func resetCoverageWrapper() js.Func {
+	resetCoverageFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 0 {
+			return "Invalid no of arguments passed"
+		}
+		go resetCoverage()
+		return nil
+	})
+	return resetCoverageFunc
+}
+
+func computeChipStatsWrapper() js.Func {
+	computeChipStatsFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 0 {
+			return "Invalid no of arguments passed"
+		}
+		go computeChipStats()
+		return nil
+	})
+	return computeChipStatsFunc
+}
+
+func analyzeHdlWrapper() js.Func {
+	analyzeHdlFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 0 {
+			return "Invalid no of arguments passed"
+		}
+		go analyzeHdl()
+		return nil
+	})
+	return analyzeHdlFunc
+}
+
 func processHdlsWrapper() js.Func {
 	processHdlsFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
 		if len(args) != 0 {